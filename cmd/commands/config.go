@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "inspects the project config",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:               "validate",
+	Short:             "validates the config without connecting to any databases",
+	Args:              cobra.NoArgs,
+	ValidArgsFunction: cobra.NoFileCompletions,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := assertConfig()
+		if !printConfigChecks(conf) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
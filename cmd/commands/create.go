@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/telemetryos/graviton/driver"
 	"github.com/telemetryos/graviton/driver/mongodb"
 	"github.com/telemetryos/graviton/migrations"
 
@@ -16,7 +17,7 @@ import (
 var createCmd = &cobra.Command{
 	Use:   "create [database] <name>",
 	Short: "creates a new migration",
-	Long:  "Creates a new migration with the specified name.",
+	Long:  "Creates a new migration with the specified name. Use --seed to create a seed instead.",
 	Args:  cobra.RangeArgs(1, 2),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -32,14 +33,28 @@ var createCmd = &cobra.Command{
 		return []string{}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		isSeed, _ := cmd.Flags().GetBool("seed")
+
 		conf := assertConfig()
 		databaseName, migrationName := resolveAndAssertDBNameAndMigration(conf, cmd, args)
 		databaseConf := conf.Database(databaseName)
 
+		scriptsPath := databaseConf.MigrationsPath
+		extension := "migration.ts"
+		template := mongodb.MigrationTemplate
+		if isSeed {
+			if databaseConf.SeedsPath == "" {
+				databaseConf.SeedsPath = "seeds"
+			}
+			scriptsPath = databaseConf.SeedsPath
+			extension = "seed.ts"
+			template = migrations.SeedTemplate
+		}
+
 		now := time.Now()
 		timestamp := now.Format("20060102150405")
-		filename := fmt.Sprintf("%s-%s.migration.ts", timestamp, migrationName)
-		migrationPath := filepath.Join(conf.ProjectPath, databaseConf.MigrationsPath, filename)
+		filename := fmt.Sprintf("%s-%s.%s", timestamp, migrationName, extension)
+		migrationPath := filepath.Join(conf.ProjectPath, scriptsPath, filename)
 
 		if _, err := os.Stat(migrationPath); err != nil {
 			if !os.IsNotExist(err) {
@@ -50,23 +65,25 @@ var createCmd = &cobra.Command{
 				panic(err)
 			}
 
-			tsConfigPath := filepath.Join(conf.ProjectPath, databaseConf.MigrationsPath, "tsconfig.json")
+			tsConfigPath := filepath.Join(conf.ProjectPath, scriptsPath, "tsconfig.json")
 			if err := os.WriteFile(tsConfigPath, migrations.TSConfigTemplate, 0644); err != nil {
 				panic(err)
 			}
 
-			typeDefPath := filepath.Join(conf.ProjectPath, databaseConf.MigrationsPath, "migration.d.ts")
-			if err := os.WriteFile(typeDefPath, mongodb.MigrationTypeDefTemplate, 0644); err != nil {
+			typeDefPath := filepath.Join(conf.ProjectPath, scriptsPath, "migration.d.ts")
+			if err := os.WriteFile(typeDefPath, driver.MigrationTypeDefTemplate(databaseConf.Kind), 0644); err != nil {
 				panic(err)
 			}
 		}
 
-		if err := os.WriteFile(migrationPath, mongodb.MigrationTemplate, 0644); err != nil {
+		if err := os.WriteFile(migrationPath, template, 0644); err != nil {
 			panic(err)
 		}
 	},
 }
 
 func init() {
+	createCmd.Flags().Bool("seed", false, "create a seed instead of a migration")
+
 	rootCmd.AddCommand(createCmd)
 }
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/diagnostics"
+	"github.com/telemetryos/graviton/driver"
+
+	"github.com/spf13/cobra"
+)
+
+const DOCTOR_TIMEOUT = 30 * time.Second
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [database]",
+	Short: "checks the config and databases for problems",
+	Long: "Validates the config, then connects to each database to check its version, " +
+		"write permissions, tracking tables, transaction support and whether it is read-only",
+	Args: cobra.MaximumNArgs(1),
+
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			conf := assertConfig()
+			return databaseNamesWithPrefix(conf, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+		}
+		return []string{}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := assertConfig()
+
+		failed := !printConfigChecks(conf)
+
+		databaseConfs := conf.Databases
+		if len(args) == 1 {
+			databaseConf := conf.Database(args[0])
+			if databaseConf == nil {
+				fmt.Println("Unknown database `" + args[0] + "`")
+				os.Exit(1)
+			}
+			databaseConfs = []*config.DatabaseConfig{databaseConf}
+		}
+
+		for _, databaseConf := range databaseConfs {
+			fmt.Println("Database `" + databaseConf.Name + "` (" + string(databaseConf.Kind) + ")")
+
+			drv, err := driver.FromDatabaseConfig(databaseConf)
+			if err != nil {
+				printChecks([]*diagnostics.Check{diagnostics.Fail("driver", err.Error(), "")})
+				failed = true
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), DOCTOR_TIMEOUT)
+			checks := drv.Diagnose(ctx)
+			cancel()
+
+			printChecks(checks)
+			if diagnostics.Failed(checks) {
+				failed = true
+			}
+		}
+
+		if failed {
+			fmt.Println("Problems were found")
+			os.Exit(1)
+		}
+		fmt.Println("No problems found")
+	},
+}
+
+// printConfigChecks validates the config and prints the result. It returns
+// false if the config has problems.
+func printConfigChecks(conf *config.Config) bool {
+	configPath, _ := config.GetFilePath(ConfigPath)
+	if conf.Environment != "" {
+		fmt.Println("Config " + configPath + " (environment `" + conf.Environment + "`)")
+	} else {
+		fmt.Println("Config " + configPath)
+	}
+
+	problems := conf.Validate()
+	if len(problems) == 0 {
+		printChecks([]*diagnostics.Check{diagnostics.Pass("config", "valid")})
+		return true
+	}
+
+	checks := []*diagnostics.Check{}
+	for _, problem := range problems {
+		checks = append(checks, diagnostics.Fail("config", problem.Error(), ""))
+	}
+	printChecks(checks)
+	return false
+}
+
+func printChecks(checks []*diagnostics.Check) {
+	for _, check := range checks {
+		status := "[" + check.Status.String() + "]"
+		fmt.Println("  " + status + strings.Repeat(" ", 7-len(status)) + check.Name + ": " + check.Message)
+		if check.Hint != "" {
+			fmt.Println("         " + check.Hint)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
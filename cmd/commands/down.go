@@ -6,7 +6,6 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/telemetryos/graviton/driver"
 	"github.com/telemetryos/graviton/migrations"
 	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
 
@@ -58,7 +57,7 @@ var downCmd = &cobra.Command{
 
 		ctx := context.Background()
 
-		drv := driver.FromDatabaseConfig(databaseConf)
+		drv := assertDriver(databaseConf)
 		if err := drv.Connect(ctx); err != nil {
 			panic(err)
 		}
@@ -103,13 +102,17 @@ var downCmd = &cobra.Command{
 		}
 		rollbackMigrationNames := []string{}
 		for _, rollbackMigration := range rollbackMigrations {
-			rollbackMigrationNames = append(rollbackMigrationNames, " --- "+rollbackMigration.Name())
+			rollbackMigrationNames = append(rollbackMigrationNames, migrationListing(" --- ", rollbackMigration))
 		}
 		fmt.Println(strings.Join(rollbackMigrationNames, "\n"))
+		warnIfNonTransactional(drv, databaseName)
+		defer assertDatabases(ctx, conf, databaseName, rollbackMigrations)()
+
+		confirmProtectedEnvironment(conf, "reverting these migrations")
 
 		for _, rollbackMigration := range rollbackMigrations {
-			err = drv.WithTransaction(ctx, func(sessCtx context.Context) error {
-				err := rollbackMigration.Script.Down()
+			err = rollbackMigration.Script.WithTransaction(ctx, func(sessCtx context.Context) error {
+				err := rollbackMigration.Script.Down(sessCtx)
 				if err != nil {
 					return err
 				}
@@ -133,6 +136,7 @@ var downCmd = &cobra.Command{
 				return nil
 			})
 			if err != nil {
+				exitOnCommitError(databaseName, rollbackMigration, err)
 				panic(err)
 			}
 		}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/driver"
+	"github.com/telemetryos/graviton/migrations"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var databaseKinds = []string{
+	string(config.DatabaseKindPostgreSQL),
+	string(config.DatabaseKindMySQL),
+	string(config.DatabaseKindSQLite),
+	string(config.DatabaseKindMongoDB),
+}
+
+var configFormats = []string{"toml", "yaml", "json"}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "creates a config for the current project",
+	Long: "Writes a Graviton config, creates the migrations directory and adds the TypeScript " +
+		"definitions for the chosen kind of database. Any values not given as flags are prompted for.",
+	Args:              cobra.NoArgs,
+	ValidArgsFunction: cobra.NoFileCompletions,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		kind, _ := cmd.Flags().GetString("kind")
+		connectionUrl, _ := cmd.Flags().GetString("connection-url")
+		databaseName, _ := cmd.Flags().GetString("database-name")
+		migrationsPath, _ := cmd.Flags().GetString("migrations-path")
+		format, _ := cmd.Flags().GetString("format")
+
+		configPath := ConfigPath
+		if format == "" && configPath != "" {
+			format = strings.TrimPrefix(filepath.Ext(configPath), ".")
+			if format == "yml" {
+				format = "yaml"
+			}
+		}
+
+		prompter := newPrompter()
+
+		if kind == "" {
+			kind = prompter.ask("Database kind ("+strings.Join(databaseKinds, ", ")+")", string(config.DatabaseKindPostgreSQL))
+		}
+		if !slices.Contains(databaseKinds, kind) {
+			fmt.Println("Unknown database kind `" + kind + "`. Expected one of: " + strings.Join(databaseKinds, ", "))
+			os.Exit(1)
+		}
+		if name == "" {
+			name = prompter.ask("Database name used in commands", "main")
+		}
+		if databaseName == "" {
+			databaseName = prompter.ask("Name of the database to migrate", "app")
+		}
+		if connectionUrl == "" {
+			connectionUrl = prompter.ask("Connection URL", defaultConnectionUrl(config.DatabaseKind(kind), databaseName))
+		}
+		if migrationsPath == "" {
+			migrationsPath = prompter.ask("Migrations path", "migrations")
+		}
+		if format == "" {
+			format = prompter.ask("Config format ("+strings.Join(configFormats, ", ")+")", "toml")
+		}
+		if !slices.Contains(configFormats, format) {
+			fmt.Println("Unknown config format `" + format + "`. Expected one of: " + strings.Join(configFormats, ", "))
+			os.Exit(1)
+		}
+
+		if configPath == "" {
+			configPath = "graviton.config." + format
+		}
+		if _, err := os.Stat(configPath); err == nil {
+			fmt.Println("A config already exists at " + configPath)
+			os.Exit(1)
+		}
+
+		conf := &config.Config{
+			Databases: []*config.DatabaseConfig{{
+				Name:           name,
+				Kind:           config.DatabaseKind(kind),
+				ConnectionUrl:  connectionUrl,
+				DatabaseName:   databaseName,
+				MigrationsPath: migrationsPath,
+			}},
+		}
+
+		configSrc, err := marshalConfig(conf, format)
+		if err != nil {
+			panic(err)
+		}
+		if err := os.WriteFile(configPath, configSrc, 0644); err != nil {
+			panic(err)
+		}
+		fmt.Println("Created " + configPath)
+
+		migrationsDir := filepath.Join(filepath.Dir(configPath), migrationsPath)
+		if err := os.MkdirAll(migrationsDir, 0755); err != nil {
+			panic(err)
+		}
+
+		scaffoldFiles := map[string][]byte{
+			"tsconfig.json":  migrations.TSConfigTemplate,
+			"migration.d.ts": driver.MigrationTypeDefTemplate(config.DatabaseKind(kind)),
+		}
+		for _, filename := range []string{"tsconfig.json", "migration.d.ts"} {
+			scaffoldPath := filepath.Join(migrationsDir, filename)
+			if _, err := os.Stat(scaffoldPath); err == nil {
+				continue
+			}
+			if err := os.WriteFile(scaffoldPath, scaffoldFiles[filename], 0644); err != nil {
+				panic(err)
+			}
+			fmt.Println("Created " + scaffoldPath)
+		}
+	},
+}
+
+func defaultConnectionUrl(kind config.DatabaseKind, databaseName string) string {
+	switch kind {
+	case config.DatabaseKindMongoDB:
+		return "mongodb://localhost:27017"
+	case config.DatabaseKindMySQL:
+		return "root@tcp(localhost:3306)/" + databaseName + "?parseTime=true"
+	case config.DatabaseKindSQLite:
+		return "file:./" + databaseName + ".db?mode=rwc"
+	default:
+		return "postgres://postgres@localhost:5432/" + databaseName + "?sslmode=disable"
+	}
+}
+
+func marshalConfig(conf *config.Config, format string) ([]byte, error) {
+	switch format {
+	case "yaml":
+		return yaml.Marshal(conf)
+	case "json":
+		configSrc, err := json.MarshalIndent(conf, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(configSrc, '\n'), nil
+	default:
+		return toml.Marshal(conf)
+	}
+}
+
+// prompter asks for values on stdin when it is a terminal. When stdin is not
+// a terminal the defaults are used so init can run unattended.
+type prompter struct {
+	reader      *bufio.Reader
+	interactive bool
+}
+
+func newPrompter() *prompter {
+	stat, err := os.Stdin.Stat()
+	return &prompter{
+		reader:      bufio.NewReader(os.Stdin),
+		interactive: err == nil && stat.Mode()&os.ModeCharDevice != 0,
+	}
+}
+
+func (p *prompter) ask(question string, defaultValue string) string {
+	if !p.interactive {
+		return defaultValue
+	}
+	fmt.Print(question + " [" + defaultValue + "]: ")
+	answer, _ := p.reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue
+	}
+	return answer
+}
+
+func init() {
+	initCmd.Flags().String("name", "", "name used to refer to the database in commands")
+	initCmd.Flags().String("kind", "", "kind of database: "+strings.Join(databaseKinds, ", "))
+	initCmd.Flags().String("connection-url", "", "connection URL for the database")
+	initCmd.Flags().String("database-name", "", "name of the database to migrate")
+	initCmd.Flags().String("migrations-path", "", "path to the migrations directory")
+	initCmd.Flags().String("format", "", "config file format: "+strings.Join(configFormats, ", "))
+
+	initCmd.RegisterFlagCompletionFunc("kind", cobra.FixedCompletions(databaseKinds, cobra.ShellCompDirectiveNoFileComp))
+	initCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(configFormats, cobra.ShellCompDirectiveNoFileComp))
+
+	rootCmd.AddCommand(initCmd)
+}
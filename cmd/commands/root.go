@@ -1,9 +1,12 @@
 package commands
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/telemetryos/graviton/assets"
@@ -15,6 +18,9 @@ import (
 )
 
 var TargetDatabaseNamesStr string
+var ConfigPath string
+var EnvironmentName string
+var AssumeYes bool
 
 var rootCmd = &cobra.Command{
 	Use:   "graviton",
@@ -31,19 +37,76 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+func init() {
+	rootCmd.PersistentFlags().StringVar(&ConfigPath, "config", "", "path to the config file (defaults to $"+config.CONFIG_VAR+" or searching the project)")
+	rootCmd.PersistentFlags().StringVar(&EnvironmentName, "env", "", "environment to use from the config (defaults to $"+config.ENVIRONMENT_VAR+")")
+	rootCmd.PersistentFlags().BoolVarP(&AssumeYes, "yes", "y", false, "skip confirmation prompts for protected environments")
+
+	rootCmd.RegisterFlagCompletionFunc("env", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		conf := assertConfig()
+		environmentNames := []string{}
+		for _, environmentName := range conf.Environments {
+			if strings.HasPrefix(environmentName, toComplete) {
+				environmentNames = append(environmentNames, environmentName)
+			}
+		}
+		return environmentNames, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
 func assertConfig() *config.Config {
-	conf, err := config.Load()
+	conf, err := config.Load(config.LoadOptions{Path: ConfigPath, Environment: EnvironmentName})
 	if err != nil {
-		panic(err)
+		fmt.Println("Failed to load configuration: " + err.Error())
+		os.Exit(1)
 	}
 	if conf == nil {
-		fmt.Println("No configuration found. Create a graviton.toml in the root of your project.")
+		fmt.Println("No configuration found. Run `graviton init` or create a " + config.CONFIG_NAME + " in the root of your project.")
 		os.Exit(0)
 	}
 
 	return conf
 }
 
+// assertDriver returns the driver for the database, exiting if its kind is
+// not supported.
+func assertDriver(databaseConf *config.DatabaseConfig) driver.Driver {
+	drv, err := driver.FromDatabaseConfig(databaseConf)
+	if err != nil {
+		fmt.Println("Failed to load driver: " + err.Error())
+		os.Exit(1)
+	}
+	return drv
+}
+
+// warnIfNonTransactional prints a warning when the database runs migrations
+// without transactions.
+func warnIfNonTransactional(drv driver.Driver, databaseName string) {
+	if driver.TransactionsEnabled(drv) {
+		return
+	}
+	fmt.Println("WARN: Transactions are disabled for database `" + databaseName + "`. " +
+		"A migration that fails part way through is left partially applied and must be cleaned up by hand.")
+}
+
+// confirmProtectedEnvironment asks the user to type the environment name
+// before a destructive action runs against a protected environment. It exits
+// if the user does not confirm.
+func confirmProtectedEnvironment(conf *config.Config, action string) {
+	if !conf.Protected || AssumeYes {
+		return
+	}
+
+	fmt.Println("Environment `" + conf.Environment + "` is protected.")
+	fmt.Print("Type the environment name to confirm " + action + ": ")
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.TrimSpace(answer) != conf.Environment {
+		fmt.Println("Aborted")
+		os.Exit(1)
+	}
+}
+
 func databaseNamesWithPrefix(conf *config.Config, prefix string) []string {
 	databaseNames := []string{}
 	for _, database := range conf.Databases {
@@ -56,7 +119,10 @@ func databaseNamesWithPrefix(conf *config.Config, prefix string) []string {
 
 func pendingMigrationNamesWithPrefix(conf *config.Config, databaseName string, prefix string) []string {
 	databaseConf := conf.Database(databaseName)
-	drv := driver.FromDatabaseConfig(databaseConf)
+	drv, err := driver.FromDatabaseConfig(databaseConf)
+	if err != nil {
+		return []string{}
+	}
 	ctx := context.Background()
 	if err := drv.Connect(ctx); err != nil {
 		return []string{}
@@ -78,7 +144,10 @@ func pendingMigrationNamesWithPrefix(conf *config.Config, databaseName string, p
 
 func appliedMigrationNamesWithPrefix(conf *config.Config, databaseName string, prefix string) []string {
 	databaseConf := conf.Database(databaseName)
-	drv := driver.FromDatabaseConfig(databaseConf)
+	drv, err := driver.FromDatabaseConfig(databaseConf)
+	if err != nil {
+		return []string{}
+	}
 	ctx := context.Background()
 	if err := drv.Connect(ctx); err != nil {
 		return []string{}
@@ -100,7 +169,10 @@ func appliedMigrationNamesWithPrefix(conf *config.Config, databaseName string, p
 
 func appliedMigrationNamesFromDiskWithPrefix(conf *config.Config, databaseName string, prefix string) []string {
 	databaseConf := conf.Database(databaseName)
-	drv := driver.FromDatabaseConfig(databaseConf)
+	drv, err := driver.FromDatabaseConfig(databaseConf)
+	if err != nil {
+		return []string{}
+	}
 	ctx := context.Background()
 	if err := drv.Connect(ctx); err != nil {
 		return []string{}
@@ -160,3 +232,92 @@ func resolveAndAssertDBNameAndMigration(conf *config.Config, cmd *cobra.Command,
 	}
 	return databaseName, migrationName
 }
+
+// assertDatabases connects to the other databases the migrations use, as they
+// declare with `export const databases`, and gives the migrations their
+// drivers. Each database is connected once however many migrations use it.
+// It exits if a migration uses a database that is not configured, and returns
+// a function that disconnects the databases.
+func assertDatabases(ctx context.Context, conf *config.Config, databaseName string, migrationList []*migrations.Migration) func() {
+	drivers := map[string]driver.Driver{}
+	disconnect := func() {
+		for _, drv := range drivers {
+			drv.Disconnect(ctx)
+		}
+	}
+
+	for _, migration := range migrationList {
+		var databases []*migrations.Database
+		for _, name := range migration.Script.Databases() {
+			if name == databaseName {
+				fmt.Println("Migration `" + migration.Name() + "` lists its own database `" + name + "` in databases")
+				disconnect()
+				os.Exit(1)
+			}
+			drv, ok := drivers[name]
+			if !ok {
+				databaseConf := conf.Database(name)
+				if databaseConf == nil {
+					fmt.Println("Migration `" + migration.Name() + "` uses database `" + name + "`, which is not configured")
+					disconnect()
+					os.Exit(1)
+				}
+				drv = assertDriver(databaseConf)
+				if err := drv.Connect(ctx); err != nil {
+					fmt.Println("Failed to connect to database `" + name + "`: " + err.Error())
+					disconnect()
+					os.Exit(1)
+				}
+				drivers[name] = drv
+				warnIfNonTransactional(drv, name)
+			}
+			databases = append(databases, &migrations.Database{Name: name, Driver: drv})
+		}
+		if len(databases) == 0 {
+			continue
+		}
+		migration.Script.UseDatabases(ctx, databases)
+	}
+
+	return disconnect
+}
+
+// migrationListing returns how a migration is listed before it runs, with the
+// other databases it uses.
+func migrationListing(prefix string, migration *migrations.Migration) string {
+	databases := migration.Script.Databases()
+	if len(databases) == 0 {
+		return prefix + migration.Name()
+	}
+	return prefix + migration.Name() + " (with " + strings.Join(databases, ", ") + ")"
+}
+
+// exitOnCommitError reports a migration that only some of its databases
+// committed, listing which kept its changes and which rolled them back, and
+// exits. Other errors are left to the caller.
+func exitOnCommitError(databaseName string, migration *migrations.Migration, err error) {
+	var commitErr *migrations.CommitError
+	if !errors.As(err, &commitErr) {
+		return
+	}
+
+	failed := commitErr.Database
+	if failed == "" {
+		failed = databaseName
+	}
+	var rolledBack []string
+	for _, name := range append([]string{databaseName}, migration.Script.Databases()...) {
+		if name != failed && !slices.Contains(commitErr.Committed, name) {
+			rolledBack = append(rolledBack, name)
+		}
+	}
+
+	fmt.Println("Migration `" + migration.Name() + "` was only partly committed")
+	fmt.Println("  Committed: " + strings.Join(commitErr.Committed, ", "))
+	fmt.Println("  Failed to commit: " + failed + ": " + commitErr.Err.Error())
+	if len(rolledBack) > 0 {
+		fmt.Println("  Rolled back: " + strings.Join(rolledBack, ", "))
+	}
+	fmt.Println("The migration is not recorded as run. Revert its changes to " + strings.Join(commitErr.Committed, ", ") + " by hand before running it again.")
+	os.Exit(1)
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/driver"
+	"github.com/telemetryos/graviton/migrations"
+	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
+
+	"github.com/spf13/cobra"
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed [database] [seed]",
+	Short: "runs seeds",
+	Long: "Will run all seeds that have not been run yet in order. If a seed is specified, " +
+		"only that seed will be run. Seeds restricted to other environments are skipped.",
+	Args: cobra.MaximumNArgs(2),
+
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		conf := assertConfig()
+		singularDatabase := conf.GetSingularDatabase()
+		switch len(args) {
+		case 0:
+			if singularDatabase != "" {
+				seedNames := seedNamesWithPrefix(conf, singularDatabase, toComplete)
+				return seedNames, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+			}
+			databaseNames := databaseNamesWithPrefix(conf, toComplete)
+			return databaseNames, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+		case 1:
+			if singularDatabase == "" {
+				seedNames := seedNamesWithPrefix(conf, args[0], toComplete)
+				return seedNames, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+			}
+		}
+		return []string{}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+
+		conf := assertConfig()
+		databaseName, seedName := resolveAndAssertDBNameAndMigration(conf, cmd, args)
+		databaseConf := conf.Database(databaseName)
+
+		ctx := context.Background()
+
+		drv := assertDriver(databaseConf)
+		if err := drv.Connect(ctx); err != nil {
+			panic(err)
+		}
+		defer drv.Disconnect(ctx)
+
+		seeds, err := migrations.GetSeeds(ctx, conf.ProjectPath, databaseConf, drv)
+		if err != nil {
+			if err, ok := err.(*migrations.BuildScriptError); ok {
+				err.Print()
+				return
+			}
+			panic(err)
+		}
+
+		var runSeeds []*migrations.Migration
+		if seedName != "" {
+			for _, seed := range seeds {
+				if seed.Name() == seedName {
+					runSeeds = append(runSeeds, seed)
+					break
+				}
+			}
+			if len(runSeeds) == 0 {
+				fmt.Println("target seed not found")
+				return
+			}
+			if !seedAllowedInEnvironment(conf, runSeeds[0]) {
+				fmt.Println("Seed `" + seedName + "` cannot run in " + describeEnvironment(conf) +
+					". It is restricted to: " + strings.Join(runSeeds[0].Script.Environments(), ", "))
+				return
+			}
+			if !runSeeds[0].AppliedAt.IsZero() && !force {
+				fmt.Println("Seed `" + seedName + "` has already been run. Use --force to run it again.")
+				return
+			}
+		} else {
+			for _, seed := range seeds {
+				if !seed.AppliedAt.IsZero() && !force {
+					continue
+				}
+				if !seedAllowedInEnvironment(conf, seed) {
+					fmt.Println("Skipping seed `" + seed.Name() + "` as it is restricted to: " +
+						strings.Join(seed.Script.Environments(), ", "))
+					continue
+				}
+				runSeeds = append(runSeeds, seed)
+			}
+		}
+
+		if len(runSeeds) == 0 {
+			fmt.Println("No pending seeds")
+			return
+		}
+
+		fmt.Println("Running seeds for database `" + databaseName + "` in " + describeEnvironment(conf))
+		runSeedNames := []string{}
+		for _, runSeed := range runSeeds {
+			runSeedNames = append(runSeedNames, migrationListing(" +++ ", runSeed))
+		}
+		fmt.Println(strings.Join(runSeedNames, "\n"))
+		warnIfNonTransactional(drv, databaseName)
+
+		confirmProtectedEnvironment(conf, "running these seeds")
+
+		defer assertDatabases(ctx, conf, databaseName, runSeeds)()
+
+		for _, runSeed := range runSeeds {
+			err = runSeed.Script.WithTransaction(ctx, func(sessCtx context.Context) error {
+				if err := runSeed.Script.Seed(sessCtx); err != nil {
+					return err
+				}
+
+				runSeed.AppliedAt = time.Now()
+				runSeed.NonAtomic = !driver.TransactionsEnabled(drv)
+
+				previouslyApplied, err := drv.GetAppliedSeedsMetadata(sessCtx)
+				if err != nil {
+					return err
+				}
+
+				allAppliedSeeds := []*migrationsmeta.MigrationMetadata{}
+				for _, m := range previouslyApplied {
+					if m.Filename != runSeed.Filename {
+						allAppliedSeeds = append(allAppliedSeeds, m)
+					}
+				}
+				allAppliedSeeds = append(allAppliedSeeds, runSeed.MigrationMetadata)
+
+				if err := drv.SetAppliedSeedsMetadata(sessCtx, allAppliedSeeds); err != nil {
+					return err
+				}
+
+				return nil
+			})
+			if err != nil {
+				exitOnCommitError(databaseName, runSeed, err)
+				panic(err)
+			}
+		}
+
+		fmt.Println("Ran seeds for database `" + databaseName + "`")
+	},
+}
+
+// DEFAULT_SEED_ENVIRONMENT is the environment seeds are matched against when
+// none is selected, as the top-level databases are usually those of local
+// development.
+const DEFAULT_SEED_ENVIRONMENT = "development"
+
+func seedAllowedInEnvironment(conf *config.Config, seed *migrations.Migration) bool {
+	environments := seed.Script.Environments()
+	if len(environments) == 0 {
+		return true
+	}
+	environment := conf.Environment
+	if environment == "" {
+		environment = DEFAULT_SEED_ENVIRONMENT
+	}
+	return slices.Contains(environments, environment)
+}
+
+func describeEnvironment(conf *config.Config) string {
+	if conf.Environment == "" {
+		return "the default environment"
+	}
+	return "environment `" + conf.Environment + "`"
+}
+
+func seedNamesWithPrefix(conf *config.Config, databaseName string, prefix string) []string {
+	databaseConf := conf.Database(databaseName)
+	if databaseConf == nil {
+		return []string{}
+	}
+	drv, err := driver.FromDatabaseConfig(databaseConf)
+	if err != nil {
+		return []string{}
+	}
+	ctx := context.Background()
+	if err := drv.Connect(ctx); err != nil {
+		return []string{}
+	}
+	defer drv.Disconnect(ctx)
+
+	seeds, err := migrations.GetSeeds(ctx, conf.ProjectPath, databaseConf, drv)
+	if err != nil {
+		return []string{}
+	}
+	seedNames := []string{}
+	for _, seed := range seeds {
+		if strings.HasPrefix(seed.Name(), prefix) {
+			seedNames = append(seedNames, seed.Name())
+		}
+	}
+	return seedNames
+}
+
+func init() {
+	seedCmd.Flags().Bool("force", false, "run seeds again even if they have already been run")
+
+	rootCmd.AddCommand(seedCmd)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/telemetryos/graviton/driver"
 	"github.com/telemetryos/graviton/migrations"
 	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
 
@@ -44,13 +43,14 @@ var setHeadCmd = &cobra.Command{
 
 		ctx := context.Background()
 
-		drv := driver.FromDatabaseConfig(databaseConf)
+		drv := assertDriver(databaseConf)
 		if err := drv.Connect(ctx); err != nil {
 			panic(err)
 		}
 		defer drv.Disconnect(ctx)
 
 		if migrationName == "-" {
+			confirmProtectedEnvironment(conf, "marking all migrations for database `"+databaseName+"` as unapplied")
 			if err := drv.SetAppliedMigrationsMetadata(ctx, []*migrationsmeta.MigrationMetadata{}); err != nil {
 				panic(err)
 			}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/telemetryos/graviton/driver"
 	"github.com/telemetryos/graviton/migrations"
 
 	"github.com/spf13/cobra"
@@ -38,7 +37,7 @@ var statusCmd = &cobra.Command{
 
 		fmt.Println("Migration status for database `" + databaseName + "`")
 
-		drv := driver.FromDatabaseConfig(databaseConf)
+		drv := assertDriver(databaseConf)
 		if err := drv.Connect(ctx); err != nil {
 			panic(err)
 		}
@@ -54,7 +53,18 @@ var statusCmd = &cobra.Command{
 		}
 		pendingMigrationNames := []string{}
 		for _, pendingMigration := range pendingMigrations {
-			pendingMigrationNames = append(pendingMigrationNames, "   - "+pendingMigration.Name())
+			checkpoint, backfilling, err := drv.GetBackfillCheckpoint(ctx, pendingMigration.Filename)
+			if err != nil {
+				panic(err)
+			}
+			switch {
+			case backfilling && checkpoint == "":
+				pendingMigrationNames = append(pendingMigrationNames, "   - "+pendingMigration.Name()+" (backfill not started)")
+			case backfilling:
+				pendingMigrationNames = append(pendingMigrationNames, "   - "+pendingMigration.Name()+" (backfill in progress after "+checkpoint+")")
+			default:
+				pendingMigrationNames = append(pendingMigrationNames, "   - "+pendingMigration.Name())
+			}
 		}
 
 		appliedMigrations, err := migrations.GetApplied(ctx, drv)
@@ -63,7 +73,11 @@ var statusCmd = &cobra.Command{
 		}
 		appliedMigrationNames := []string{}
 		for _, appliedMigration := range appliedMigrations {
-			appliedMigrationNames = append(appliedMigrationNames, "   - "+appliedMigration.Name())
+			if appliedMigration.NonAtomic {
+				appliedMigrationNames = append(appliedMigrationNames, "   - "+appliedMigration.Name()+" (applied without a transaction)")
+			} else {
+				appliedMigrationNames = append(appliedMigrationNames, "   - "+appliedMigration.Name())
+			}
 		}
 
 		fmt.Println("  Applied migrations:")
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/telemetryos/graviton/schematypes"
+
+	"github.com/spf13/cobra"
+)
+
+var typesCmd = &cobra.Command{
+	Use:   "types [database]",
+	Short: "generates TypeScript types from the database schema",
+	Long: "Introspects the database and writes " + schematypes.FILENAME + " next to migration.d.ts, " +
+		"with a type for the rows of each table or the documents of each collection",
+	Args: cobra.MaximumNArgs(1),
+
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			conf := assertConfig()
+			singularDatabase := conf.GetSingularDatabase()
+			if singularDatabase == "" {
+				databaseNames := databaseNamesWithPrefix(conf, toComplete)
+				return databaseNames, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+			}
+		}
+		return []string{}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := assertConfig()
+		databaseName := resolveAndAssertDBName(conf, cmd, args)
+		databaseConf := conf.Database(databaseName)
+		if databaseConf == nil {
+			fmt.Println("Unknown database `" + databaseName + "`")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+
+		fmt.Println("Generating types for database `" + databaseName + "`")
+
+		drv := assertDriver(databaseConf)
+		if err := drv.Connect(ctx); err != nil {
+			panic(err)
+		}
+		defer drv.Disconnect(ctx)
+
+		schema, err := drv.SchemaTypes(ctx)
+		if err != nil {
+			fmt.Println("Failed to introspect database `" + databaseName + "`: " + err.Error())
+			os.Exit(1)
+		}
+		typeDefs := schematypes.Render(databaseName, schema)
+
+		// Seeds get the types too when their directory has its own
+		// migration.d.ts, as `graviton create --seed` gives it.
+		typeDefDirs := []string{filepath.Join(conf.ProjectPath, databaseConf.MigrationsPath)}
+		if databaseConf.SeedsPath != "" {
+			seedsDir := filepath.Join(conf.ProjectPath, databaseConf.SeedsPath)
+			if _, err := os.Stat(filepath.Join(seedsDir, "migration.d.ts")); err == nil {
+				typeDefDirs = append(typeDefDirs, seedsDir)
+			}
+		}
+
+		for _, typeDefDir := range typeDefDirs {
+			if err := os.MkdirAll(typeDefDir, 0755); err != nil {
+				panic(err)
+			}
+			typeDefPath := filepath.Join(typeDefDir, schematypes.FILENAME)
+			if err := os.WriteFile(typeDefPath, typeDefs, 0644); err != nil {
+				panic(err)
+			}
+			fmt.Println(" +++ " + typeDefPath)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(typesCmd)
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -46,7 +47,7 @@ var upCmd = &cobra.Command{
 
 		ctx := context.Background()
 
-		drv := driver.FromDatabaseConfig(databaseConf)
+		drv := assertDriver(databaseConf)
 		if err := drv.Connect(ctx); err != nil {
 			panic(err)
 		}
@@ -89,33 +90,43 @@ var upCmd = &cobra.Command{
 		}
 		applyMigrationNames := []string{}
 		for _, applyMigration := range applyMigrations {
-			applyMigrationNames = append(applyMigrationNames, " +++ "+applyMigration.Name())
+			applyMigrationNames = append(applyMigrationNames, migrationListing(" +++ ", applyMigration))
 		}
 		fmt.Println(strings.Join(applyMigrationNames, "\n"))
+		warnIfNonTransactional(drv, databaseName)
+		defer assertDatabases(ctx, conf, databaseName, applyMigrations)()
 
 		for _, applyMigration := range applyMigrations {
-			err = drv.WithTransaction(ctx, func(sessCtx context.Context) error {
-				if err := applyMigration.Script.Up(); err != nil {
-					return err
-				}
+			checkpoint, backfilling, err := drv.GetBackfillCheckpoint(ctx, applyMigration.Filename)
+			if err != nil {
+				panic(err)
+			}
+
+			if !backfilling {
+				err = applyMigration.Script.WithTransaction(ctx, func(sessCtx context.Context) error {
+					if err := applyMigration.Script.Up(sessCtx); err != nil {
+						return err
+					}
 
-				applyMigration.AppliedAt = time.Now()
+					// A migration with a backfill is recorded as applied once the
+					// backfill finishes. Until then its checkpoint marks up as done.
+					if applyMigration.Script.HasBackfill() {
+						return drv.SetBackfillCheckpoint(sessCtx, applyMigration.Filename, "")
+					}
 
-				previouslyApplied, err := drv.GetAppliedMigrationsMetadata(sessCtx)
+					return recordApplied(sessCtx, drv, applyMigration)
+				})
 				if err != nil {
-					return err
+					exitOnCommitError(databaseName, applyMigration, err)
+					panic(err)
 				}
+			}
 
-				allAppliedMigrations := append(previouslyApplied, applyMigration.MigrationMetadata)
-
-				if err := drv.SetAppliedMigrationsMetadata(sessCtx, allAppliedMigrations); err != nil {
-					return err
+			if applyMigration.Script.HasBackfill() {
+				if err := backfill(ctx, drv, applyMigration, checkpoint); err != nil {
+					exitOnCommitError(databaseName, applyMigration, err)
+					panic(err)
 				}
-
-				return nil
-			})
-			if err != nil {
-				panic(err)
 			}
 		}
 
@@ -128,6 +139,66 @@ var upCmd = &cobra.Command{
 	},
 }
 
+func recordApplied(ctx context.Context, drv driver.Driver, migration *migrations.Migration) error {
+	migration.AppliedAt = time.Now()
+	migration.NonAtomic = !driver.TransactionsEnabled(drv)
+
+	previouslyApplied, err := drv.GetAppliedMigrationsMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	allAppliedMigrations := append(previouslyApplied, migration.MigrationMetadata)
+
+	return drv.SetAppliedMigrationsMetadata(ctx, allAppliedMigrations)
+}
+
+// backfill runs the migration's backfill from checkpoint until it is done.
+// Each batch commits in its own transaction together with its checkpoint, so
+// an interrupted backfill resumes after the last committed batch. The final
+// batch records the migration as applied.
+func backfill(ctx context.Context, drv driver.Driver, migration *migrations.Migration, checkpoint string) error {
+	options := migration.Script.BackfillOptions()
+
+	if checkpoint == "" {
+		fmt.Println(" ... backfilling " + migration.Name())
+	} else {
+		fmt.Println(" ... resuming backfill of " + migration.Name() + " after " + checkpoint)
+	}
+
+	for batchNumber := 1; ; batchNumber += 1 {
+		var next string
+		var done bool
+		err := migration.Script.WithTransaction(ctx, func(sessCtx context.Context) error {
+			var err error
+			next, done, err = migration.Script.BackfillBatch(sessCtx, checkpoint, options.BatchSize)
+			if err != nil {
+				return err
+			}
+
+			if done {
+				if err := drv.DeleteBackfillCheckpoint(sessCtx, migration.Filename); err != nil {
+					return err
+				}
+				return recordApplied(sessCtx, drv, migration)
+			}
+
+			return drv.SetBackfillCheckpoint(sessCtx, migration.Filename, next)
+		})
+		if err != nil {
+			return fmt.Errorf("backfill of %s failed in batch %d: %w", migration.Name(), batchNumber, err)
+		}
+
+		if done {
+			fmt.Println(" ... backfilled " + migration.Name() + " in " + strconv.Itoa(batchNumber) + " batches")
+			return nil
+		}
+
+		checkpoint = next
+		time.Sleep(options.Throttle)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(upCmd)
 }
@@ -1,16 +1,36 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
 const CONFIG_NAME = "graviton.config.toml"
 
+// CONFIG_NAMES lists the config filenames Graviton searches for, in order of
+// preference.
+var CONFIG_NAMES = []string{
+	CONFIG_NAME,
+	"graviton.config.yaml",
+	"graviton.config.yml",
+	"graviton.config.json",
+}
+
+// CONFIG_VAR names the environment variable that can point Graviton at a
+// specific config file.
+const CONFIG_VAR = "GRAVITON_CONFIG"
+
+// ENVIRONMENT_VAR names the environment variable used to select the
+// environment Graviton is running against.
+const ENVIRONMENT_VAR = "GRAVITON_ENV"
+
 type DatabaseKind string
 
 const (
@@ -21,21 +41,49 @@ const (
 )
 
 type Config struct {
-	ProjectPath string
-	Databases   []*DatabaseConfig `toml:"databases"`
+	ProjectPath  string            `toml:"-" yaml:"-" json:"-"`
+	Environment  string            `toml:"-" yaml:"-" json:"-"`
+	Environments []string          `toml:"-" yaml:"-" json:"-"`
+	Protected    bool              `toml:"-" yaml:"-" json:"-"`
+	Databases    []*DatabaseConfig `toml:"databases" yaml:"databases" json:"databases"`
 }
 
 type DatabaseConfig struct {
-	Name           string       `toml:"name"`
-	Kind           DatabaseKind `toml:"kind"`
-	ConnectionUrl  string       `toml:"connection_url"`
-	DatabaseName   string       `toml:"database_name"`
-	MigrationsPath string       `toml:"migrations_path"`
+	Name                string       `toml:"name" yaml:"name" json:"name"`
+	Kind                DatabaseKind `toml:"kind" yaml:"kind" json:"kind"`
+	ConnectionUrl       string       `toml:"connection_url" yaml:"connection_url" json:"connection_url"`
+	DatabaseName        string       `toml:"database_name,omitempty" yaml:"database_name,omitempty" json:"database_name,omitempty"`
+	MigrationsPath      string       `toml:"migrations_path" yaml:"migrations_path" json:"migrations_path"`
+	SeedsPath           string       `toml:"seeds_path,omitempty" yaml:"seeds_path,omitempty" json:"seeds_path,omitempty"`
+	Transactions        *bool        `toml:"transactions,omitempty" yaml:"transactions,omitempty" json:"transactions,omitempty"`
+	StrictSQLValidation bool         `toml:"strict_sql_validation,omitempty" yaml:"strict_sql_validation,omitempty" json:"strict_sql_validation,omitempty"`
+}
+
+// TransactionsEnabled reports whether migrations run inside transactions.
+// Transactions are on unless the config sets `transactions = false`, which is
+// only supported for MongoDB.
+func (d *DatabaseConfig) TransactionsEnabled() bool {
+	return d.Transactions == nil || *d.Transactions
 }
 
-// GetFilePath returns the path to Graviton's config within the current project
-// if one exists.
-func GetFilePath() (string, error) {
+// GetFilePath returns the path to Graviton's config if one exists. An
+// explicit path takes precedence, followed by the GRAVITON_CONFIG environment
+// variable. Otherwise the current directory and its parents are searched.
+func GetFilePath(explicitPath string) (string, error) {
+	if explicitPath == "" {
+		explicitPath = os.Getenv(CONFIG_VAR)
+	}
+	if explicitPath != "" {
+		absPath, err := filepath.Abs(explicitPath)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return "", fmt.Errorf("config file %s: %w", explicitPath, err)
+		}
+		return absPath, nil
+	}
+
 	dir, err := os.Getwd()
 	if err != nil {
 		return "", err
@@ -44,9 +92,11 @@ func GetFilePath() (string, error) {
 	chunks := strings.Split(dir, string(filepath.Separator))
 	for i := len(chunks); i != -1; i -= 1 {
 		curPath := strings.Join(chunks[:i], string(filepath.Separator))
-		targetPath := filepath.Join(curPath, CONFIG_NAME)
-		if _, err := os.Stat(targetPath); err == nil {
-			return targetPath, nil
+		for _, configName := range CONFIG_NAMES {
+			targetPath := filepath.Join(curPath, configName)
+			if _, err := os.Stat(targetPath); err == nil {
+				return targetPath, nil
+			}
 		}
 	}
 
@@ -55,16 +105,26 @@ func GetFilePath() (string, error) {
 
 // Exists returns true if the config exists on disk
 func Exists() bool {
-	configPath, err := GetFilePath()
+	configPath, err := GetFilePath("")
 	if err != nil {
 		return false
 	}
 	return configPath != ""
 }
 
+type LoadOptions struct {
+	// Path points at a specific config file. When empty the GRAVITON_CONFIG
+	// environment variable is used, falling back to searching the project.
+	Path string
+
+	// Environment selects which environment overlay to apply. When empty the
+	// GRAVITON_ENV environment variable is used instead.
+	Environment string
+}
+
 // Load loads the config from the current project if one exists.
-func Load() (*Config, error) {
-	configPath, err := GetFilePath()
+func Load(opts LoadOptions) (*Config, error) {
+	configPath, err := GetFilePath(opts.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -83,26 +143,75 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
-	// FIXME: THIS SUCKS, we need fallbacks
-	// template in environment variables
-	envVars := os.Environ()
-	for _, envVar := range envVars {
-		parts := strings.SplitN(envVar, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		configSrc = []byte(strings.ReplaceAll(string(configSrc), "${"+parts[0]+"}", parts[1]))
+	rawConfig, err := parseRawConfig(configPath, configSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := opts.Environment
+	if environment == "" {
+		environment = os.Getenv(ENVIRONMENT_VAR)
+	}
+
+	environmentNames, protected, err := applyEnvironment(rawConfig, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	projectPath := filepath.Dir(configPath)
+
+	dotEnvVars, err := loadDotEnv(projectPath, environment)
+	if err != nil {
+		return nil, err
+	}
+	configInterpolator := &interpolator{
+		projectPath: projectPath,
+		lookup: func(name string) (string, bool) {
+			if value, ok := os.LookupEnv(name); ok {
+				return value, true
+			}
+			value, ok := dotEnvVars[name]
+			return value, ok
+		},
+	}
+	if _, err := configInterpolator.interpolateValue(rawConfig, ""); err != nil {
+		return nil, err
 	}
-	configSrcReader := strings.NewReader(string(configSrc))
 
-	var config Config
-	if err := toml.NewDecoder(configSrcReader).Decode(&config); err != nil {
+	config, err := decodeRawConfig(rawConfig)
+	if err != nil {
 		return nil, err
 	}
 
-	config.ProjectPath = filepath.Dir(configPath)
+	config.ProjectPath = projectPath
+	config.Environment = environment
+	config.Environments = environmentNames
+	config.Protected = protected
+
+	return config, nil
+}
+
+// parseRawConfig parses the config source into a map based on the file
+// extension. TOML is assumed for unknown extensions.
+func parseRawConfig(configPath string, configSrc []byte) (map[string]any, error) {
+	rawConfig := map[string]any{}
+
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(configSrc, &rawConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(configSrc, &rawConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+	default:
+		if err := toml.Unmarshal(configSrc, &rawConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+	}
 
-	return &config, nil
+	return rawConfig, nil
 }
 
 func (c *Config) GetSingularDatabase() string {
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Load_ConfigFormats(t *testing.T) {
+	sources := map[string]string{
+		"graviton.config.toml": `
+[[databases]]
+name = "main"
+kind = "sqlite"
+connection_url = "file:./app.db"
+migrations_path = "migrations"
+`,
+		"graviton.config.yaml": `
+databases:
+  - name: main
+    kind: sqlite
+    connection_url: file:./app.db
+    migrations_path: migrations
+`,
+		"graviton.config.json": `{
+  "databases": [
+    {"name": "main", "kind": "sqlite", "connection_url": "file:./app.db", "migrations_path": "migrations"}
+  ]
+}`,
+	}
+
+	for configName, src := range sources {
+		t.Run(configName, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, configName)
+			if err := os.WriteFile(configPath, []byte(src), 0644); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			t.Setenv(ENVIRONMENT_VAR, "")
+			t.Setenv(CONFIG_VAR, configPath)
+
+			conf, err := Load(LoadOptions{})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if conf.ProjectPath != dir {
+				t.Errorf("ProjectPath = %q, want %q", conf.ProjectPath, dir)
+			}
+			database := conf.Database("main")
+			if database == nil {
+				t.Fatalf("Database(main) = nil")
+			}
+			if database.Kind != DatabaseKindSQLite || database.ConnectionUrl != "file:./app.db" || database.MigrationsPath != "migrations" {
+				t.Errorf("Database(main) = %+v, want sqlite database", database)
+			}
+		})
+	}
+}
+
+func Test_GetFilePath_ExplicitPathMissing(t *testing.T) {
+	t.Setenv(CONFIG_VAR, "")
+
+	if _, err := GetFilePath(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Errorf("GetFilePath() error = nil, want error for missing explicit config")
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	projectPath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(projectPath, "migrations"), 0755); err != nil {
+		t.Fatalf("Failed to create migrations directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, "seeds"), []byte{}, 0644); err != nil {
+		t.Fatalf("Failed to write seeds file: %v", err)
+	}
+
+	transactions := false
+	conf := &Config{
+		ProjectPath: projectPath,
+		Databases: []*DatabaseConfig{
+			{Name: "main", Kind: DatabaseKindPostgreSQL, ConnectionUrl: "postgres://localhost/app", MigrationsPath: "migrations"},
+			{Name: "main", Kind: "postgres", ConnectionUrl: "postgres://localhost/app", MigrationsPath: "migrations"},
+			{Name: "events", Kind: DatabaseKindMongoDB, ConnectionUrl: "mongodb://localhost", MigrationsPath: "events-migrations", StrictSQLValidation: true},
+			{Name: "cache", Kind: DatabaseKindSQLite, MigrationsPath: "migrations", SeedsPath: "seeds", Transactions: &transactions},
+		},
+	}
+
+	expected := []string{
+		"databases[main]: name is used by more than one database",
+		"databases[main]: unknown kind `postgres`, expected one of mongodb, postgresql, mysql or sqlite",
+		"databases[events]: database_name is required for mongodb",
+		"databases[events]: strict_sql_validation only applies to sql databases",
+		"databases[events]: migrations_path `events-migrations` does not exist",
+		"databases[cache]: connection_url is required",
+		"databases[cache]: transactions can only be disabled for mongodb",
+		"databases[cache]: seeds_path `seeds` is not a directory",
+	}
+
+	problems := conf.Validate()
+	if len(problems) != len(expected) {
+		t.Fatalf("Validate() returned %d problems, want %d: %v", len(problems), len(expected), problems)
+	}
+	for index, problem := range problems {
+		if problem.Error() != expected[index] {
+			t.Errorf("Validate()[%d] = %q, want %q", index, problem.Error(), expected[index])
+		}
+	}
+
+	conf.Databases = conf.Databases[:1]
+	if problems := conf.Validate(); len(problems) != 0 {
+		t.Errorf("Validate() = %v, want no problems for a valid config", problems)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadDotEnv reads `.env` and, when an environment is selected,
+// `.env.<environment>` from the project directory. Values from the
+// environment specific file take precedence. Missing files are ignored.
+func loadDotEnv(projectPath string, environment string) (map[string]string, error) {
+	filenames := []string{".env"}
+	if environment != "" {
+		filenames = append(filenames, ".env."+environment)
+	}
+
+	vars := map[string]string{}
+	for _, filename := range filenames {
+		if err := parseDotEnvFile(filepath.Join(projectPath, filename), vars); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+func parseDotEnvFile(path string, vars map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf("%s:%d: expected NAME=value", path, lineNumber)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		vars[name] = value
+	}
+	return scanner.Err()
+}
+
+func parseDotEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		unquoted := value[1:end]
+		if quote == '"' {
+			unquoted = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(unquoted)
+		}
+		return unquoted, nil
+	default:
+		if comment := strings.Index(value, " #"); comment != -1 {
+			value = value[:comment]
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// applyEnvironment applies the overlay for the named environment onto the raw
+// config and strips the environments table from it. It returns the names of
+// all defined environments and whether the selected environment is
+// protected.
+//
+// An environment overlay looks like this:
+//
+//	[environments.production]
+//	protected = true
+//
+//	[[environments.production.databases]]
+//	name = "main"
+//	connection_url = "${PRODUCTION_DB_URL}"
+//
+// Overlay databases are matched to base databases by name. Any field set in
+// the overlay replaces the base field, overlay databases without a matching
+// base database are added, and databases with `disabled = true` are removed.
+func applyEnvironment(rawConfig map[string]any, environment string) ([]string, bool, error) {
+	rawEnvironments := map[string]any{}
+	if rawEnvironmentsVal, ok := rawConfig["environments"]; ok {
+		rawEnvironments, ok = rawEnvironmentsVal.(map[string]any)
+		if !ok {
+			return nil, false, fmt.Errorf("config: environments must be a table")
+		}
+	}
+	delete(rawConfig, "environments")
+
+	environmentNames := []string{}
+	for name := range rawEnvironments {
+		environmentNames = append(environmentNames, name)
+	}
+	sort.Strings(environmentNames)
+
+	rawDatabases, err := rawTables(rawConfig["databases"], "databases")
+	if err != nil {
+		return nil, false, err
+	}
+
+	protected := false
+	if environment != "" {
+		rawEnvironmentVal, ok := rawEnvironments[environment]
+		if !ok {
+			return nil, false, fmt.Errorf("config: environment `%s` is not defined", environment)
+		}
+		rawEnvironment, ok := rawEnvironmentVal.(map[string]any)
+		if !ok {
+			return nil, false, fmt.Errorf("config: environment `%s` must be a table", environment)
+		}
+
+		if protectedVal, ok := rawEnvironment["protected"]; ok {
+			protected, ok = protectedVal.(bool)
+			if !ok {
+				return nil, false, fmt.Errorf("config: environments.%s.protected must be a boolean", environment)
+			}
+		}
+
+		overlayDatabases, err := rawTables(rawEnvironment["databases"], "environments."+environment+".databases")
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, overlayDatabase := range overlayDatabases {
+			overlayName, _ := overlayDatabase["name"].(string)
+			if overlayName == "" {
+				return nil, false, fmt.Errorf("config: databases in environment `%s` must have a name", environment)
+			}
+
+			merged := false
+			for _, rawDatabase := range rawDatabases {
+				if name, _ := rawDatabase["name"].(string); name == overlayName {
+					mergeTables(rawDatabase, overlayDatabase)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				rawDatabases = append(rawDatabases, overlayDatabase)
+			}
+		}
+	}
+
+	enabledDatabases := []any{}
+	for _, rawDatabase := range rawDatabases {
+		if disabled, _ := rawDatabase["disabled"].(bool); disabled {
+			continue
+		}
+		delete(rawDatabase, "disabled")
+		enabledDatabases = append(enabledDatabases, rawDatabase)
+	}
+	rawConfig["databases"] = enabledDatabases
+
+	return environmentNames, protected, nil
+}
+
+// decodeRawConfig converts a raw config map into a Config.
+func decodeRawConfig(rawConfig map[string]any) (*Config, error) {
+	configSrc, err := toml.Marshal(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := toml.Unmarshal(configSrc, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func rawTables(val any, path string) ([]map[string]any, error) {
+	if val == nil {
+		return nil, nil
+	}
+	vals, ok := val.([]any)
+	if !ok {
+		return nil, fmt.Errorf("config: %s must be an array of tables", path)
+	}
+	tables := []map[string]any{}
+	for _, val := range vals {
+		table, ok := val.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("config: %s must be an array of tables", path)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+func mergeTables(dst map[string]any, src map[string]any) {
+	for key, srcVal := range src {
+		srcTable, srcIsTable := srcVal.(map[string]any)
+		dstTable, dstIsTable := dst[key].(map[string]any)
+		if srcIsTable && dstIsTable {
+			mergeTables(dstTable, srcTable)
+			continue
+		}
+		dst[key] = srcVal
+	}
+}
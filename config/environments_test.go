@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testEnvironmentsConfig = `
+[[databases]]
+name = "main"
+kind = "postgresql"
+connection_url = "postgres://localhost/dev"
+database_name = "dev"
+migrations_path = "migrations"
+
+[[databases]]
+name = "analytics"
+kind = "mongodb"
+connection_url = "mongodb://localhost:27017"
+database_name = "analytics"
+disabled = true
+
+[environments.staging]
+
+[[environments.staging.databases]]
+name = "analytics"
+disabled = false
+
+[environments.production]
+protected = true
+
+[[environments.production.databases]]
+name = "main"
+connection_url = "postgres://prod/main"
+
+[[environments.production.databases]]
+name = "reporting"
+kind = "mysql"
+connection_url = "user:pass@tcp(reporting:3306)/reporting"
+`
+
+func loadTestConfig(t *testing.T, src string, environment string) (*Config, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, CONFIG_NAME), []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	t.Setenv(ENVIRONMENT_VAR, "")
+
+	return Load(LoadOptions{Environment: environment})
+}
+
+func Test_Load_NoEnvironment(t *testing.T) {
+	conf, err := loadTestConfig(t, testEnvironmentsConfig, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(conf.Databases) != 1 {
+		t.Fatalf("Load() returned %d databases, want 1 (disabled databases should be removed)", len(conf.Databases))
+	}
+	if conf.Databases[0].ConnectionUrl != "postgres://localhost/dev" {
+		t.Errorf("ConnectionUrl = %s, want postgres://localhost/dev", conf.Databases[0].ConnectionUrl)
+	}
+	if conf.Protected {
+		t.Error("Protected = true, want false")
+	}
+	if len(conf.Environments) != 2 || conf.Environments[0] != "production" || conf.Environments[1] != "staging" {
+		t.Errorf("Environments = %v, want [production staging]", conf.Environments)
+	}
+}
+
+func Test_Load_EnvironmentOverridesAndAddsDatabases(t *testing.T) {
+	conf, err := loadTestConfig(t, testEnvironmentsConfig, "production")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !conf.Protected {
+		t.Error("Protected = false, want true")
+	}
+	if conf.Environment != "production" {
+		t.Errorf("Environment = %s, want production", conf.Environment)
+	}
+
+	mainDatabase := conf.Database("main")
+	if mainDatabase == nil {
+		t.Fatal("Database(main) = nil")
+	}
+	if mainDatabase.ConnectionUrl != "postgres://prod/main" {
+		t.Errorf("main.ConnectionUrl = %s, want postgres://prod/main", mainDatabase.ConnectionUrl)
+	}
+	if mainDatabase.DatabaseName != "dev" || mainDatabase.MigrationsPath != "migrations" {
+		t.Errorf("main did not keep fields that were not overridden: %+v", mainDatabase)
+	}
+
+	if reporting := conf.Database("reporting"); reporting == nil || reporting.Kind != DatabaseKindMySQL {
+		t.Errorf("Database(reporting) = %+v, want mysql database added by environment", reporting)
+	}
+	if conf.Database("analytics") != nil {
+		t.Error("Database(analytics) should remain disabled")
+	}
+}
+
+func Test_Load_EnvironmentEnablesDatabase(t *testing.T) {
+	conf, err := loadTestConfig(t, testEnvironmentsConfig, "staging")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	analytics := conf.Database("analytics")
+	if analytics == nil {
+		t.Fatal("Database(analytics) = nil, want database enabled by environment")
+	}
+	if analytics.DatabaseName != "analytics" {
+		t.Errorf("analytics.DatabaseName = %s, want analytics", analytics.DatabaseName)
+	}
+}
+
+func Test_Load_UnknownEnvironment(t *testing.T) {
+	if _, err := loadTestConfig(t, testEnvironmentsConfig, "qa"); err == nil {
+		t.Error("Load() error = nil, want error for undefined environment")
+	}
+}
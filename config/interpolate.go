@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// interpolator resolves variable references within config values. The
+// following forms are supported:
+//
+//	${VAR}             value of VAR, failing if it is not set
+//	${VAR:-default}    value of VAR, or default if VAR is unset or empty
+//	${VAR-default}     value of VAR, or default if VAR is unset
+//	${VAR:?message}    value of VAR, failing with message if VAR is unset or empty
+//	${VAR?message}     value of VAR, failing with message if VAR is unset
+//	${file:path}       contents of the file at path, relative to the project
+//	$$                 a literal $
+//
+// Defaults and messages may contain references themselves.
+type interpolator struct {
+	projectPath string
+	lookup      func(name string) (string, bool)
+}
+
+func (i *interpolator) interpolateValue(val any, path string) (any, error) {
+	switch val := val.(type) {
+	case string:
+		interpolated, err := i.interpolateString(val)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+		return interpolated, nil
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			interpolated, err := i.interpolateValue(val[key], joinConfigPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			val[key] = interpolated
+		}
+		return val, nil
+	case []any:
+		for index, item := range val {
+			itemPath := fmt.Sprintf("%s[%d]", path, index)
+			if table, ok := item.(map[string]any); ok {
+				if name, ok := table["name"].(string); ok && name != "" {
+					itemPath = fmt.Sprintf("%s[%s]", path, name)
+				}
+			}
+			interpolated, err := i.interpolateValue(item, itemPath)
+			if err != nil {
+				return nil, err
+			}
+			val[index] = interpolated
+		}
+		return val, nil
+	default:
+		return val, nil
+	}
+}
+
+func (i *interpolator) interpolateString(src string) (string, error) {
+	var out strings.Builder
+	for index := 0; index < len(src); index += 1 {
+		char := src[index]
+		if char != '$' || index+1 == len(src) {
+			out.WriteByte(char)
+			continue
+		}
+
+		switch src[index+1] {
+		case '$':
+			out.WriteByte('$')
+			index += 1
+		case '{':
+			end := findReferenceEnd(src, index+2)
+			if end == -1 {
+				return "", fmt.Errorf("unterminated reference `%s`", src[index:])
+			}
+			resolved, err := i.resolveReference(src[index+2 : end])
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(resolved)
+			index = end
+		default:
+			out.WriteByte(char)
+		}
+	}
+	return out.String(), nil
+}
+
+func (i *interpolator) resolveReference(reference string) (string, error) {
+	if filePath, ok := strings.CutPrefix(reference, "file:"); ok {
+		filePath, err := i.interpolateString(filePath)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(i.projectPath, filePath)
+		}
+		contents, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read `${file:%s}`: %w", filePath, err)
+		}
+		return strings.TrimRight(string(contents), "\r\n"), nil
+	}
+
+	nameEnd := 0
+	for nameEnd < len(reference) && isVariableNameChar(reference[nameEnd]) {
+		nameEnd += 1
+	}
+	name := reference[:nameEnd]
+	if name == "" {
+		return "", fmt.Errorf("invalid reference `${%s}`", reference)
+	}
+
+	value, isSet := i.lookup(name)
+	operator := reference[nameEnd:]
+
+	switch {
+	case operator == "":
+		if !isSet {
+			return "", fmt.Errorf("environment variable `%s` is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(operator, ":-"), strings.HasPrefix(operator, "-"):
+		useDefault := !isSet
+		if strings.HasPrefix(operator, ":") {
+			useDefault = !isSet || value == ""
+		}
+		if !useDefault {
+			return value, nil
+		}
+		return i.interpolateString(strings.TrimPrefix(strings.TrimPrefix(operator, ":"), "-"))
+	case strings.HasPrefix(operator, ":?"), strings.HasPrefix(operator, "?"):
+		missing := !isSet
+		if strings.HasPrefix(operator, ":") {
+			missing = !isSet || value == ""
+		}
+		if !missing {
+			return value, nil
+		}
+		message, err := i.interpolateString(strings.TrimPrefix(strings.TrimPrefix(operator, ":"), "?"))
+		if err != nil {
+			return "", err
+		}
+		if message == "" {
+			message = "is required"
+		}
+		return "", fmt.Errorf("environment variable `%s` %s", name, message)
+	default:
+		return "", fmt.Errorf("invalid reference `${%s}`", reference)
+	}
+}
+
+// findReferenceEnd returns the index of the brace closing a reference that
+// starts at start, accounting for nested references.
+func findReferenceEnd(src string, start int) int {
+	depth := 1
+	for index := start; index < len(src); index += 1 {
+		switch {
+		case src[index] == '$' && index+1 < len(src) && src[index+1] == '{':
+			depth += 1
+			index += 1
+		case src[index] == '}':
+			depth -= 1
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+func isVariableNameChar(char byte) bool {
+	return char == '_' ||
+		(char >= 'a' && char <= 'z') ||
+		(char >= 'A' && char <= 'Z') ||
+		(char >= '0' && char <= '9')
+}
+
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
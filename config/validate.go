@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Validate checks the loaded config for problems that would otherwise only
+// surface part way through a command. All problems found are returned.
+func (c *Config) Validate() []error {
+	var problems []error
+
+	if len(c.Databases) == 0 {
+		problems = append(problems, fmt.Errorf("no databases are configured"))
+	}
+
+	seenNames := map[string]bool{}
+	for index, database := range c.Databases {
+		label := fmt.Sprintf("databases[%d]", index)
+		if database.Name == "" {
+			problems = append(problems, fmt.Errorf("%s: name is required", label))
+		} else {
+			label = "databases[" + database.Name + "]"
+			if seenNames[database.Name] {
+				problems = append(problems, fmt.Errorf("%s: name is used by more than one database", label))
+			}
+			seenNames[database.Name] = true
+		}
+
+		switch database.Kind {
+		case DatabaseKindMongoDB, DatabaseKindPostgreSQL, DatabaseKindMySQL, DatabaseKindSQLite:
+		case "":
+			problems = append(problems, fmt.Errorf("%s: kind is required", label))
+		default:
+			problems = append(problems, fmt.Errorf(
+				"%s: unknown kind `%s`, expected one of mongodb, postgresql, mysql or sqlite",
+				label, database.Kind,
+			))
+		}
+
+		if database.ConnectionUrl == "" {
+			problems = append(problems, fmt.Errorf("%s: connection_url is required", label))
+		}
+		if database.Kind == DatabaseKindMongoDB && database.DatabaseName == "" {
+			problems = append(problems, fmt.Errorf("%s: database_name is required for mongodb", label))
+		}
+
+		if !database.TransactionsEnabled() && database.Kind != DatabaseKindMongoDB {
+			problems = append(problems, fmt.Errorf("%s: transactions can only be disabled for mongodb", label))
+		}
+		if database.StrictSQLValidation && database.Kind == DatabaseKindMongoDB {
+			problems = append(problems, fmt.Errorf("%s: strict_sql_validation only applies to sql databases", label))
+		}
+
+		migrationsPath := database.MigrationsPath
+		if migrationsPath == "" {
+			migrationsPath = "migrations"
+		}
+		if err := c.validateDirectory(migrationsPath); err != nil {
+			problems = append(problems, fmt.Errorf("%s: migrations_path %w", label, err))
+		}
+		if database.SeedsPath != "" {
+			if err := c.validateDirectory(database.SeedsPath); err != nil {
+				problems = append(problems, fmt.Errorf("%s: seeds_path %w", label, err))
+			}
+		}
+	}
+
+	return problems
+}
+
+func (c *Config) validateDirectory(path string) error {
+	stat, err := os.Stat(filepath.Join(c.ProjectPath, path))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("`%s` does not exist", path)
+	}
+	if err != nil {
+		return fmt.Errorf("`%s` cannot be read: %w", path, err)
+	}
+	if !stat.IsDir() {
+		return fmt.Errorf("`%s` is not a directory", path)
+	}
+	return nil
+}
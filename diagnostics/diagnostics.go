@@ -0,0 +1,52 @@
+package diagnostics
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	StatusPass Status = iota
+	StatusWarn
+	StatusFail
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPass:
+		return "ok"
+	case StatusWarn:
+		return "warn"
+	default:
+		return "fail"
+	}
+}
+
+// Check is the result of one preflight check. Hint explains how to resolve a
+// warning or failure and is empty for passing checks.
+type Check struct {
+	Name    string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+func Pass(name string, message string) *Check {
+	return &Check{Name: name, Status: StatusPass, Message: message}
+}
+
+func Warn(name string, message string, hint string) *Check {
+	return &Check{Name: name, Status: StatusWarn, Message: message, Hint: hint}
+}
+
+func Fail(name string, message string, hint string) *Check {
+	return &Check{Name: name, Status: StatusFail, Message: message, Hint: hint}
+}
+
+// Failed returns true if any of the checks failed.
+func Failed(checks []*Check) bool {
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
@@ -3,14 +3,15 @@ package driver
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/diagnostics"
 	"github.com/telemetryos/graviton/driver/mongodb"
 	"github.com/telemetryos/graviton/driver/mysql"
 	"github.com/telemetryos/graviton/driver/postgresql"
 	"github.com/telemetryos/graviton/driver/sqlite"
 	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
+	"github.com/telemetryos/graviton/schematypes"
 
 	"github.com/dop251/goja"
 )
@@ -20,26 +21,89 @@ type Driver interface {
 	Disconnect(ctx context.Context) error
 	GetAppliedMigrationsMetadata(ctx context.Context) ([]*migrationsmeta.MigrationMetadata, error)
 	SetAppliedMigrationsMetadata(ctx context.Context, migrationsMetadata []*migrationsmeta.MigrationMetadata) error
+	GetAppliedSeedsMetadata(ctx context.Context) ([]*migrationsmeta.MigrationMetadata, error)
+	SetAppliedSeedsMetadata(ctx context.Context, seedsMetadata []*migrationsmeta.MigrationMetadata) error
+	GetBackfillCheckpoint(ctx context.Context, filename string) (checkpoint string, found bool, err error)
+	SetBackfillCheckpoint(ctx context.Context, filename string, checkpoint string) error
+	DeleteBackfillCheckpoint(ctx context.Context, filename string) error
 	WithTransaction(ctx context.Context, fn func(context.Context) error) error
 	Handle(ctx context.Context) any
 	Init(ctx context.Context, runtime *goja.Runtime)
 	Globals(ctx context.Context, runtime *goja.Runtime) map[string]any
 	MaybeFromJSValue(ctx context.Context, runtime *goja.Runtime, value goja.Value) (any, bool)
+	MaybeIntoJSValue(ctx context.Context, runtime *goja.Runtime, value any, intoJs func(any) goja.Value) (goja.Value, bool)
+
+	// Diagnose runs preflight checks against the database using its own
+	// connection. It does not require Connect and does not create the
+	// tracking tables.
+	Diagnose(ctx context.Context) []*diagnostics.Check
+
+	// SchemaTypes describes the tables or collections of the connected
+	// database as TypeScript types, for `graviton types`.
+	SchemaTypes(ctx context.Context) (*schematypes.Schema, error)
 }
 
-func FromDatabaseConfig(conf *config.DatabaseConfig) Driver {
-	switch conf.Kind {
+// DocumentDriver is implemented by drivers where the order of keys in a
+// document is significant. Plain JavaScript objects are passed to NewDocument
+// instead of being converted into a map, which would lose the order.
+type DocumentDriver interface {
+	NewDocument(keys []string, values []any) any
+}
+
+// NonTransactionalDriver is implemented by drivers that can be configured to
+// run migrations without transactions.
+type NonTransactionalDriver interface {
+	TransactionsEnabled() bool
+}
+
+// TransactionsEnabled reports whether migrations run by the driver are atomic.
+func TransactionsEnabled(d Driver) bool {
+	if nonTransactionalDriver, ok := d.(NonTransactionalDriver); ok {
+		return nonTransactionalDriver.TransactionsEnabled()
+	}
+	return true
+}
+
+// SQLValidatingDriver is implemented by SQL drivers. Statements written with
+// the sql tag are checked with it when a migration is compiled, so a typo is
+// found before the migration changes anything.
+type SQLValidatingDriver interface {
+	// ValidateSQL returns an error when the database cannot parse the
+	// statement. Other problems, such as a missing table, are not reported.
+	ValidateSQL(ctx context.Context, statement string) error
+	StrictSQLValidation() bool
+	Placeholder(index int) string
+	QuoteIdentifier(names ...string) string
+}
+
+// MigrationTypeDefTemplate returns the TypeScript definitions for migrations
+// written against the given kind of database.
+func MigrationTypeDefTemplate(kind config.DatabaseKind) []byte {
+	switch kind {
 	case config.DatabaseKindMongoDB:
-		return mongodb.New(conf)
+		return mongodb.MigrationTypeDefTemplate
 	case config.DatabaseKindPostgreSQL:
-		return postgresql.New(conf)
+		return postgresql.MigrationTypeDefTemplate
 	case config.DatabaseKindMySQL:
-		return mysql.New(conf)
+		return mysql.MigrationTypeDefTemplate
 	case config.DatabaseKindSQLite:
-		return sqlite.New(conf)
+		return sqlite.MigrationTypeDefTemplate
 	default:
-		fmt.Println("Unknown database kind: " + string(conf.Kind))
-		os.Exit(1)
 		return nil
 	}
 }
+
+func FromDatabaseConfig(conf *config.DatabaseConfig) (Driver, error) {
+	switch conf.Kind {
+	case config.DatabaseKindMongoDB:
+		return mongodb.New(conf), nil
+	case config.DatabaseKindPostgreSQL:
+		return postgresql.New(conf), nil
+	case config.DatabaseKindMySQL:
+		return mysql.New(conf), nil
+	case config.DatabaseKindSQLite:
+		return sqlite.New(conf), nil
+	default:
+		return nil, fmt.Errorf("unknown database kind `%s` for database `%s`", conf.Kind, conf.Name)
+	}
+}
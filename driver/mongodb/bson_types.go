@@ -0,0 +1,314 @@
+package mongodb
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/telemetryos/graviton/migrations/js"
+
+	"github.com/dop251/goja"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Long is a 64-bit integer read from a document. BSON int64 values decode to
+// Long rather than int64 so they can be handed to migrations as Long objects,
+// keeping their type when written back, while int64 values returned by the
+// driver API, such as counts, remain plain numbers.
+type Long int64
+
+// maxSafeInteger is the largest integer a JavaScript number can represent
+// exactly.
+const maxSafeInteger = 1<<53 - 1
+
+func newRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	registry.RegisterTypeMapEntry(bsontype.Int64, reflect.TypeOf(Long(0)))
+	return registry
+}
+
+func JSDecimal128Ctor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object {
+	if len(call.Arguments) == 0 {
+		panic(jsvm.NewTypeError("Decimal128 requires a value"))
+	}
+	decimal, err := primitive.ParseDecimal128(call.Arguments[0].String())
+	if err != nil {
+		panic(jsvm.NewTypeError(err.Error()))
+	}
+
+	toString := func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(decimal.String())
+	}
+	call.This.Set("toString", toString)
+	call.This.Set("toJSON", toString)
+
+	return nil
+}
+
+func JSLongCtor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object {
+	var value int64
+	if len(call.Arguments) > 0 {
+		var err error
+		value, err = strconv.ParseInt(call.Arguments[0].String(), 10, 64)
+		if err != nil {
+			panic(jsvm.NewTypeError("invalid Long value `" + call.Arguments[0].String() + "`"))
+		}
+	}
+
+	toNumber := func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(float64(value))
+	}
+	call.This.Set("toNumber", toNumber)
+	call.This.Set("valueOf", toNumber)
+	call.This.Set("toString", func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(strconv.FormatInt(value, 10))
+	})
+	call.This.Set("toJSON", func(call goja.FunctionCall) goja.Value {
+		if value > maxSafeInteger || value < -maxSafeInteger {
+			return jsvm.ToValue(strconv.FormatInt(value, 10))
+		}
+		return jsvm.ToValue(value)
+	})
+
+	return nil
+}
+
+func JSDoubleCtor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object {
+	var value float64
+	if len(call.Arguments) > 0 {
+		value = call.Arguments[0].ToFloat()
+	}
+
+	valueOf := func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(value)
+	}
+	call.This.Set("valueOf", valueOf)
+	call.This.Set("toJSON", valueOf)
+	call.This.Set("toString", func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(strconv.FormatFloat(value, 'g', -1, 64))
+	})
+
+	return nil
+}
+
+func JSUUIDCtor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object {
+	uuid := make([]byte, 16)
+	if len(call.Arguments) > 0 {
+		decoded, err := hex.DecodeString(strings.ReplaceAll(call.Arguments[0].String(), "-", ""))
+		if err != nil || len(decoded) != 16 {
+			panic(jsvm.NewTypeError("invalid UUID `" + call.Arguments[0].String() + "`"))
+		}
+		uuid = decoded
+	} else {
+		if _, err := rand.Read(uuid); err != nil {
+			panic(err)
+		}
+		uuid[6] = (uuid[6] & 0x0f) | 0x40
+		uuid[8] = (uuid[8] & 0x3f) | 0x80
+	}
+
+	setUUIDMethods(call.This, jsvm, uuid)
+
+	return nil
+}
+
+func setUUIDMethods(obj *goja.Object, jsvm *goja.Runtime, uuid []byte) {
+	hexStr := hex.EncodeToString(uuid)
+	dashed := hexStr[0:8] + "-" + hexStr[8:12] + "-" + hexStr[12:16] + "-" + hexStr[16:20] + "-" + hexStr[20:32]
+
+	toString := func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(dashed)
+	}
+	obj.Set("toString", toString)
+	obj.Set("toJSON", toString)
+	obj.Set("toHexString", func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(hexStr)
+	})
+}
+
+func JSBinaryCtor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object {
+	var data []byte
+	if len(call.Arguments) > 0 {
+		arg := call.Arguments[0]
+		if _, ok := arg.Export().(string); ok {
+			decoded, err := base64.StdEncoding.DecodeString(arg.String())
+			if err != nil {
+				panic(jsvm.NewTypeError("invalid base64 data for Binary: " + err.Error()))
+			}
+			data = decoded
+		} else if err := jsvm.ExportTo(arg, &data); err != nil {
+			panic(jsvm.NewTypeError("Binary data must be a base64 string, Uint8Array or array of bytes"))
+		}
+	}
+	var subType int64
+	if len(call.Arguments) > 1 {
+		subType = call.Arguments[1].ToInteger()
+	}
+
+	setBinaryMethods(call.This, jsvm, data, byte(subType))
+
+	return nil
+}
+
+func setBinaryMethods(obj *goja.Object, jsvm *goja.Runtime, data []byte, subType byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	obj.Set("subType", int64(subType))
+	obj.Set("length", len(data))
+	toBase64 := func(call goja.FunctionCall) goja.Value {
+		return jsvm.ToValue(encoded)
+	}
+	obj.Set("toBase64", toBase64)
+	obj.Set("toString", toBase64)
+	obj.Set("toJSON", toBase64)
+}
+
+func JSTimestampCtor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object {
+	var t, i int64
+	if len(call.Arguments) > 0 {
+		spec := call.Arguments[0].ToObject(jsvm)
+		t = spec.Get("t").ToInteger()
+		i = spec.Get("i").ToInteger()
+	}
+
+	call.This.Set("t", t)
+	call.This.Set("i", i)
+
+	return nil
+}
+
+func newJSObject(jsvm *goja.Runtime, ctorVal goja.Value, args ...any) goja.Value {
+	argVals := make([]goja.Value, len(args))
+	for i, arg := range args {
+		argVals[i] = jsvm.ToValue(arg)
+	}
+	obj, err := jsvm.New(ctorVal, argVals...)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+func callJSMethod(jsvm *goja.Runtime, val goja.Value, name string) goja.Value {
+	method, ok := goja.AssertFunction(val.ToObject(jsvm).Get(name))
+	if !ok {
+		panic(name + " is not a function")
+	}
+	result, err := method(val)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// bsonFromJSValue converts instances of the BSON classes, RegExp and numbers
+// into their BSON representations.
+func bsonFromJSValue(jsvm *goja.Runtime, rtData *driverRuntimeData, val goja.Value) (any, bool) {
+	switch {
+	case IsObjectId(jsvm, val, rtData.objectIdCtorVal):
+		return ObjectIdFromJSValue(jsvm, val), true
+
+	case jsvm.InstanceOf(val, rtData.decimal128CtorVal.ToObject(jsvm)):
+		decimal, err := primitive.ParseDecimal128(callJSMethod(jsvm, val, "toString").String())
+		if err != nil {
+			panic(err)
+		}
+		return decimal, true
+
+	case jsvm.InstanceOf(val, rtData.longCtorVal.ToObject(jsvm)):
+		value, err := strconv.ParseInt(callJSMethod(jsvm, val, "toString").String(), 10, 64)
+		if err != nil {
+			panic(err)
+		}
+		return value, true
+
+	case jsvm.InstanceOf(val, rtData.doubleCtorVal.ToObject(jsvm)):
+		return callJSMethod(jsvm, val, "valueOf").ToFloat(), true
+
+	case jsvm.InstanceOf(val, rtData.uuidCtorVal.ToObject(jsvm)):
+		data, err := hex.DecodeString(callJSMethod(jsvm, val, "toHexString").String())
+		if err != nil {
+			panic(err)
+		}
+		return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: data}, true
+
+	case jsvm.InstanceOf(val, rtData.binaryCtorVal.ToObject(jsvm)):
+		data, err := base64.StdEncoding.DecodeString(callJSMethod(jsvm, val, "toBase64").String())
+		if err != nil {
+			panic(err)
+		}
+		subType := val.ToObject(jsvm).Get("subType").ToInteger()
+		return primitive.Binary{Subtype: byte(subType), Data: data}, true
+
+	case jsvm.InstanceOf(val, rtData.timestampCtorVal.ToObject(jsvm)):
+		obj := val.ToObject(jsvm)
+		return primitive.Timestamp{T: uint32(obj.Get("t").ToInteger()), I: uint32(obj.Get("i").ToInteger())}, true
+
+	case js.IsObjectFromConstructorWithGlobalName(jsvm, val, "RegExp"):
+		obj := val.ToObject(jsvm)
+		options := []string{}
+		for _, flag := range strings.Split(obj.Get("flags").String(), "") {
+			if strings.Contains("imsu", flag) {
+				options = append(options, flag)
+			}
+		}
+		sort.Strings(options)
+		return primitive.Regex{Pattern: obj.Get("source").String(), Options: strings.Join(options, "")}, true
+	}
+
+	// Numbers follow the same rules as the Node.js driver. Integers that fit
+	// in 32 bits are stored as int32 and all other numbers as doubles. Use
+	// Long for 64-bit integers.
+	if value, ok := val.Export().(int64); ok {
+		if value >= math.MinInt32 && value <= math.MaxInt32 {
+			return int32(value), true
+		}
+		return float64(value), true
+	}
+
+	return nil, false
+}
+
+// bsonIntoJSValue converts BSON values read from the database into instances
+// of the BSON classes and built in JavaScript types.
+func bsonIntoJSValue(jsvm *goja.Runtime, rtData *driverRuntimeData, value any, intoJs func(any) goja.Value) (goja.Value, bool) {
+	switch value := value.(type) {
+	case primitive.ObjectID:
+		return newJSObject(jsvm, rtData.objectIdCtorVal, value.Hex()), true
+	case primitive.DateTime:
+		return newJSObject(jsvm, jsvm.Get("Date"), int64(value)), true
+	case primitive.Decimal128:
+		return newJSObject(jsvm, rtData.decimal128CtorVal, value.String()), true
+	case Long:
+		return newJSObject(jsvm, rtData.longCtorVal, strconv.FormatInt(int64(value), 10)), true
+	case primitive.Binary:
+		if value.Subtype == bson.TypeBinaryUUID && len(value.Data) == 16 {
+			return newJSObject(jsvm, rtData.uuidCtorVal, hex.EncodeToString(value.Data)), true
+		}
+		return newJSObject(jsvm, rtData.binaryCtorVal, base64.StdEncoding.EncodeToString(value.Data), int64(value.Subtype)), true
+	case primitive.Timestamp:
+		return newJSObject(jsvm, rtData.timestampCtorVal, map[string]any{"t": value.T, "i": value.I}), true
+	case primitive.Regex:
+		return newJSObject(jsvm, jsvm.Get("RegExp"), value.Pattern, value.Options), true
+	case primitive.Null:
+		return goja.Null(), true
+	case primitive.Undefined:
+		return goja.Undefined(), true
+	case primitive.D:
+		obj := jsvm.NewObject()
+		for _, elem := range value {
+			obj.Set(elem.Key, intoJs(elem.Value))
+		}
+		return obj, true
+	default:
+		return nil, false
+	}
+}
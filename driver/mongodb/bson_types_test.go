@@ -0,0 +1,178 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/telemetryos/graviton/config"
+
+	"github.com/dop251/goja"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func setupTestRuntime(t *testing.T) (*Driver, *goja.Runtime) {
+	t.Helper()
+
+	drv := New(&config.DatabaseConfig{})
+	runtime := goja.New()
+	ctx := context.Background()
+
+	drv.Init(ctx, runtime)
+	for name, value := range drv.Globals(ctx, runtime) {
+		runtime.Set(name, value)
+	}
+
+	return drv, runtime
+}
+
+func Test_BSONTypes_FromJSValue(t *testing.T) {
+	drv, runtime := setupTestRuntime(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		src      string
+		expected any
+	}{
+		{`new ObjectId("65a1b2c3d4e5f60718293a4b")`, mustObjectID(t, "65a1b2c3d4e5f60718293a4b")},
+		{`new Decimal128("12.50")`, mustDecimal128(t, "12.50")},
+		{`new Long("9007199254740993")`, int64(9007199254740993)},
+		{`new Double(2)`, float64(2)},
+		{`new UUID("0f8fad5b-d9cb-469f-a165-70867728950e")`, primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte{0x0f, 0x8f, 0xad, 0x5b, 0xd9, 0xcb, 0x46, 0x9f, 0xa1, 0x65, 0x70, 0x86, 0x77, 0x28, 0x95, 0x0e}}},
+		{`new Binary("AQID", 128)`, primitive.Binary{Subtype: 128, Data: []byte{1, 2, 3}}},
+		{`new Timestamp({ t: 1700000000, i: 3 })`, primitive.Timestamp{T: 1700000000, I: 3}},
+		{`/^ab+c$/gi`, primitive.Regex{Pattern: "^ab+c$", Options: "i"}},
+		{`42`, int32(42)},
+		{`4294967296`, float64(4294967296)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			val, err := runtime.RunString(tt.src)
+			if err != nil {
+				t.Fatalf("RunString() error = %v", err)
+			}
+			result, ok := drv.MaybeFromJSValue(ctx, runtime, val)
+			if !ok {
+				t.Fatalf("MaybeFromJSValue() ok = false, want true")
+			}
+			if !bsonEqual(result, tt.expected) {
+				t.Errorf("MaybeFromJSValue() = %#v, want %#v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_BSONTypes_RoundTrip(t *testing.T) {
+	drv, runtime := setupTestRuntime(t)
+	ctx := context.Background()
+
+	values := []any{
+		mustObjectID(t, "65a1b2c3d4e5f60718293a4b"),
+		mustDecimal128(t, "-0.0001"),
+		primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: []byte("0123456789abcdef")},
+		primitive.Binary{Subtype: 0, Data: []byte{0, 255}},
+		primitive.Timestamp{T: 1, I: 2},
+		primitive.Regex{Pattern: "a.c", Options: "im"},
+	}
+
+	intoJs := func(value any) goja.Value { return runtime.ToValue(value) }
+
+	for _, value := range values {
+		jsVal, ok := drv.MaybeIntoJSValue(ctx, runtime, value, intoJs)
+		if !ok {
+			t.Fatalf("MaybeIntoJSValue(%#v) ok = false, want true", value)
+		}
+		result, ok := drv.MaybeFromJSValue(ctx, runtime, jsVal)
+		if !ok {
+			t.Fatalf("MaybeFromJSValue() ok = false for %#v", value)
+		}
+		if !bsonEqual(result, value) {
+			t.Errorf("round trip = %#v, want %#v", result, value)
+		}
+	}
+
+	jsVal, _ := drv.MaybeIntoJSValue(ctx, runtime, Long(1<<62), intoJs)
+	if result, _ := drv.MaybeFromJSValue(ctx, runtime, jsVal); result != int64(1<<62) {
+		t.Errorf("Long round trip = %#v, want %d", result, int64(1<<62))
+	}
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	jsVal, _ = drv.MaybeIntoJSValue(ctx, runtime, primitive.NewDateTimeFromTime(now), intoJs)
+	if exported, ok := jsVal.Export().(time.Time); !ok || !exported.Equal(now) {
+		t.Errorf("DateTime into JS = %v, want Date for %v", jsVal.Export(), now)
+	}
+}
+
+func Test_BSONTypes_DocumentKeyOrder(t *testing.T) {
+	drv, runtime := setupTestRuntime(t)
+
+	intoJs := func(value any) goja.Value { return runtime.ToValue(value) }
+	jsVal, ok := drv.MaybeIntoJSValue(context.Background(), runtime, bson.D{{Key: "b", Value: 1}, {Key: "a", Value: 2}}, intoJs)
+	if !ok {
+		t.Fatalf("MaybeIntoJSValue() ok = false, want true")
+	}
+
+	keys := jsVal.ToObject(runtime).Keys()
+	if len(keys) != 2 || keys[0] != "b" || keys[1] != "a" {
+		t.Errorf("MaybeIntoJSValue() keys = %v, want [b a]", keys)
+	}
+}
+
+func Test_BSONTypes_Registry_DecodesInt64AsLong(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "big", Value: int64(1 << 40)}, {Key: "small", Value: int32(1)}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var doc map[string]any
+	if err := bson.UnmarshalWithRegistry(newRegistry(), raw, &doc); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if doc["big"] != Long(1<<40) {
+		t.Errorf("big = %#v, want Long", doc["big"])
+	}
+	if doc["small"] != int32(1) {
+		t.Errorf("small = %#v, want int32", doc["small"])
+	}
+
+	encoded, err := bson.Marshal(bson.D{{Key: "big", Value: Long(5)}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if bson.Raw(encoded).Lookup("big").Type != bson.TypeInt64 {
+		t.Errorf("Long encoded as %s, want int64", bson.Raw(encoded).Lookup("big").Type)
+	}
+}
+
+func mustObjectID(t *testing.T, hex string) primitive.ObjectID {
+	t.Helper()
+	objectId, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		t.Fatalf("ObjectIDFromHex() error = %v", err)
+	}
+	return objectId
+}
+
+func mustDecimal128(t *testing.T, value string) primitive.Decimal128 {
+	t.Helper()
+	decimal, err := primitive.ParseDecimal128(value)
+	if err != nil {
+		t.Fatalf("ParseDecimal128() error = %v", err)
+	}
+	return decimal
+}
+
+func bsonEqual(a any, b any) bool {
+	switch a := a.(type) {
+	case primitive.Binary:
+		b, ok := b.(primitive.Binary)
+		return ok && a.Equal(b)
+	case primitive.Decimal128:
+		b, ok := b.(primitive.Decimal128)
+		return ok && a.String() == b.String()
+	default:
+		return a == b
+	}
+}
@@ -2,7 +2,11 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -19,25 +23,34 @@ type FindOptions = options.FindOptions
 type FindOneOptions = options.FindOneOptions
 type UpdateOptions = options.UpdateOptions
 type DeleteOptions = options.DeleteOptions
+type ReplaceOptions = options.ReplaceOptions
+type FindOneAndUpdateOptions = options.FindOneAndUpdateOptions
+type FindOneAndDeleteOptions = options.FindOneAndDeleteOptions
+type AggregateOptions = options.AggregateOptions
+type BulkWriteOptions = options.BulkWriteOptions
+type CountOptions = options.CountOptions
+type DistinctOptions = options.DistinctOptions
+type IndexOptions = options.IndexOptions
+type ListIndexesOptions = options.ListIndexesOptions
 
-func (c *Collection) InsertMany(docs []any, options ...*InsertManyOptions) *mongo.InsertManyResult {
-	result, err := c.driver.database.Collection(c.name).InsertMany(c.ctx, docs, options...)
+func (c *Collection) InsertMany(docs []any, opts ...any) *mongo.InsertManyResult {
+	result, err := c.driver.database.Collection(c.name).InsertMany(c.ctx, docs, decodeOptions[InsertManyOptions]("insertMany", opts)...)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
-func (c *Collection) InsertOne(doc any, options ...*InsertOneOptions) *mongo.InsertOneResult {
-	result, err := c.driver.database.Collection(c.name).InsertOne(c.ctx, doc, options...)
+func (c *Collection) InsertOne(doc any, opts ...any) *mongo.InsertOneResult {
+	result, err := c.driver.database.Collection(c.name).InsertOne(c.ctx, doc, decodeOptions[InsertOneOptions]("insertOne", opts)...)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
-func (c *Collection) Find(filter any, options ...*FindOptions) []map[string]any {
-	cur, err := c.driver.database.Collection(c.name).Find(c.ctx, filter, options...)
+func (c *Collection) Find(filter any, opts ...any) []map[string]any {
+	cur, err := c.driver.database.Collection(c.name).Find(c.ctx, filterOrEmpty(filter), decodeOptions[FindOptions]("find", opts)...)
 	if err != nil {
 		panic(err)
 	}
@@ -50,43 +63,361 @@ func (c *Collection) Find(filter any, options ...*FindOptions) []map[string]any
 	return results
 }
 
-func (c *Collection) FindOne(filter any, options ...*FindOneOptions) map[string]any {
+func (c *Collection) FindOne(filter any, opts ...any) map[string]any {
 	var result map[string]any
-	err := c.driver.database.Collection(c.name).FindOne(c.ctx, filter, options...).Decode(&result)
+	err := c.driver.database.Collection(c.name).FindOne(c.ctx, filterOrEmpty(filter), decodeOptions[FindOneOptions]("findOne", opts)...).Decode(&result)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
-func (c *Collection) UpdateMany(filter any, update any, options ...*UpdateOptions) *mongo.UpdateResult {
-	result, err := c.driver.database.Collection(c.name).UpdateMany(c.ctx, filter, update, options...)
+func (c *Collection) UpdateMany(filter any, update any, opts ...any) *mongo.UpdateResult {
+	result, err := c.driver.database.Collection(c.name).UpdateMany(c.ctx, filter, update, decodeOptions[UpdateOptions]("updateMany", opts)...)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
-func (c *Collection) UpdateOne(filter any, update any, options ...*UpdateOptions) *mongo.UpdateResult {
-	result, err := c.driver.database.Collection(c.name).UpdateOne(c.ctx, filter, update, options...)
+func (c *Collection) UpdateOne(filter any, update any, opts ...any) *mongo.UpdateResult {
+	result, err := c.driver.database.Collection(c.name).UpdateOne(c.ctx, filter, update, decodeOptions[UpdateOptions]("updateOne", opts)...)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
-func (c *Collection) DeleteMany(filter any, options ...*DeleteOptions) *mongo.DeleteResult {
-	result, err := c.driver.database.Collection(c.name).DeleteMany(c.ctx, filter, options...)
+func (c *Collection) DeleteMany(filter any, opts ...any) *mongo.DeleteResult {
+	result, err := c.driver.database.Collection(c.name).DeleteMany(c.ctx, filter, decodeOptions[DeleteOptions]("deleteMany", opts)...)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
 
-func (c *Collection) DeleteOne(filter any, options ...*DeleteOptions) *mongo.DeleteResult {
-	result, err := c.driver.database.Collection(c.name).DeleteOne(c.ctx, filter, options...)
+func (c *Collection) DeleteOne(filter any, opts ...any) *mongo.DeleteResult {
+	result, err := c.driver.database.Collection(c.name).DeleteOne(c.ctx, filter, decodeOptions[DeleteOptions]("deleteOne", opts)...)
 	if err != nil {
 		panic(err)
 	}
 	return result
 }
+
+func (c *Collection) ReplaceOne(filter any, replacement any, opts ...any) *mongo.UpdateResult {
+	result, err := c.driver.database.Collection(c.name).ReplaceOne(c.ctx, filter, replacement, decodeOptions[ReplaceOptions]("replaceOne", opts)...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// FindOneAndUpdate returns the matched document, or nil if no document
+// matched the filter.
+func (c *Collection) FindOneAndUpdate(filter any, update any, opts ...any) map[string]any {
+	var result map[string]any
+	err := c.driver.database.Collection(c.name).FindOneAndUpdate(c.ctx, filter, update, decodeOptions[FindOneAndUpdateOptions]("findOneAndUpdate", opts)...).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// FindOneAndDelete returns the deleted document, or nil if no document
+// matched the filter.
+func (c *Collection) FindOneAndDelete(filter any, opts ...any) map[string]any {
+	var result map[string]any
+	err := c.driver.database.Collection(c.name).FindOneAndDelete(c.ctx, filter, decodeOptions[FindOneAndDeleteOptions]("findOneAndDelete", opts)...).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Aggregate runs the pipeline and returns the resulting documents. Pipelines
+// ending in $out or $merge return no documents. They cannot run within a
+// transaction so they run outside of it, and what they write is not rolled
+// back if the migration fails.
+func (c *Collection) Aggregate(pipeline []any, opts ...any) []map[string]any {
+	ctx := commandContext(c.ctx, aggregateCommandName(pipeline))
+	cur, err := c.driver.database.Collection(c.name).Aggregate(ctx, pipeline, decodeOptions[AggregateOptions]("aggregate", opts)...)
+	if err != nil {
+		panic(err)
+	}
+
+	results := []map[string]any{}
+	if err := cur.All(ctx, &results); err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// BulkWrite accepts operations in the same shape as the MongoDB shell, for
+// example `{ updateOne: { filter: {...}, update: {...}, upsert: true } }`.
+func (c *Collection) BulkWrite(operations []any, opts ...any) *mongo.BulkWriteResult {
+	models := make([]mongo.WriteModel, 0, len(operations))
+	for i, operation := range operations {
+		model, err := bulkWriteModel(operation)
+		if err != nil {
+			panic(fmt.Errorf("bulkWrite operation %d: %w", i, err))
+		}
+		models = append(models, model)
+	}
+
+	result, err := c.driver.database.Collection(c.name).BulkWrite(c.ctx, models, decodeOptions[BulkWriteOptions]("bulkWrite", opts)...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (c *Collection) CountDocuments(filter any, opts ...any) int64 {
+	count, err := c.driver.database.Collection(c.name).CountDocuments(c.ctx, filterOrEmpty(filter), decodeOptions[CountOptions]("countDocuments", opts)...)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+func (c *Collection) Distinct(fieldName string, filter any, opts ...any) []any {
+	values, err := c.driver.database.Collection(c.name).Distinct(c.ctx, fieldName, filterOrEmpty(filter), decodeOptions[DistinctOptions]("distinct", opts)...)
+	if err != nil {
+		panic(err)
+	}
+	return values
+}
+
+// CreateIndex creates an index and returns its name. Indexes on a collection
+// that existed before the migration's transaction cannot be built within it,
+// so those are built outside of it and are not dropped if the migration fails.
+func (c *Collection) CreateIndex(keys any, opts ...any) string {
+	model := mongo.IndexModel{Keys: keys, Options: mergeIndexOptions(decodeOptions[IndexOptions]("createIndex", opts))}
+	name, err := c.driver.database.Collection(c.name).Indexes().CreateOne(c.indexContext(), model)
+	if err != nil {
+		panic(err)
+	}
+	return name
+}
+
+// CreateIndexes creates an index for each of the key specifications, applying
+// the same options to each, and returns their names. A name would be given to
+// every index, so it is an error when there is more than one. Like
+// CreateIndex, indexes on an existing collection are built outside of the
+// transaction.
+func (c *Collection) CreateIndexes(keySpecs []any, opts ...any) []string {
+	indexOptions := mergeIndexOptions(decodeOptions[IndexOptions]("createIndexes", opts))
+	if len(keySpecs) > 1 && indexOptions != nil && indexOptions.Name != nil {
+		panic(errors.New("createIndexes cannot give one name to several indexes, use createIndex for each named index"))
+	}
+	models := make([]mongo.IndexModel, 0, len(keySpecs))
+	for _, keys := range keySpecs {
+		models = append(models, mongo.IndexModel{Keys: keys, Options: indexOptions})
+	}
+	names, err := c.driver.database.Collection(c.name).Indexes().CreateMany(c.indexContext(), models)
+	if err != nil {
+		panic(err)
+	}
+	return names
+}
+
+// DropIndex drops an index by its name or by its key specification. Indexes
+// cannot be dropped within a transaction so this runs outside of it.
+func (c *Collection) DropIndex(index any) {
+	command := bson.D{
+		{Key: "dropIndexes", Value: c.name},
+		{Key: "index", Value: index},
+	}
+	if err := c.driver.database.RunCommand(commandContext(c.ctx, "dropIndexes"), command).Err(); err != nil {
+		panic(err)
+	}
+}
+
+func (c *Collection) ListIndexes(opts ...any) []map[string]any {
+	cur, err := c.driver.database.Collection(c.name).Indexes().List(c.ctx, decodeOptions[ListIndexesOptions]("listIndexes", opts)...)
+	if err != nil {
+		panic(err)
+	}
+
+	results := []map[string]any{}
+	if err := cur.All(c.ctx, &results); err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// IndexExists reports whether the collection has an index with the given
+// name, such as email_1.
+func (c *Collection) IndexExists(name string) bool {
+	return slices.ContainsFunc(c.ListIndexes(), func(index map[string]any) bool {
+		return index["name"] == name
+	})
+}
+
+// Rename renames the collection. Subsequent calls on the collection use the
+// new name. The target collection is dropped first when dropTarget is true.
+// Collections cannot be renamed within a transaction so this runs outside of
+// it.
+func (c *Collection) Rename(newName string, dropTarget ...bool) {
+	databaseName := c.driver.database.Name()
+	command := bson.D{
+		{Key: "renameCollection", Value: databaseName + "." + c.name},
+		{Key: "to", Value: databaseName + "." + newName},
+		{Key: "dropTarget", Value: len(dropTarget) > 0 && dropTarget[0]},
+	}
+	if err := c.driver.client.Database("admin").RunCommand(commandContext(c.ctx, "renameCollection"), command).Err(); err != nil {
+		panic(err)
+	}
+	c.name = newName
+}
+
+// indexContext returns the context indexes on the collection are built with.
+// From MongoDB 4.4 a transaction can build indexes on the collections it
+// creates, which are not yet visible outside of it. Indexes on a collection
+// that existed before the transaction are built outside of it.
+func (c *Collection) indexContext() context.Context {
+	if mongo.SessionFromContext(c.ctx) == nil {
+		return c.ctx
+	}
+	if server := c.driver.server; server != nil && !server.parsedVersion.AtLeast(ServerVersion{Major: 4, Minor: 4}) {
+		return commandContext(c.ctx, "createIndexes")
+	}
+
+	names, err := c.driver.database.ListCollectionNames(commandContext(c.ctx, "listCollections"), bson.D{{Key: "name", Value: c.name}})
+	if err != nil {
+		panic(err)
+	}
+	if len(names) > 0 {
+		return commandContext(c.ctx, "createIndexes")
+	}
+	return c.ctx
+}
+
+// aggregateCommandName returns the name commandContext knows the pipeline by,
+// which is that of its last stage when it writes its results with $out or
+// $merge.
+func aggregateCommandName(pipeline []any) string {
+	if len(pipeline) > 0 {
+		stage, err := toDocument(pipeline[len(pipeline)-1])
+		if err == nil && len(stage) == 1 && (stage[0].Key == "$out" || stage[0].Key == "$merge") {
+			return stage[0].Key
+		}
+	}
+	return "aggregate"
+}
+
+func mergeIndexOptions(indexOptions []*IndexOptions) *IndexOptions {
+	if len(indexOptions) == 0 {
+		return nil
+	}
+	return options.MergeIndexOptions(indexOptions...)
+}
+
+func bulkWriteModel(operation any) (mongo.WriteModel, error) {
+	operationDoc, err := toDocument(operation)
+	if err != nil {
+		return nil, err
+	}
+	if len(operationDoc) != 1 {
+		return nil, errors.New("expected exactly one of insertOne, updateOne, updateMany, replaceOne, deleteOne or deleteMany")
+	}
+
+	operationName := operationDoc[0].Key
+	args, err := toDocument(operationDoc[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", operationName, err)
+	}
+
+	filter, hasFilter := documentField(args, "filter")
+	if !hasFilter {
+		filter = bson.D{}
+	}
+	upsert, _ := documentField(args, "upsert")
+	hint, hasHint := documentField(args, "hint")
+
+	switch operationName {
+	case "insertOne":
+		document, ok := documentField(args, "document")
+		if !ok {
+			return nil, errors.New("insertOne: document is required")
+		}
+		return mongo.NewInsertOneModel().SetDocument(document), nil
+
+	case "updateOne", "updateMany":
+		update, ok := documentField(args, "update")
+		if !ok {
+			return nil, fmt.Errorf("%s: update is required", operationName)
+		}
+		var arrayFilters *options.ArrayFilters
+		if filters, ok := documentField(args, "arrayFilters"); ok {
+			filtersArr, ok := filters.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: arrayFilters must be an array", operationName)
+			}
+			arrayFilters = &options.ArrayFilters{Filters: filtersArr}
+		}
+
+		if operationName == "updateOne" {
+			model := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update)
+			if upsert, ok := upsert.(bool); ok {
+				model.SetUpsert(upsert)
+			}
+			if arrayFilters != nil {
+				model.SetArrayFilters(*arrayFilters)
+			}
+			if hasHint {
+				model.SetHint(hint)
+			}
+			return model, nil
+		}
+		model := mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update)
+		if upsert, ok := upsert.(bool); ok {
+			model.SetUpsert(upsert)
+		}
+		if arrayFilters != nil {
+			model.SetArrayFilters(*arrayFilters)
+		}
+		if hasHint {
+			model.SetHint(hint)
+		}
+		return model, nil
+
+	case "replaceOne":
+		replacement, ok := documentField(args, "replacement")
+		if !ok {
+			return nil, errors.New("replaceOne: replacement is required")
+		}
+		model := mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(replacement)
+		if upsert, ok := upsert.(bool); ok {
+			model.SetUpsert(upsert)
+		}
+		if hasHint {
+			model.SetHint(hint)
+		}
+		return model, nil
+
+	case "deleteOne":
+		model := mongo.NewDeleteOneModel().SetFilter(filter)
+		if hasHint {
+			model.SetHint(hint)
+		}
+		return model, nil
+
+	case "deleteMany":
+		model := mongo.NewDeleteManyModel().SetFilter(filter)
+		if hasHint {
+			model.SetHint(hint)
+		}
+		return model, nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation `%s`", operationName)
+	}
+}
@@ -1,6 +1,8 @@
 package mongodb
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -186,3 +188,392 @@ func Test_Collection_Find_WithOptions(t *testing.T) {
 		t.Errorf("Find() with limit returned %d results, want 2", len(results))
 	}
 }
+
+func Test_Collection_ReplaceOne_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"_id": 1, "value": "old", "extra": true})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	result := coll.ReplaceOne(bson.M{"_id": 1}, bson.M{"value": "new"})
+	if result.ModifiedCount != 1 {
+		t.Errorf("ReplaceOne() ModifiedCount = %d, want 1", result.ModifiedCount)
+	}
+
+	doc := coll.FindOne(bson.M{"_id": 1})
+	if doc["value"] != "new" {
+		t.Errorf("ReplaceOne() value = %v, want 'new'", doc["value"])
+	}
+	if _, ok := doc["extra"]; ok {
+		t.Error("ReplaceOne() kept field from the replaced document")
+	}
+}
+
+func Test_Collection_FindOneAndUpdate_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"_id": 1, "count": int32(1)})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	result := coll.FindOneAndUpdate(bson.M{"_id": 1}, bson.M{"$inc": bson.M{"count": 1}}, opts)
+	if result["count"] != int32(2) {
+		t.Errorf("FindOneAndUpdate() count = %v, want 2", result["count"])
+	}
+
+	if result := coll.FindOneAndUpdate(bson.M{"_id": 2}, bson.M{"$inc": bson.M{"count": 1}}); result != nil {
+		t.Errorf("FindOneAndUpdate() = %v, want nil when nothing matches", result)
+	}
+}
+
+func Test_Collection_FindOneAndDelete_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"_id": 1, "value": "test"})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	result := coll.FindOneAndDelete(bson.M{"_id": 1})
+	if result["value"] != "test" {
+		t.Errorf("FindOneAndDelete() value = %v, want 'test'", result["value"])
+	}
+	if count := coll.CountDocuments(bson.M{}); count != 0 {
+		t.Errorf("CountDocuments() = %d, want 0 after FindOneAndDelete()", count)
+	}
+	if result := coll.FindOneAndDelete(bson.M{"_id": 1}); result != nil {
+		t.Errorf("FindOneAndDelete() = %v, want nil when nothing matches", result)
+	}
+}
+
+func Test_Collection_Aggregate_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"group": "a", "value": int32(1)})
+	testColl.InsertOne(ctx, bson.M{"group": "a", "value": int32(2)})
+	testColl.InsertOne(ctx, bson.M{"group": "b", "value": int32(3)})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	results := coll.Aggregate([]any{
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$group"},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: "$value"}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	})
+	if len(results) != 2 {
+		t.Fatalf("Aggregate() returned %d results, want 2", len(results))
+	}
+	if results[0]["_id"] != "a" || results[0]["total"] != int32(3) {
+		t.Errorf("Aggregate() first result = %v, want group a with total 3", results[0])
+	}
+}
+
+func Test_Collection_Aggregate_Out(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": "test"})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	results := coll.Aggregate([]any{bson.D{{Key: "$out", Value: "test_copy"}}})
+	if len(results) != 0 {
+		t.Errorf("Aggregate() with $out returned %d results, want 0", len(results))
+	}
+	if count := handle.Collection("test_copy").CountDocuments(bson.M{}); count != 1 {
+		t.Errorf("CountDocuments() on $out collection = %d, want 1", count)
+	}
+}
+
+func Test_Collection_Aggregate_OutInTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": "test"})
+
+	err := drv.WithTransaction(ctx, func(sessCtx context.Context) error {
+		coll := drv.Handle(sessCtx).(*MongoHandle).Collection("test")
+		coll.Aggregate([]any{bson.D{{Key: "$out", Value: "test_copy"}}})
+		coll.Aggregate([]any{map[string]any{"$merge": map[string]any{"into": "test_merged"}}})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v, want $out and $merge to run outside the transaction", err)
+	}
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	for _, name := range []string{"test_copy", "test_merged"} {
+		if count := handle.Collection(name).CountDocuments(bson.M{}); count != 1 {
+			t.Errorf("CountDocuments() on %s = %d, want 1", name, count)
+		}
+	}
+}
+
+func Test_AggregateCommandName(t *testing.T) {
+	tests := []struct {
+		pipeline []any
+		expected string
+	}{
+		{nil, "aggregate"},
+		{[]any{bson.D{{Key: "$match", Value: bson.D{}}}}, "aggregate"},
+		{[]any{bson.D{{Key: "$out", Value: "copy"}}, bson.D{{Key: "$match", Value: bson.D{}}}}, "aggregate"},
+		{[]any{bson.D{{Key: "$match", Value: bson.D{}}}, bson.D{{Key: "$out", Value: "copy"}}}, "$out"},
+		{[]any{map[string]any{"$merge": map[string]any{"into": "copy"}}}, "$merge"},
+	}
+
+	for _, tt := range tests {
+		if name := aggregateCommandName(tt.pipeline); name != tt.expected {
+			t.Errorf("aggregateCommandName(%v) = %q, want %q", tt.pipeline, name, tt.expected)
+		}
+	}
+}
+
+func Test_Collection_BulkWrite_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"_id": 1, "value": "update"})
+	testColl.InsertOne(ctx, bson.M{"_id": 2, "value": "delete"})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	result := coll.BulkWrite([]any{
+		map[string]any{"insertOne": map[string]any{"document": bson.M{"_id": 3}}},
+		map[string]any{"updateOne": map[string]any{
+			"filter": bson.M{"_id": 1},
+			"update": bson.M{"$set": bson.M{"value": "updated"}},
+		}},
+		map[string]any{"deleteOne": map[string]any{"filter": bson.M{"_id": 2}}},
+		map[string]any{"replaceOne": map[string]any{
+			"filter":      bson.M{"_id": 4},
+			"replacement": bson.M{"value": "upserted"},
+			"upsert":      true,
+		}},
+	})
+
+	if result.InsertedCount != 1 || result.ModifiedCount != 1 || result.DeletedCount != 1 || result.UpsertedCount != 1 {
+		t.Errorf("BulkWrite() result = %+v, want one insert, update, delete and upsert", result)
+	}
+}
+
+func Test_Collection_CountDocuments_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": 1})
+	testColl.InsertOne(ctx, bson.M{"value": 2})
+	testColl.InsertOne(ctx, bson.M{"value": 2})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	if count := coll.CountDocuments(bson.M{"value": 2}); count != 2 {
+		t.Errorf("CountDocuments() = %d, want 2", count)
+	}
+}
+
+func Test_Collection_Distinct_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": "a"})
+	testColl.InsertOne(ctx, bson.M{"value": "b"})
+	testColl.InsertOne(ctx, bson.M{"value": "a"})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	values := coll.Distinct("value", bson.M{})
+	if len(values) != 2 {
+		t.Errorf("Distinct() returned %d values, want 2", len(values))
+	}
+}
+
+func Test_Collection_Indexes(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	name := coll.CreateIndex(bson.D{{Key: "b", Value: 1}, {Key: "a", Value: -1}}, options.Index().SetUnique(true))
+	if name != "b_1_a_-1" {
+		t.Errorf("CreateIndex() = %q, want b_1_a_-1 (key order must be kept)", name)
+	}
+
+	names := coll.CreateIndexes([]any{bson.D{{Key: "c", Value: 1}}, bson.D{{Key: "d", Value: 1}}})
+	if len(names) != 2 {
+		t.Errorf("CreateIndexes() returned %d names, want 2", len(names))
+	}
+
+	if indexes := coll.ListIndexes(); len(indexes) != 4 {
+		t.Errorf("ListIndexes() returned %d indexes, want 4", len(indexes))
+	}
+
+	if !coll.IndexExists("c_1") || coll.IndexExists("e_1") {
+		t.Errorf("IndexExists() is wrong for c_1 or e_1")
+	}
+
+	coll.DropIndex("c_1")
+	coll.DropIndex(bson.D{{Key: "d", Value: 1}})
+
+	indexes := coll.ListIndexes()
+	if len(indexes) != 2 {
+		t.Fatalf("ListIndexes() returned %d indexes after DropIndex(), want 2", len(indexes))
+	}
+	for _, index := range indexes {
+		if index["name"] == "b_1_a_-1" && index["unique"] != true {
+			t.Errorf("CreateIndex() did not apply the unique option")
+		}
+	}
+}
+
+func Test_Collection_Indexes_InTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"a": 1, "b": 2})
+
+	err := drv.WithTransaction(ctx, func(sessCtx context.Context) error {
+		coll := drv.Handle(sessCtx).(*MongoHandle).Collection("test")
+		coll.CreateIndex(bson.D{{Key: "a", Value: 1}})
+		coll.CreateIndexes([]any{bson.D{{Key: "b", Value: 1}}})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v, want the indexes built outside the transaction", err)
+	}
+
+	coll := drv.Handle(ctx).(*MongoHandle).Collection("test")
+	if !coll.IndexExists("a_1") || !coll.IndexExists("b_1") {
+		t.Errorf("ListIndexes() = %v, want a_1 and b_1", coll.ListIndexes())
+	}
+}
+
+func Test_Collection_Indexes_NewCollectionInTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	err := drv.WithTransaction(ctx, func(sessCtx context.Context) error {
+		handle := drv.Handle(sessCtx).(*MongoHandle)
+		coll := handle.CreateCollection("users")
+		coll.CreateIndex(bson.D{{Key: "email", Value: 1}}, options.Index().SetUnique(true))
+		coll.InsertOne(bson.M{"email": "ada@example.com"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v, want the index built within the transaction", err)
+	}
+
+	coll := drv.Handle(ctx).(*MongoHandle).Collection("users")
+	if !coll.IndexExists("email_1") {
+		t.Errorf("ListIndexes() = %v, want email_1", coll.ListIndexes())
+	}
+	if count := coll.CountDocuments(bson.M{}); count != 1 {
+		t.Errorf("CountDocuments() = %d, want 1", count)
+	}
+}
+
+func Test_Collection_CreateIndexes_RejectsSharedName(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	coll := drv.Handle(ctx).(*MongoHandle).Collection("test")
+	defer func() {
+		if recover() == nil {
+			t.Error("CreateIndexes() with a name for two indexes did not fail")
+		}
+	}()
+	coll.CreateIndexes([]any{bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "b", Value: 1}}}, options.Index().SetName("shared"))
+}
+
+func Test_Collection_Rename_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": "test"})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	coll.Rename("renamed")
+
+	if count := coll.CountDocuments(bson.M{}); count != 1 {
+		t.Errorf("CountDocuments() after Rename() = %d, want 1", count)
+	}
+	if count := handle.Collection("test").CountDocuments(bson.M{}); count != 0 {
+		t.Errorf("CountDocuments() on old name = %d, want 0", count)
+	}
+}
+
+func Test_BulkWriteModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation any
+		expected  mongo.WriteModel
+	}{
+		{
+			name:      "insertOne",
+			operation: bson.D{{Key: "insertOne", Value: bson.D{{Key: "document", Value: bson.M{"a": 1}}}}},
+			expected:  &mongo.InsertOneModel{},
+		},
+		{
+			name:      "updateMany",
+			operation: map[string]any{"updateMany": map[string]any{"filter": bson.M{}, "update": bson.M{"$set": bson.M{"a": 1}}, "upsert": true}},
+			expected:  &mongo.UpdateManyModel{},
+		},
+		{
+			name:      "deleteMany without filter",
+			operation: map[string]any{"deleteMany": map[string]any{}},
+			expected:  &mongo.DeleteManyModel{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := bulkWriteModel(tt.operation)
+			if err != nil {
+				t.Fatalf("bulkWriteModel() error = %v", err)
+			}
+			if reflect.TypeOf(model) != reflect.TypeOf(tt.expected) {
+				t.Errorf("bulkWriteModel() = %T, want %T", model, tt.expected)
+			}
+		})
+	}
+
+	model, err := bulkWriteModel(map[string]any{"updateMany": map[string]any{"update": bson.M{}, "upsert": true}})
+	if err != nil {
+		t.Fatalf("bulkWriteModel() error = %v", err)
+	}
+	if upsert := model.(*mongo.UpdateManyModel).Upsert; upsert == nil || !*upsert {
+		t.Errorf("bulkWriteModel() did not set upsert")
+	}
+}
+
+func Test_BulkWriteModel_Errors(t *testing.T) {
+	operations := []any{
+		map[string]any{},
+		map[string]any{"insertOne": map[string]any{}},
+		map[string]any{"updateOne": map[string]any{"filter": bson.M{}}},
+		map[string]any{"replaceOne": map[string]any{"filter": bson.M{}}},
+		map[string]any{"upsertOne": map[string]any{}},
+		map[string]any{"insertOne": "not a document"},
+		"not a document",
+	}
+
+	for _, operation := range operations {
+		if _, err := bulkWriteModel(operation); err == nil {
+			t.Errorf("bulkWriteModel(%v) error = nil, want error", operation)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Cursor reads the documents of a find or aggregate a batch at a time, so
+// collections too large to hold in memory can be processed document by
+// document. The size of each batch is set with the batchSize option.
+type Cursor struct {
+	ctx    context.Context
+	cursor *mongo.Cursor
+	next   map[string]any
+	done   bool
+}
+
+// Cursor runs find and returns a cursor over the matching documents.
+func (c *Collection) Cursor(filter any, opts ...any) *Cursor {
+	cur, err := c.driver.database.Collection(c.name).Find(c.ctx, filterOrEmpty(filter), decodeOptions[FindOptions]("cursor", opts)...)
+	if err != nil {
+		panic(err)
+	}
+	return &Cursor{ctx: c.ctx, cursor: cur}
+}
+
+// AggregateCursor runs the pipeline and returns a cursor over the resulting
+// documents.
+func (c *Collection) AggregateCursor(pipeline []any, opts ...any) *Cursor {
+	cur, err := c.driver.database.Collection(c.name).Aggregate(c.ctx, pipeline, decodeOptions[AggregateOptions]("aggregateCursor", opts)...)
+	if err != nil {
+		panic(err)
+	}
+	return &Cursor{ctx: c.ctx, cursor: cur}
+}
+
+// ForEach calls fn with each document matching the filter. Returning false
+// from fn stops the iteration.
+func (c *Collection) ForEach(filter any, fn func(doc map[string]any) any, opts ...any) {
+	c.Cursor(filter, opts...).ForEach(fn)
+}
+
+func (c *Cursor) HasNext() bool {
+	if c.next != nil {
+		return true
+	}
+	if c.done {
+		return false
+	}
+
+	if !c.cursor.Next(c.ctx) {
+		err := c.cursor.Err()
+		c.Close()
+		if err != nil {
+			panic(err)
+		}
+		return false
+	}
+
+	var doc map[string]any
+	if err := c.cursor.Decode(&doc); err != nil {
+		c.Close()
+		panic(err)
+	}
+	c.next = doc
+	return true
+}
+
+// Next returns the next document, or nil when there are no documents left.
+func (c *Cursor) Next() map[string]any {
+	if !c.HasNext() {
+		return nil
+	}
+	doc := c.next
+	c.next = nil
+	return doc
+}
+
+// ForEach calls fn with each remaining document and then closes the cursor.
+// Returning false from fn stops the iteration.
+func (c *Cursor) ForEach(fn func(doc map[string]any) any) {
+	defer c.Close()
+	for c.HasNext() {
+		if proceed, ok := fn(c.Next()).(bool); ok && !proceed {
+			return
+		}
+	}
+}
+
+func (c *Cursor) ToArray() []map[string]any {
+	results := []map[string]any{}
+	for c.HasNext() {
+		results = append(results, c.Next())
+	}
+	return results
+}
+
+func (c *Cursor) Close() {
+	c.next = nil
+	if c.done {
+		return
+	}
+	c.done = true
+	c.cursor.Close(c.ctx)
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_Cursor_ReadsAllDocuments(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	for i := 0; i < 7; i += 1 {
+		testColl.InsertOne(ctx, bson.M{"value": i})
+	}
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	cursor := coll.Cursor(bson.M{}, bson.D{{Key: "batchSize", Value: int64(2)}, {Key: "sort", Value: bson.D{{Key: "value", Value: 1}}}})
+	count := 0
+	for cursor.HasNext() {
+		doc := cursor.Next()
+		if doc["value"] != int32(count) {
+			t.Errorf("document %d has value %v, want %d", count, doc["value"], count)
+		}
+		count += 1
+	}
+
+	if count != 7 {
+		t.Errorf("cursor returned %d documents, want 7", count)
+	}
+	if cursor.Next() != nil {
+		t.Error("Next() after the last document should return nil")
+	}
+}
+
+func Test_Collection_ForEach_Stop(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	for i := 0; i < 5; i += 1 {
+		testColl.InsertOne(ctx, bson.M{"value": i})
+	}
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	count := 0
+	coll.ForEach(bson.M{}, func(doc map[string]any) any {
+		count += 1
+		return count < 2
+	})
+
+	if count != 2 {
+		t.Errorf("callback called %d times, want 2", count)
+	}
+}
+
+func Test_Collection_AggregateCursor_Success(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": 1})
+	testColl.InsertOne(ctx, bson.M{"value": 2})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.Collection("test")
+
+	results := coll.AggregateCursor([]any{bson.M{"$match": bson.M{"value": 2}}}).ToArray()
+	if len(results) != 1 {
+		t.Errorf("AggregateCursor() returned %d documents, want 1", len(results))
+	}
+}
@@ -0,0 +1,192 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/telemetryos/graviton/diagnostics"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const DIAGNOSE_PROBE_COLLECTION = "graviton-doctor-probe"
+
+func (d *Driver) Diagnose(ctx context.Context) []*diagnostics.Check {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(d.config.ConnectionUrl).SetRegistry(newRegistry()))
+	if err == nil {
+		err = client.Ping(ctx, nil)
+	}
+	if err != nil {
+		return []*diagnostics.Check{
+			diagnostics.Fail("connection", err.Error(), "Check connection_url and that the server is reachable"),
+		}
+	}
+	defer client.Disconnect(ctx)
+
+	checks := []*diagnostics.Check{diagnostics.Pass("connection", "connected")}
+
+	if d.config.DatabaseName == "" {
+		checks = append(checks, diagnostics.Fail("database", "no database_name configured", "Set database_name for this database"))
+		return checks
+	}
+	database := client.Database(d.config.DatabaseName)
+
+	var buildInfo struct {
+		Version string `bson:"version"`
+	}
+	versionKnown := false
+	if err := database.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+		checks = append(checks, diagnostics.Warn("server version", err.Error(), "The server version could not be determined"))
+	} else if version, err := ParseServerVersion(buildInfo.Version); err != nil {
+		checks = append(checks, diagnostics.Warn("server version", err.Error(), "The server version could not be determined"))
+	} else {
+		versionKnown = true
+		if !version.AtLeast(MIN_SERVER_VERSION) {
+			checks = append(checks, diagnostics.Fail("server version", "MongoDB "+buildInfo.Version, "Graviton requires MongoDB 4.0 or later"))
+		} else {
+			checks = append(checks, diagnostics.Pass("server version", "MongoDB "+buildInfo.Version))
+		}
+	}
+
+	var hello helloResult
+	supportsTransactions := false
+	if err := database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		checks = append(checks, diagnostics.Warn("topology", err.Error(), "Server metadata could not be determined"))
+	} else {
+		switch {
+		case hello.Secondary || !hello.IsWritablePrimary:
+			checks = append(checks, diagnostics.Fail("read-only", "server is a secondary", "Point connection_url at the replica set rather than a secondary member"))
+		default:
+			checks = append(checks, diagnostics.Pass("read-only", "server accepts writes"))
+		}
+
+		switch hello.topology() {
+		case TopologyReplicaSet:
+			supportsTransactions = true
+			checks = append(checks, diagnostics.Pass("topology", "replica set `"+hello.SetName+"`"))
+		case TopologySharded:
+			supportsTransactions = true
+			checks = append(checks, diagnostics.Pass("topology", "sharded cluster"))
+		default:
+			if !d.config.TransactionsEnabled() {
+				checks = append(checks, diagnostics.Pass("topology", "standalone server"))
+			} else {
+				checks = append(checks, diagnostics.Fail(
+					"topology",
+					"standalone server, transactions are not supported",
+					"Run MongoDB as a replica set, or set `transactions = false` to run migrations without transactions",
+				))
+			}
+		}
+
+		if versionKnown {
+			checks = append(checks, diagnoseFeatures(buildInfo.Version, &hello, d.config.TransactionsEnabled()))
+		}
+	}
+
+	for _, collectionName := range []string{MIGRATIONS_COLLECTION, SEEDS_COLLECTION} {
+		checks = append(checks, diagnoseTrackingCollection(ctx, database, collectionName))
+	}
+	checks = append(checks, diagnoseWritePermission(ctx, database))
+	switch {
+	case !d.config.TransactionsEnabled():
+		checks = append(checks, diagnostics.Warn(
+			"transactions",
+			"disabled by `transactions = false`",
+			"Migrations are not atomic, a failure can leave one partially applied",
+		))
+	case supportsTransactions:
+		checks = append(checks, diagnoseTransactions(ctx, client, database))
+	}
+
+	return checks
+}
+
+func diagnoseFeatures(version string, hello *helloResult, transactionsEnabled bool) *diagnostics.Check {
+	server, err := newServer(version, hello, transactionsEnabled)
+	if err != nil {
+		return diagnostics.Warn("features", err.Error(), "")
+	}
+
+	available := []string{}
+	missing := []string{}
+	for _, feature := range []struct {
+		name      string
+		available bool
+	}{
+		{"transactions", server.Transactions},
+		{"$merge", server.Merge},
+		{"time-series collections", server.TimeSeries},
+		{"clustered collections", server.ClusteredIndexes},
+	} {
+		if feature.available {
+			available = append(available, feature.name)
+		} else {
+			missing = append(missing, feature.name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return diagnostics.Pass("features", strings.Join(available, ", "))
+	}
+	if len(available) == 0 {
+		return diagnostics.Pass("features", "unavailable: "+strings.Join(missing, ", "))
+	}
+	return diagnostics.Pass("features", strings.Join(available, ", ")+"; unavailable: "+strings.Join(missing, ", "))
+}
+
+func diagnoseTrackingCollection(ctx context.Context, database *mongo.Database, collectionName string) *diagnostics.Check {
+	collectionNames, err := database.ListCollectionNames(ctx, bson.D{{Key: "name", Value: collectionName}})
+	if err != nil {
+		return diagnostics.Fail(collectionName, err.Error(), "Grant listCollections on the database to the user Graviton connects as")
+	}
+	if len(collectionNames) == 0 {
+		return diagnostics.Pass(collectionName, "does not exist yet and will be created on first use")
+	}
+
+	if err := database.Collection(collectionName).FindOne(ctx, bson.D{}).Err(); err != nil && err != mongo.ErrNoDocuments {
+		return diagnostics.Fail(collectionName, err.Error(), "Grant read and write access to "+collectionName+" to the user Graviton connects as")
+	}
+
+	return diagnostics.Pass(collectionName, "readable")
+}
+
+func diagnoseWritePermission(ctx context.Context, database *mongo.Database) *diagnostics.Check {
+	collection := database.Collection(DIAGNOSE_PROBE_COLLECTION)
+	if _, err := collection.InsertOne(ctx, bson.D{{Key: "probe", Value: true}}); err != nil {
+		return diagnostics.Fail("write permission", "cannot insert documents: "+err.Error(), "Grant the readWrite role on the database to the user Graviton connects as")
+	}
+	if err := collection.Drop(ctx); err != nil {
+		return diagnostics.Fail("write permission", "cannot drop collections: "+err.Error(), "Grant the dbAdmin role on the database to the user Graviton connects as")
+	}
+
+	return diagnostics.Pass("write permission", "can insert documents and drop collections")
+}
+
+func diagnoseTransactions(ctx context.Context, client *mongo.Client, database *mongo.Database) *diagnostics.Check {
+	session, err := client.StartSession()
+	if err != nil {
+		return diagnostics.Fail("transactions", err.Error(), "")
+	}
+	defer session.EndSession(ctx)
+
+	err = mongo.WithSession(ctx, session, func(sessCtx mongo.SessionContext) error {
+		if err := session.StartTransaction(); err != nil {
+			return err
+		}
+		defer session.AbortTransaction(sessCtx)
+
+		err := database.Collection(MIGRATIONS_COLLECTION).FindOne(sessCtx, bson.D{}).Err()
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return diagnostics.Fail("transactions", err.Error(), "Check the server supports multi-document transactions")
+	}
+
+	return diagnostics.Pass("transactions", "supported")
+}
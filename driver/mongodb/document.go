@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NewDocument builds an ordered document from a JavaScript object. Key order
+// is significant for index keys, sorts and aggregation stages.
+func (d *Driver) NewDocument(keys []string, values []any) any {
+	doc := make(bson.D, len(keys))
+	for i, key := range keys {
+		doc[i] = bson.E{Key: key, Value: values[i]}
+	}
+	return doc
+}
+
+// toDocument converts the document types passed in from migrations into an
+// ordered document.
+func toDocument(val any) (bson.D, error) {
+	switch val := val.(type) {
+	case bson.D:
+		return val, nil
+	case bson.M:
+		return mapToDocument(val), nil
+	case map[string]any:
+		return mapToDocument(val), nil
+	default:
+		return nil, fmt.Errorf("expected a document, got %T", val)
+	}
+}
+
+func mapToDocument(val map[string]any) bson.D {
+	doc := bson.D{}
+	for key, value := range val {
+		doc = append(doc, bson.E{Key: key, Value: value})
+	}
+	return doc
+}
+
+func documentField(doc bson.D, key string) (any, bool) {
+	for _, elem := range doc {
+		if elem.Key == key {
+			return elem.Value, true
+		}
+	}
+	return nil, false
+}
+
+// filterOrEmpty allows the filter to be omitted for read operations, matching
+// every document.
+func filterOrEmpty(filter any) any {
+	if filter == nil {
+		return bson.D{}
+	}
+	return filter
+}
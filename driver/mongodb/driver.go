@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/telemetryos/graviton/config"
 	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
@@ -15,6 +16,14 @@ import (
 )
 
 const MIGRATIONS_COLLECTION = "graviton-migrations"
+const SEEDS_COLLECTION = "graviton-seeds"
+const BACKFILLS_COLLECTION = "graviton-backfills"
+
+type backfillCheckpoint struct {
+	Filename   string    `bson:"filename"`
+	Checkpoint string    `bson:"checkpoint"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
 
 type Options struct {
 	URI      string
@@ -22,13 +31,20 @@ type Options struct {
 }
 
 type driverRuntimeData struct {
-	objectIdCtorVal goja.Value
+	objectIdCtorVal   goja.Value
+	decimal128CtorVal goja.Value
+	longCtorVal       goja.Value
+	doubleCtorVal     goja.Value
+	uuidCtorVal       goja.Value
+	binaryCtorVal     goja.Value
+	timestampCtorVal  goja.Value
 }
 
 type Driver struct {
 	config      *config.DatabaseConfig
 	client      *mongo.Client
 	database    *mongo.Database
+	server      *Server
 	runtimeData map[*goja.Runtime]*driverRuntimeData
 }
 
@@ -41,7 +57,8 @@ func New(conf *config.DatabaseConfig) *Driver {
 
 func (d *Driver) Connect(ctx context.Context) error {
 	clientOptions := options.Client().
-		ApplyURI(d.config.ConnectionUrl)
+		ApplyURI(d.config.ConnectionUrl).
+		SetRegistry(newRegistry())
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -58,29 +75,36 @@ func (d *Driver) Connect(ctx context.Context) error {
 	if err := result.Decode(&buildInfo); err != nil {
 		return fmt.Errorf("failed to get MongoDB server version: %w", err)
 	}
-	if buildInfo.Version < "4.0" {
-		return errors.New("MongoDB version must be at least 4.0")
-	}
 
-	var helloDB struct {
-		IsWritablePrimary bool `bson:"isWritablePrimary"`
-		IsWritable        bool `bson:"isWritable"`
-		Secondary         bool `bson:"secondary"`
-		HasReplica        bool `bson:"hasReplica"`
-	}
+	var hello helloResult
 	result = d.database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}})
-	if err := result.Decode(&helloDB); err != nil {
+	if err := result.Decode(&hello); err != nil {
 		return fmt.Errorf("failed to get MongoDB server metadata: %w", err)
 	}
-	if !helloDB.IsWritable && !helloDB.IsWritablePrimary {
-		return errors.New("MongoDB server is not writable")
+
+	server, err := newServer(buildInfo.Version, &hello, d.config.TransactionsEnabled())
+	if err != nil {
+		return err
+	}
+	if !server.parsedVersion.AtLeast(MIN_SERVER_VERSION) {
+		return fmt.Errorf("MongoDB version must be at least %d.%d, the server is MongoDB %s", MIN_SERVER_VERSION.Major, MIN_SERVER_VERSION.Minor, buildInfo.Version)
 	}
-	if helloDB.Secondary {
+	if hello.Secondary {
 		return errors.New("Graviton cannot write to a secondary MongoDB server")
 	}
-	if !helloDB.HasReplica && !helloDB.IsWritablePrimary {
-		return errors.New("MongoDB server must be part of a replica set as transactions are required for Graviton")
+	if !hello.IsWritablePrimary {
+		return errors.New("MongoDB server is not writable")
+	}
+	if server.Topology == TopologyStandalone && d.config.TransactionsEnabled() {
+		return errors.New(
+			"MongoDB server is standalone, but transactions require a replica set or sharded cluster. " +
+				"Set `transactions = false` for this database to run migrations without transactions",
+		)
+	}
+	if !server.Transactions && d.config.TransactionsEnabled() {
+		return errors.New("MongoDB server does not support transactions: " + server.transactionsReason)
 	}
+	d.server = server
 
 	return nil
 }
@@ -92,19 +116,39 @@ func (d *Driver) Disconnect(ctx context.Context) error {
 	return d.client.Disconnect(ctx)
 }
 
+// TransactionsEnabled reports whether migrations run inside transactions. It
+// is false when the config sets `transactions = false`, which allows standalone
+// servers.
+func (d *Driver) TransactionsEnabled() bool {
+	return d.config.TransactionsEnabled()
+}
+
 func (d *Driver) Handle(ctx context.Context) any {
-	return &MongoHandle{ctx: ctx, driver: d}
+	return &MongoHandle{ctx: ctx, driver: d, Server: d.server}
 }
 
 func (d *Driver) Init(ctx context.Context, runtime *goja.Runtime) {
 	d.runtimeData[runtime] = &driverRuntimeData{
-		objectIdCtorVal: runtime.ToValue(JSObjectIdCtor),
+		objectIdCtorVal:   runtime.ToValue(JSObjectIdCtor),
+		decimal128CtorVal: runtime.ToValue(JSDecimal128Ctor),
+		longCtorVal:       runtime.ToValue(JSLongCtor),
+		doubleCtorVal:     runtime.ToValue(JSDoubleCtor),
+		uuidCtorVal:       runtime.ToValue(JSUUIDCtor),
+		binaryCtorVal:     runtime.ToValue(JSBinaryCtor),
+		timestampCtorVal:  runtime.ToValue(JSTimestampCtor),
 	}
 }
 
 func (d *Driver) Globals(ctx context.Context, runtime *goja.Runtime) map[string]any {
+	rtData := d.runtimeData[runtime]
 	globals := map[string]any{}
-	globals["ObjectId"] = d.runtimeData[runtime].objectIdCtorVal
+	globals["ObjectId"] = rtData.objectIdCtorVal
+	globals["Decimal128"] = rtData.decimal128CtorVal
+	globals["Long"] = rtData.longCtorVal
+	globals["Double"] = rtData.doubleCtorVal
+	globals["UUID"] = rtData.uuidCtorVal
+	globals["Binary"] = rtData.binaryCtorVal
+	globals["Timestamp"] = rtData.timestampCtorVal
 	return globals
 }
 
@@ -113,17 +157,41 @@ func (d *Driver) MaybeFromJSValue(ctx context.Context, jsvm *goja.Runtime, val g
 	if rtData == nil {
 		return nil, false
 	}
-	if IsObjectId(jsvm, val, rtData.objectIdCtorVal) {
-		return ObjectIdFromJSValue(jsvm, val), true
+	return bsonFromJSValue(jsvm, rtData, val)
+}
+
+func (d *Driver) MaybeIntoJSValue(ctx context.Context, jsvm *goja.Runtime, value any, intoJs func(any) goja.Value) (goja.Value, bool) {
+	if err, ok := value.(error); ok {
+		return databaseErrorIntoJSValue(jsvm, err)
+	}
+	rtData := d.runtimeData[jsvm]
+	if rtData == nil {
+		return nil, false
 	}
-	return nil, false
+	return bsonIntoJSValue(jsvm, rtData, value, intoJs)
 }
 
 func (d *Driver) GetAppliedMigrationsMetadata(ctx context.Context) ([]*migrationsmeta.MigrationMetadata, error) {
+	return d.getMetadata(ctx, d.getMigrationsCollection())
+}
+
+func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMetadata []*migrationsmeta.MigrationMetadata) error {
+	return d.setMetadata(ctx, d.getMigrationsCollection(), migrationsMetadata)
+}
+
+func (d *Driver) GetAppliedSeedsMetadata(ctx context.Context) ([]*migrationsmeta.MigrationMetadata, error) {
+	return d.getMetadata(ctx, d.getSeedsCollection())
+}
+
+func (d *Driver) SetAppliedSeedsMetadata(ctx context.Context, seedsMetadata []*migrationsmeta.MigrationMetadata) error {
+	return d.setMetadata(ctx, d.getSeedsCollection(), seedsMetadata)
+}
+
+func (d *Driver) getMetadata(ctx context.Context, collection *mongo.Collection) ([]*migrationsmeta.MigrationMetadata, error) {
 	findOptions := options.Find().SetSort(bson.D{
 		{Key: "filename", Value: 1},
 	})
-	cur, err := d.getMigrationsCollection().Find(ctx, bson.M{}, findOptions)
+	cur, err := collection.Find(ctx, bson.M{}, findOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -137,9 +205,8 @@ func (d *Driver) GetAppliedMigrationsMetadata(ctx context.Context) ([]*migration
 	return migrationsMetadata, nil
 }
 
-func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMetadata []*migrationsmeta.MigrationMetadata) error {
-	migrationsCollection := d.getMigrationsCollection()
-	_, err := migrationsCollection.DeleteMany(ctx, bson.M{})
+func (d *Driver) setMetadata(ctx context.Context, collection *mongo.Collection, migrationsMetadata []*migrationsmeta.MigrationMetadata) error {
+	_, err := collection.DeleteMany(ctx, bson.M{})
 	if err != nil {
 		return err
 	}
@@ -152,11 +219,44 @@ func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMet
 	for _, migrationMetadata := range migrationsMetadata {
 		documents = append(documents, migrationMetadata)
 	}
-	_, err = migrationsCollection.InsertMany(ctx, documents)
+	_, err = collection.InsertMany(ctx, documents)
+	return err
+}
+
+// GetBackfillCheckpoint returns the checkpoint of the migration's backfill.
+// found is false when no backfill is in progress for the migration.
+func (d *Driver) GetBackfillCheckpoint(ctx context.Context, filename string) (checkpoint string, found bool, err error) {
+	var doc backfillCheckpoint
+	err = d.getBackfillsCollection().FindOne(ctx, bson.M{"filename": filename}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return doc.Checkpoint, true, nil
+}
+
+func (d *Driver) SetBackfillCheckpoint(ctx context.Context, filename string, checkpoint string) error {
+	_, err := d.getBackfillsCollection().ReplaceOne(
+		ctx,
+		bson.M{"filename": filename},
+		&backfillCheckpoint{Filename: filename, Checkpoint: checkpoint, UpdatedAt: time.Now()},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+func (d *Driver) DeleteBackfillCheckpoint(ctx context.Context, filename string) error {
+	_, err := d.getBackfillsCollection().DeleteOne(ctx, bson.M{"filename": filename})
 	return err
 }
 
 func (d *Driver) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if !d.config.TransactionsEnabled() {
+		return withoutTransaction(ctx, fn)
+	}
+
 	session, err := d.client.StartSession()
 	if err != nil {
 		return err
@@ -185,6 +285,30 @@ func (d *Driver) WithTransaction(ctx context.Context, fn func(context.Context) e
 	return err
 }
 
+// withoutTransaction runs fn directly against the database for when
+// transactions are disabled. Writes made before an error are kept.
+func withoutTransaction(ctx context.Context, fn func(context.Context) error) (returnErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				returnErr = e
+			} else {
+				returnErr = fmt.Errorf("panic in migration: %v", r)
+			}
+		}
+	}()
+
+	return fn(ctx)
+}
+
 func (d *Driver) getMigrationsCollection() *mongo.Collection {
 	return d.database.Collection(MIGRATIONS_COLLECTION)
 }
+
+func (d *Driver) getSeedsCollection() *mongo.Collection {
+	return d.database.Collection(SEEDS_COLLECTION)
+}
+
+func (d *Driver) getBackfillsCollection() *mongo.Collection {
+	return d.database.Collection(BACKFILLS_COLLECTION)
+}
@@ -10,6 +10,7 @@ import (
 	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
@@ -257,3 +258,67 @@ func Test_Driver_GetAppliedMigrationsMetadata(t *testing.T) {
 		t.Errorf("GetAppliedMigrationsMetadata() returned %d migrations, want 0 (empty database)", len(retrieved))
 	}
 }
+
+func Test_Driver_BackfillCheckpoint(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	filename := "20240101000000-backfill.migration.ts"
+
+	_, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+	if err != nil {
+		t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+	}
+	if found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint before one was set")
+	}
+
+	for _, checkpoint := range []string{"", "42"} {
+		if err := drv.SetBackfillCheckpoint(ctx, filename, checkpoint); err != nil {
+			t.Fatalf("SetBackfillCheckpoint() error = %v", err)
+		}
+
+		retrieved, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+		if err != nil {
+			t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+		}
+		if !found || retrieved != checkpoint {
+			t.Errorf("GetBackfillCheckpoint() = %q, %v, want %q, true", retrieved, found, checkpoint)
+		}
+	}
+
+	if err := drv.DeleteBackfillCheckpoint(ctx, filename); err != nil {
+		t.Fatalf("DeleteBackfillCheckpoint() error = %v", err)
+	}
+	if _, found, _ := drv.GetBackfillCheckpoint(ctx, filename); found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint after it was deleted")
+	}
+}
+
+func Test_Driver_WithTransaction_Disabled(t *testing.T) {
+	transactions := false
+	drv := New(&config.DatabaseConfig{Transactions: &transactions})
+	ctx := context.Background()
+
+	if drv.TransactionsEnabled() {
+		t.Fatal("TransactionsEnabled() = true, want false when the config disables transactions")
+	}
+
+	called := false
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		called = true
+		if mongo.SessionFromContext(txCtx) != nil {
+			t.Error("WithTransaction() started a session with transactions disabled")
+		}
+		return nil
+	})
+	if err != nil || !called {
+		t.Errorf("WithTransaction() error = %v, called = %v, want nil, true", err, called)
+	}
+
+	err = drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		panic(errors.New("panic error"))
+	})
+	if err == nil || err.Error() != "panic error" {
+		t.Errorf("WithTransaction() error = %v, want the recovered panic", err)
+	}
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"errors"
+
+	"github.com/dop251/goja"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// databaseErrorIntoJSValue converts an error returned by the server into a
+// JavaScript Error named DatabaseError carrying its code and labels, so
+// migrations can catch specific errors. When writes failed, the code is the
+// first write error's, so a duplicate key is caught with err.code === 11000.
+// Other errors are not converted.
+func databaseErrorIntoJSValue(jsvm *goja.Runtime, err error) (goja.Value, bool) {
+	var code int
+	var codeName string
+	var labels []string
+	var writeErrors []mongo.WriteError
+	var writeConcernErr *mongo.WriteConcernError
+
+	var commandErr mongo.CommandError
+	var writeErr mongo.WriteException
+	var bulkWriteErr mongo.BulkWriteException
+	switch {
+	case errors.As(err, &commandErr):
+		code, codeName, labels = int(commandErr.Code), commandErr.Name, commandErr.Labels
+	case errors.As(err, &writeErr):
+		writeErrors, writeConcernErr, labels = writeErr.WriteErrors, writeErr.WriteConcernError, writeErr.Labels
+	case errors.As(err, &bulkWriteErr):
+		for _, bulkWriteError := range bulkWriteErr.WriteErrors {
+			writeErrors = append(writeErrors, bulkWriteError.WriteError)
+		}
+		writeConcernErr, labels = bulkWriteErr.WriteConcernError, bulkWriteErr.Labels
+	default:
+		return nil, false
+	}
+	if len(writeErrors) != 0 {
+		code = writeErrors[0].Code
+	} else if writeConcernErr != nil {
+		code, codeName = writeConcernErr.Code, writeConcernErr.Name
+	}
+
+	jsErr := jsvm.NewGoError(err)
+	jsErr.Set("name", "DatabaseError")
+	jsErr.Set("code", code)
+	if codeName != "" {
+		jsErr.Set("codeName", codeName)
+	}
+
+	labelVals := make([]any, len(labels))
+	for i, label := range labels {
+		labelVals[i] = label
+	}
+	jsErr.Set("labels", jsvm.NewArray(labelVals...))
+
+	writeErrorVals := make([]any, len(writeErrors))
+	for i, writeError := range writeErrors {
+		writeErrorVal := jsvm.NewObject()
+		writeErrorVal.Set("index", writeError.Index)
+		writeErrorVal.Set("code", writeError.Code)
+		writeErrorVal.Set("message", writeError.Message)
+		writeErrorVals[i] = writeErrorVal
+	}
+	jsErr.Set("writeErrors", jsvm.NewArray(writeErrorVals...))
+
+	return jsErr, true
+}
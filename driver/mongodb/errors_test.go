@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dop251/goja"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Test_DatabaseErrorIntoJSValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected map[string]any
+	}{
+		{
+			"command error",
+			mongo.CommandError{Code: 85, Name: "IndexOptionsConflict", Message: "index already exists", Labels: []string{"TransientTransactionError"}},
+			map[string]any{
+				"err.code":             int64(85),
+				"err.codeName":         "IndexOptionsConflict",
+				"err.labels":           []any{"TransientTransactionError"},
+				"err.writeErrors":      []any{},
+				"err instanceof Error": true,
+			},
+		},
+		{
+			"write exception",
+			mongo.WriteException{WriteErrors: mongo.WriteErrors{{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}}},
+			map[string]any{
+				"err.code":        int64(11000),
+				"err.codeName":    nil,
+				"err.labels":      []any{},
+				"err.writeErrors": []any{map[string]any{"index": int64(0), "code": int64(11000), "message": "E11000 duplicate key error"}},
+			},
+		},
+		{
+			"bulk write exception",
+			mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: 2, Code: 11000, Message: "E11000 duplicate key error"}}}},
+			map[string]any{
+				"err.code":        int64(11000),
+				"err.writeErrors": []any{map[string]any{"index": int64(2), "code": int64(11000), "message": "E11000 duplicate key error"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jsvm := goja.New()
+			val, ok := databaseErrorIntoJSValue(jsvm, test.err)
+			if !ok {
+				t.Fatal("databaseErrorIntoJSValue() ok = false, want true")
+			}
+			jsvm.Set("err", val)
+
+			if name, _ := jsvm.RunString("err.name"); name.String() != "DatabaseError" {
+				t.Errorf("err.name = %s, want DatabaseError", name)
+			}
+			for src, want := range test.expected {
+				got, err := jsvm.RunString(src)
+				if err != nil {
+					t.Fatalf("RunString(%q) error = %v", src, err)
+				}
+				if !reflect.DeepEqual(got.Export(), want) {
+					t.Errorf("%s = %#v, want %#v", src, got.Export(), want)
+				}
+			}
+		})
+	}
+
+	if _, ok := databaseErrorIntoJSValue(goja.New(), errors.New("other")); ok {
+		t.Error("databaseErrorIntoJSValue() converted an error that is not from the database")
+	}
+}
@@ -2,13 +2,197 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// transactionalCommands lists the commands MongoDB allows inside a
+// multi-document transaction. Any other command is run outside of the
+// transaction, as the server would otherwise reject it, and is not rolled
+// back if the migration fails.
+var transactionalCommands = map[string]bool{
+	"aggregate":     true,
+	"create":        true,
+	"delete":        true,
+	"distinct":      true,
+	"find":          true,
+	"findAndModify": true,
+	"getMore":       true,
+	"insert":        true,
+	"update":        true,
+}
+
+// commandContext returns the context a command should run with. The session,
+// and so the transaction, is only kept for commands the server allows within
+// a transaction.
+func commandContext(ctx context.Context, commandName string) context.Context {
+	if transactionalCommands[commandName] {
+		return ctx
+	}
+	return mongo.NewSessionContext(ctx, nil)
+}
+
 type MongoHandle struct {
 	ctx    context.Context
 	driver *Driver
+
+	// Server describes the connected server, so migrations can check for
+	// features with db.server.
+	Server *Server
 }
 
 func (h *MongoHandle) Collection(name string) *Collection {
 	return &Collection{ctx: h.ctx, driver: h.driver, name: name}
 }
+
+// CreateCollection creates a collection explicitly. Options are passed to the
+// create command as is, so capped, timeseries, clusteredIndex, collation,
+// validator and the other create options are supported. Capped, time-series
+// and clustered collections cannot be created within a transaction, nor can
+// any collection before MongoDB 4.4, so those are created outside of it and
+// are not dropped if the migration fails.
+func (h *MongoHandle) CreateCollection(name string, options ...any) *Collection {
+	command := bson.D{{Key: "create", Value: name}}
+	h.runCommand(appendCommandOptions(command, options))
+	return h.Collection(name)
+}
+
+// CreateView creates a read-only view of the source collection defined by the
+// aggregation pipeline.
+func (h *MongoHandle) CreateView(name string, source string, pipeline []any, options ...any) *Collection {
+	command := bson.D{
+		{Key: "create", Value: name},
+		{Key: "viewOn", Value: source},
+		{Key: "pipeline", Value: pipeline},
+	}
+	h.runCommandWithContext(commandContext(h.ctx, "createView"), appendCommandOptions(command, options))
+	return h.Collection(name)
+}
+
+// DropCollection drops a collection or view. Dropping a collection that does
+// not exist is not an error.
+func (h *MongoHandle) DropCollection(name string) {
+	err := h.driver.database.Collection(name).Drop(commandContext(h.ctx, "drop"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ListCollections returns the specifications of the collections and views in
+// the database, optionally filtered.
+func (h *MongoHandle) ListCollections(filter ...any) []map[string]any {
+	var listFilter any = bson.D{}
+	if len(filter) > 0 {
+		listFilter = filter[0]
+	}
+
+	ctx := commandContext(h.ctx, "listCollections")
+	cur, err := h.driver.database.ListCollections(ctx, listFilter)
+	if err != nil {
+		panic(err)
+	}
+
+	results := []map[string]any{}
+	if err := cur.All(ctx, &results); err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// ListCollectionNames returns the names of the collections and views in the
+// database in order, apart from Graviton's own.
+func (h *MongoHandle) ListCollectionNames() []string {
+	names, err := h.driver.database.ListCollectionNames(commandContext(h.ctx, "listCollections"), bson.D{})
+	if err != nil {
+		panic(err)
+	}
+	names = slices.DeleteFunc(names, isGravitonCollection)
+	slices.Sort(names)
+	return names
+}
+
+// CollectionExists reports whether the database has a collection or view with
+// the given name.
+func (h *MongoHandle) CollectionExists(name string) bool {
+	names, err := h.driver.database.ListCollectionNames(commandContext(h.ctx, "listCollections"), bson.D{{Key: "name", Value: name}})
+	if err != nil {
+		panic(err)
+	}
+	return len(names) > 0
+}
+
+// CollMod modifies a collection, for example to set a $jsonSchema validator
+// with `{ validator: { $jsonSchema: {...} } }`.
+func (h *MongoHandle) CollMod(name string, options any) map[string]any {
+	command := bson.D{{Key: "collMod", Value: name}}
+	return h.runCommand(appendCommandOptions(command, []any{options}))
+}
+
+// RunCommand runs a database command. Commands that are allowed within a
+// transaction join the migration's transaction.
+func (h *MongoHandle) RunCommand(command any) map[string]any {
+	return h.runCommand(command)
+}
+
+func (h *MongoHandle) runCommand(command any) map[string]any {
+	commandDoc, err := toDocument(command)
+	if err != nil {
+		panic(err)
+	}
+	if len(commandDoc) == 0 {
+		panic(errors.New("command must not be empty"))
+	}
+	return h.runCommandWithContext(h.runCommandContext(commandDoc), commandDoc)
+}
+
+// runCommandContext returns the context the command should run with, which
+// keeps the transaction for the create commands the server allows within one.
+func (h *MongoHandle) runCommandContext(command bson.D) context.Context {
+	if command[0].Key == "create" && !h.createInTransaction(command) {
+		return mongo.NewSessionContext(h.ctx, nil)
+	}
+	return commandContext(h.ctx, command[0].Key)
+}
+
+// createInTransaction reports whether a create command can run within a
+// transaction, which MongoDB allows from 4.4 for collections that are not
+// capped, time-series or clustered.
+func (h *MongoHandle) createInTransaction(command bson.D) bool {
+	if h.Server != nil && !h.Server.parsedVersion.AtLeast(ServerVersion{Major: 4, Minor: 4}) {
+		return false
+	}
+	for _, option := range command[1:] {
+		switch option.Key {
+		case "capped":
+			if option.Value == true {
+				return false
+			}
+		case "timeseries", "clusteredIndex":
+			return false
+		}
+	}
+	return true
+}
+
+func (h *MongoHandle) runCommandWithContext(ctx context.Context, command bson.D) map[string]any {
+	var result map[string]any
+	if err := h.driver.database.RunCommand(ctx, command).Decode(&result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func appendCommandOptions(command bson.D, options []any) bson.D {
+	for _, option := range options {
+		optionDoc, err := toDocument(option)
+		if err != nil {
+			panic(err)
+		}
+		command = append(command, optionDoc...)
+	}
+	return command
+}
@@ -0,0 +1,194 @@
+package mongodb
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Test_CommandContext(t *testing.T) {
+	ctx := context.Background()
+
+	if commandContext(ctx, "insert") != ctx {
+		t.Error("commandContext() changed the context for a transactional command")
+	}
+
+	strippedCtx := commandContext(ctx, "drop")
+	if strippedCtx == ctx {
+		t.Error("commandContext() kept the context for a non-transactional command")
+	}
+	if mongo.SessionFromContext(strippedCtx) != nil {
+		t.Error("commandContext() kept the session for a non-transactional command")
+	}
+}
+
+func Test_MongoHandle_CreateCollection_WithOptions(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.CreateCollection("capped", bson.D{
+		{Key: "capped", Value: true},
+		{Key: "size", Value: 4096},
+	})
+	if coll.name != "capped" {
+		t.Errorf("CreateCollection() returned collection %q, want capped", coll.name)
+	}
+
+	specs := handle.ListCollections(bson.D{{Key: "name", Value: "capped"}})
+	if len(specs) != 1 {
+		t.Fatalf("ListCollections() returned %d collections, want 1", len(specs))
+	}
+	options, _ := specs[0]["options"].(map[string]any)
+	if options["capped"] != true {
+		t.Errorf("CreateCollection() options = %v, want capped", options)
+	}
+}
+
+func Test_MongoHandle_CreateCollection_InTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	err := drv.WithTransaction(ctx, func(sessCtx context.Context) error {
+		handle := drv.Handle(sessCtx).(*MongoHandle)
+		handle.CreateCollection("plain")
+		handle.CreateCollection("capped", bson.D{
+			{Key: "capped", Value: true},
+			{Key: "size", Value: 4096},
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v, want the capped collection created outside the transaction", err)
+	}
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	if !handle.CollectionExists("plain") || !handle.CollectionExists("capped") {
+		t.Errorf("ListCollectionNames() = %v, want plain and capped", handle.ListCollectionNames())
+	}
+}
+
+func Test_MongoHandle_CreateInTransaction(t *testing.T) {
+	tests := []struct {
+		version  ServerVersion
+		command  bson.D
+		expected bool
+	}{
+		{ServerVersion{Major: 7}, bson.D{{Key: "create", Value: "c"}}, true},
+		{ServerVersion{Major: 7}, bson.D{{Key: "create", Value: "c"}, {Key: "capped", Value: false}}, true},
+		{ServerVersion{Major: 7}, bson.D{{Key: "create", Value: "c"}, {Key: "capped", Value: true}, {Key: "size", Value: 4096}}, false},
+		{ServerVersion{Major: 7}, bson.D{{Key: "create", Value: "c"}, {Key: "timeseries", Value: bson.D{{Key: "timeField", Value: "t"}}}}, false},
+		{ServerVersion{Major: 7}, bson.D{{Key: "create", Value: "c"}, {Key: "clusteredIndex", Value: bson.D{{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}}}}, false},
+		{ServerVersion{Major: 4, Minor: 4}, bson.D{{Key: "create", Value: "c"}}, true},
+		{ServerVersion{Major: 4, Minor: 2}, bson.D{{Key: "create", Value: "c"}}, false},
+	}
+
+	for _, tt := range tests {
+		handle := &MongoHandle{Server: &Server{parsedVersion: tt.version}}
+		if createInTransaction := handle.createInTransaction(tt.command); createInTransaction != tt.expected {
+			t.Errorf("createInTransaction(%v) on %s = %v, want %v", tt.command, tt.version, createInTransaction, tt.expected)
+		}
+	}
+}
+
+func Test_MongoHandle_DropCollection(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	handle.CreateCollection("test")
+	handle.DropCollection("test")
+	handle.DropCollection("does-not-exist")
+
+	if specs := handle.ListCollections(); len(specs) != 0 {
+		t.Errorf("ListCollections() returned %d collections after DropCollection(), want 0", len(specs))
+	}
+}
+
+func Test_MongoHandle_CollectionExists(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	handle.CreateCollection("users")
+	handle.CreateView("active", "users", []any{})
+	handle.CreateCollection(MIGRATIONS_COLLECTION)
+
+	if !handle.CollectionExists("users") || !handle.CollectionExists("active") || handle.CollectionExists("posts") {
+		t.Errorf("CollectionExists() is wrong for users, active or posts")
+	}
+	if names := handle.ListCollectionNames(); !slices.Equal(names, []string{"active", "users"}) {
+		t.Errorf("ListCollectionNames() = %v, want [active users]", names)
+	}
+}
+
+func Test_MongoHandle_CreateView(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	testColl := drv.database.Collection("test")
+	testColl.InsertOne(ctx, bson.M{"value": 1})
+	testColl.InsertOne(ctx, bson.M{"value": 2})
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	view := handle.CreateView("large", "test", []any{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "value", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	})
+
+	if results := view.Find(bson.M{}); len(results) != 1 {
+		t.Errorf("Find() on view returned %d results, want 1", len(results))
+	}
+}
+
+func Test_MongoHandle_CollMod_Validator(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	coll := handle.CreateCollection("test")
+	handle.CollMod("test", bson.D{{Key: "validator", Value: bson.D{
+		{Key: "$jsonSchema", Value: bson.D{
+			{Key: "bsonType", Value: "object"},
+			{Key: "required", Value: bson.A{"name"}},
+		}},
+	}}})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("InsertOne() should panic when the document fails validation, but did not")
+		}
+	}()
+
+	coll.InsertOne(bson.M{"value": 1})
+}
+
+func Test_MongoHandle_RunCommand(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	result := handle.RunCommand(bson.D{{Key: "ping", Value: 1}})
+	if result["ok"] != 1.0 {
+		t.Errorf("RunCommand() ok = %v, want 1", result["ok"])
+	}
+}
+
+func Test_MongoHandle_WithTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	drv.database.Collection("old").InsertOne(ctx, bson.M{"value": 1})
+
+	err := drv.WithTransaction(ctx, func(sessCtx context.Context) error {
+		handle := drv.Handle(sessCtx).(*MongoHandle)
+		handle.CreateCollection("new").InsertOne(bson.M{"value": 1})
+		handle.DropCollection("old")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	if count := handle.Collection("new").CountDocuments(bson.M{}); count != 1 {
+		t.Errorf("CountDocuments() = %d, want 1", count)
+	}
+	if specs := handle.ListCollections(bson.D{{Key: "name", Value: "old"}}); len(specs) != 0 {
+		t.Errorf("DropCollection() within a transaction did not drop the collection")
+	}
+}
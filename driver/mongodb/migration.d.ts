@@ -1,3 +1,5 @@
+type Document = Record<string, any>;
+
 type InsertManyResult = {
   insertedIDs: string[];
 }
@@ -6,19 +8,258 @@ type InsertOneResult = {
   insertedID: string;
 }
 
+type UpdateResult = {
+  matchedCount: number;
+  modifiedCount: number;
+  upsertedCount: number;
+  upsertedID: any;
+}
+
+type DeleteResult = {
+  deletedCount: number;
+}
+
+type BulkWriteResult = {
+  insertedCount: number;
+  matchedCount: number;
+  modifiedCount: number;
+  deletedCount: number;
+  upsertedCount: number;
+  upsertedIDs: Record<number, any>;
+}
+
+type BulkWriteOperation =
+  | { insertOne: { document: Document } }
+  | { updateOne: { filter: Document; update: Document | Document[]; upsert?: boolean; arrayFilters?: Document[]; hint?: string | Document } }
+  | { updateMany: { filter: Document; update: Document | Document[]; upsert?: boolean; arrayFilters?: Document[]; hint?: string | Document } }
+  | { replaceOne: { filter: Document; replacement: Document; upsert?: boolean; hint?: string | Document } }
+  | { deleteOne: { filter: Document; hint?: string | Document } }
+  | { deleteMany: { filter: Document; hint?: string | Document } };
+
+type IndexKeys = Record<string, 1 | -1 | "text" | "2dsphere" | "hashed">;
+
+type IndexDescription = {
+  v: number;
+  key: IndexKeys;
+  name: string;
+  [option: string]: any;
+}
+
+type Sort = Record<string, 1 | -1 | { $meta: string }>;
+
+type Hint = string | IndexKeys;
+
+type Collation = {
+  locale: string;
+  caseLevel?: boolean;
+  caseFirst?: "upper" | "lower" | "off";
+  strength?: 1 | 2 | 3 | 4 | 5;
+  numericOrdering?: boolean;
+  alternate?: "non-ignorable" | "shifted";
+  maxVariable?: "punct" | "space";
+  normalization?: boolean;
+  backwards?: boolean;
+}
+
+type InsertOneOptions = {
+  bypassDocumentValidation?: boolean;
+  comment?: any;
+}
+
+type InsertManyOptions = InsertOneOptions & {
+  ordered?: boolean;
+}
+
+type FindOptions = {
+  limit?: number;
+  skip?: number;
+  sort?: Sort;
+  projection?: Document;
+  hint?: Hint;
+  collation?: Collation;
+  maxTimeMS?: number;
+  batchSize?: number;
+  allowDiskUse?: boolean;
+  comment?: string;
+  let?: Document;
+}
+
+type FindOneOptions = Omit<FindOptions, "limit" | "allowDiskUse" | "let">;
+
+type UpdateOptions = {
+  upsert?: boolean;
+  arrayFilters?: Document[];
+  hint?: Hint;
+  collation?: Collation;
+  bypassDocumentValidation?: boolean;
+  comment?: any;
+  let?: Document;
+}
+
+type ReplaceOptions = Omit<UpdateOptions, "arrayFilters">;
+
+type DeleteOptions = {
+  hint?: Hint;
+  collation?: Collation;
+  comment?: any;
+  let?: Document;
+}
+
+type FindOneAndUpdateOptions = UpdateOptions & {
+  returnDocument?: "before" | "after";
+  sort?: Sort;
+  projection?: Document;
+  maxTimeMS?: number;
+}
+
+type FindOneAndDeleteOptions = DeleteOptions & {
+  sort?: Sort;
+  projection?: Document;
+  maxTimeMS?: number;
+}
+
+type AggregateOptions = {
+  allowDiskUse?: boolean;
+  batchSize?: number;
+  bypassDocumentValidation?: boolean;
+  collation?: Collation;
+  hint?: Hint;
+  maxTimeMS?: number;
+  comment?: string;
+  let?: Document;
+}
+
+type BulkWriteOptions = {
+  ordered?: boolean;
+  bypassDocumentValidation?: boolean;
+  comment?: any;
+  let?: Document;
+}
+
+type CountOptions = {
+  limit?: number;
+  skip?: number;
+  hint?: Hint;
+  collation?: Collation;
+  maxTimeMS?: number;
+  comment?: string;
+}
+
+type DistinctOptions = {
+  collation?: Collation;
+  maxTimeMS?: number;
+  comment?: any;
+}
+
+type IndexOptions = {
+  name?: string;
+  unique?: boolean;
+  sparse?: boolean;
+  background?: boolean;
+  expireAfterSeconds?: number;
+  partialFilterExpression?: Document;
+  collation?: Collation;
+  hidden?: boolean;
+  weights?: Document;
+  defaultLanguage?: string;
+  languageOverride?: string;
+  wildcardProjection?: Document;
+}
+
+type ListIndexesOptions = {
+  batchSize?: number;
+  maxTimeMS?: number;
+}
+
+type Cursor<T = Document> = {
+  hasNext(): boolean;
+  next(): T | null;
+  forEach(callback: (doc: T) => boolean | void): void;
+  toArray(): T[];
+  close(): void;
+}
+
 type Collection = {
-  insertMany(docs: Record<string, any>[]): InsertManyResult;
-  insertOne(docs: Record<string, any>): InsertOneResult;
-  find<Document = Record<string, any>[]>(filter: Record<string, any>): Document[];
-  findOne<Document = Record<string, any>[]>(filter: Record<string, any>): Document;
-  updateMany(filter: Record<string, any>, update: Record<string, any>): void;
-  updateOne(filter: Record<string, any>, update: Record<string, any>): void;
-  deleteMany(filter: Record<string, any>): void;
-  deleteOne(filter: Record<string, any>): void;
+  insertMany(docs: Document[], options?: InsertManyOptions): InsertManyResult;
+  insertOne(docs: Document, options?: InsertOneOptions): InsertOneResult;
+  find<T = Document>(filter?: Document, options?: FindOptions): T[];
+  findOne<T = Document>(filter?: Document, options?: FindOneOptions): T;
+  cursor<T = Document>(filter?: Document, options?: FindOptions): Cursor<T>;
+  forEach<T = Document>(filter: Document, callback: (doc: T) => boolean | void, options?: FindOptions): void;
+  updateMany(filter: Document, update: Document | Document[], options?: UpdateOptions): UpdateResult;
+  updateOne(filter: Document, update: Document | Document[], options?: UpdateOptions): UpdateResult;
+  replaceOne(filter: Document, replacement: Document, options?: ReplaceOptions): UpdateResult;
+  deleteMany(filter: Document, options?: DeleteOptions): DeleteResult;
+  deleteOne(filter: Document, options?: DeleteOptions): DeleteResult;
+  findOneAndUpdate<T = Document>(filter: Document, update: Document | Document[], options?: FindOneAndUpdateOptions): T | null;
+  findOneAndDelete<T = Document>(filter: Document, options?: FindOneAndDeleteOptions): T | null;
+  aggregate<T = Document>(pipeline: Document[], options?: AggregateOptions): T[];
+  aggregateCursor<T = Document>(pipeline: Document[], options?: AggregateOptions): Cursor<T>;
+  bulkWrite(operations: BulkWriteOperation[], options?: BulkWriteOptions): BulkWriteResult;
+  countDocuments(filter?: Document, options?: CountOptions): number;
+  distinct<T = any>(fieldName: string, filter?: Document, options?: DistinctOptions): T[];
+  createIndex(keys: IndexKeys, options?: IndexOptions): string;
+  createIndexes(keySpecs: IndexKeys[], options?: IndexOptions): string[];
+  dropIndex(index: string | IndexKeys): void;
+  listIndexes(options?: ListIndexesOptions): IndexDescription[];
+  indexExists(name: string): boolean;
+  rename(newName: string, dropTarget?: boolean): void;
+}
+
+type CollectionSpecification = {
+  name: string;
+  type: "collection" | "view" | "timeseries";
+  options: Document;
+  info: Document;
+  idIndex?: Document;
+}
+
+type ServerFeature = "transactions" | "merge" | "timeSeries" | "clusteredIndexes";
+
+type Server = {
+  version: string;
+  topology: "replicaSet" | "sharded" | "standalone";
+  transactions: boolean;
+  merge: boolean;
+  timeSeries: boolean;
+  clusteredIndexes: boolean;
+  atLeast(version: string): boolean;
+  require(...features: ServerFeature[]): void;
 }
 
 type Handle = {
+  server: Server;
   collection: (name: string) => Collection;
+  createCollection(name: string, options?: Document): Collection;
+  createView(name: string, source: string, pipeline: Document[], options?: Document): Collection;
+  dropCollection(name: string): void;
+  listCollections(filter?: Document): CollectionSpecification[];
+  listCollectionNames(): string[];
+  collectionExists(name: string): boolean;
+  collMod(name: string, options: Document): Document;
+  runCommand(command: Document): Document;
+}
+
+type WriteError = {
+  index: number;
+  code: number;
+  message: string;
+}
+
+type DatabaseError = Error & {
+  name: "DatabaseError";
+  code: number;
+  codeName?: string;
+  labels: string[];
+  writeErrors: WriteError[];
+}
+
+type Databases = Record<string, any>;
+
+type Backfill<Checkpoint = any> = {
+  batchSize?: number;
+  throttleMs?: number;
+  batch(db: Handle, checkpoint: Checkpoint | null, batchSize: number, databases: Databases): Checkpoint | null | undefined;
 }
 
 type Console = {
@@ -27,7 +268,56 @@ type Console = {
 declare const console: Console;
 
 declare class ObjectId {
-  constructor(hexValue: string);
+  constructor(hexValue?: string);
+  toHexString(): string;
+  toString(): string;
+  toJSON(): string;
+}
+
+declare class Decimal128 {
+  constructor(value: string);
+  toString(): string;
+  toJSON(): string;
+}
+
+declare class Long {
+  constructor(value?: string | number);
+  toNumber(): number;
+  valueOf(): number;
+  toString(): string;
+  toJSON(): number | string;
+}
+
+declare class Double {
+  constructor(value: number);
+  valueOf(): number;
+  toString(): string;
+  toJSON(): number;
+}
+
+declare class UUID {
+  constructor(value?: string);
   toHexString(): string;
   toString(): string;
+  toJSON(): string;
+}
+
+declare class Binary {
+  constructor(data?: string | Uint8Array | number[], subType?: number);
+  readonly subType: number;
+  readonly length: number;
+  toBase64(): string;
+  toString(): string;
+  toJSON(): string;
+}
+
+declare class Timestamp {
+  constructor(value: { t: number; i: number });
+  readonly t: number;
+  readonly i: number;
+}
+
+declare module "*.csv" {
+  const text: string;
+  export default text;
 }
@@ -23,9 +23,11 @@ func JSObjectIdCtor(call goja.ConstructorCall, jsvm *goja.Runtime) *goja.Object
 		return jsvm.ToValue(objectId.String())
 	})
 
-	call.This.Set("toHexString", func(call goja.FunctionCall) goja.Value {
+	toHexString := func(call goja.FunctionCall) goja.Value {
 		return jsvm.ToValue(objectId.Hex())
-	})
+	}
+	call.This.Set("toHexString", toHexString)
+	call.This.Set("toJSON", toHexString)
 
 	return nil
 }
@@ -0,0 +1,204 @@
+package mongodb
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// optionAliases maps option names that differ from the driver's setter names.
+// Durations are given in milliseconds, as they are in the MongoDB shell.
+var optionAliases = map[string]string{
+	"maxTimeMS":      "MaxTime",
+	"maxAwaitTimeMS": "MaxAwaitTime",
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+var returnDocumentType = reflect.TypeOf(options.ReturnDocument(0))
+var arrayFiltersType = reflect.TypeOf(options.ArrayFilters{})
+
+// decodeOptions converts the options passed to a collection method into the
+// driver's options type T. When called from Go the options are already of
+// type *T. When called from a migration they are documents, and each key is
+// applied through the matching setter, so `limit` is applied with SetLimit.
+// Unknown keys are an error.
+func decodeOptions[T any](operation string, opts []any) []*T {
+	decoded := make([]*T, 0, len(opts))
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case nil:
+			continue
+		case *T:
+			decoded = append(decoded, opt)
+		default:
+			optDoc, err := toDocument(opt)
+			if err != nil {
+				panic(fmt.Errorf("%s options: %w", operation, err))
+			}
+			target := new(T)
+			if err := applyOptions(reflect.ValueOf(target), optDoc); err != nil {
+				panic(fmt.Errorf("%s options: %w", operation, err))
+			}
+			decoded = append(decoded, target)
+		}
+	}
+	return decoded
+}
+
+func applyOptions(target reflect.Value, optDoc bson.D) error {
+	for _, elem := range optDoc {
+		setterName, ok := optionAliases[elem.Key]
+		if !ok {
+			setterName = upperFirst(elem.Key)
+		}
+
+		setter := target.MethodByName("Set" + setterName)
+		if !setter.IsValid() || setter.Type().NumIn() != 1 {
+			return fmt.Errorf("unknown option `%s`", elem.Key)
+		}
+
+		value, err := convertOptionValue(elem.Value, setter.Type().In(0))
+		if err != nil {
+			return fmt.Errorf("option `%s`: %w", elem.Key, err)
+		}
+		setter.Call([]reflect.Value{value})
+	}
+	return nil
+}
+
+func convertOptionValue(value any, targetType reflect.Type) (reflect.Value, error) {
+	switch {
+	case targetType == durationType:
+		milliseconds, err := toFloat(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(time.Duration(milliseconds * float64(time.Millisecond))), nil
+
+	case targetType == returnDocumentType:
+		switch value {
+		case "after":
+			return reflect.ValueOf(options.After), nil
+		case "before":
+			return reflect.ValueOf(options.Before), nil
+		}
+		return reflect.Value{}, fmt.Errorf("expected \"before\" or \"after\", got %v", value)
+
+	case targetType == arrayFiltersType:
+		filters, ok := value.([]any)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected an array, got %T", value)
+		}
+		return reflect.ValueOf(options.ArrayFilters{Filters: filters}), nil
+
+	case targetType.Kind() == reflect.Interface:
+		if value == nil {
+			return reflect.Zero(targetType), nil
+		}
+		return reflect.ValueOf(value), nil
+
+	case targetType.Kind() == reflect.Map:
+		valueDoc, err := toDocument(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		valueMap := reflect.MakeMap(targetType)
+		for _, elem := range valueDoc {
+			valueMap.SetMapIndex(reflect.ValueOf(elem.Key), reflect.ValueOf(elem.Value))
+		}
+		return valueMap, nil
+
+	case targetType.Kind() == reflect.Ptr && targetType.Elem().Kind() == reflect.Struct:
+		valueDoc, err := toDocument(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		target := reflect.New(targetType.Elem())
+		if err := setStructFields(target.Elem(), valueDoc); err != nil {
+			return reflect.Value{}, err
+		}
+		return target, nil
+
+	case targetType.Kind() == reflect.Bool, targetType.Kind() == reflect.String:
+		valueVr := reflect.ValueOf(value)
+		if !valueVr.IsValid() || valueVr.Kind() != targetType.Kind() {
+			return reflect.Value{}, fmt.Errorf("expected a %s, got %T", targetType.Kind(), value)
+		}
+		return valueVr.Convert(targetType), nil
+
+	case isNumberKind(targetType.Kind()):
+		number, err := toFloat(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if targetType.Kind() != reflect.Float32 && targetType.Kind() != reflect.Float64 && number != math.Trunc(number) {
+			return reflect.Value{}, fmt.Errorf("expected an integer, got %v", number)
+		}
+		return reflect.ValueOf(number).Convert(targetType), nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported option type %s", targetType)
+	}
+}
+
+// setStructFields sets the fields of target from the document, matching keys
+// to field names without regard to case, so `caseLevel` sets CaseLevel.
+func setStructFields(target reflect.Value, valueDoc bson.D) error {
+	for _, elem := range valueDoc {
+		field := target.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, elem.Key)
+		})
+		if !field.IsValid() || !field.CanSet() {
+			return fmt.Errorf("unknown field `%s`", elem.Key)
+		}
+		value, err := convertOptionValue(elem.Value, field.Type())
+		if err != nil {
+			return fmt.Errorf("field `%s`: %w", elem.Key, err)
+		}
+		field.Set(value)
+	}
+	return nil
+}
+
+func toFloat(value any) (float64, error) {
+	valueVr := reflect.ValueOf(value)
+	if !valueVr.IsValid() {
+		return 0, fmt.Errorf("expected a number, got null")
+	}
+	switch {
+	case valueVr.CanInt():
+		return float64(valueVr.Int()), nil
+	case valueVr.CanUint():
+		return float64(valueVr.Uint()), nil
+	case valueVr.CanFloat():
+		return valueVr.Float(), nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func upperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
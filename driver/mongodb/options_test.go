@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func Test_DecodeOptions_Find(t *testing.T) {
+	opts := decodeOptions[options.FindOptions]("find", []any{bson.D{
+		{Key: "limit", Value: int64(10)},
+		{Key: "skip", Value: int64(5)},
+		{Key: "sort", Value: bson.D{{Key: "createdAt", Value: int64(-1)}}},
+		{Key: "projection", Value: bson.D{{Key: "name", Value: int64(1)}}},
+		{Key: "maxTimeMS", Value: int64(1500)},
+		{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int64(2)}}},
+	}})
+
+	if len(opts) != 1 {
+		t.Fatalf("decodeOptions() returned %d options, want 1", len(opts))
+	}
+	opt := opts[0]
+	if opt.Limit == nil || *opt.Limit != 10 {
+		t.Errorf("Limit = %v, want 10", opt.Limit)
+	}
+	if opt.Skip == nil || *opt.Skip != 5 {
+		t.Errorf("Skip = %v, want 5", opt.Skip)
+	}
+	if !reflect.DeepEqual(opt.Sort, bson.D{{Key: "createdAt", Value: int64(-1)}}) {
+		t.Errorf("Sort = %v, want {createdAt: -1}", opt.Sort)
+	}
+	if !reflect.DeepEqual(opt.Projection, bson.D{{Key: "name", Value: int64(1)}}) {
+		t.Errorf("Projection = %v, want {name: 1}", opt.Projection)
+	}
+	if opt.MaxTime == nil || *opt.MaxTime != 1500*time.Millisecond {
+		t.Errorf("MaxTime = %v, want 1.5s", opt.MaxTime)
+	}
+	if opt.Collation == nil || opt.Collation.Locale != "en" || opt.Collation.Strength != 2 {
+		t.Errorf("Collation = %+v, want locale en and strength 2", opt.Collation)
+	}
+}
+
+func Test_DecodeOptions_FindOneAndUpdate(t *testing.T) {
+	opts := decodeOptions[options.FindOneAndUpdateOptions]("findOneAndUpdate", []any{map[string]any{
+		"returnDocument": "after",
+		"upsert":         true,
+		"arrayFilters":   []any{bson.D{{Key: "elem.qty", Value: bson.D{{Key: "$gt", Value: int64(5)}}}}},
+	}})
+
+	opt := opts[0]
+	if opt.ReturnDocument == nil || *opt.ReturnDocument != options.After {
+		t.Errorf("ReturnDocument = %v, want After", opt.ReturnDocument)
+	}
+	if opt.Upsert == nil || !*opt.Upsert {
+		t.Errorf("Upsert = %v, want true", opt.Upsert)
+	}
+	if opt.ArrayFilters == nil || len(opt.ArrayFilters.Filters) != 1 {
+		t.Errorf("ArrayFilters = %v, want one filter", opt.ArrayFilters)
+	}
+}
+
+func Test_DecodeOptions_PassesThroughDriverOptions(t *testing.T) {
+	findOpts := options.Find().SetLimit(2)
+
+	opts := decodeOptions[options.FindOptions]("find", []any{nil, findOpts})
+
+	if len(opts) != 1 || opts[0] != findOpts {
+		t.Errorf("decodeOptions() = %v, want the given *FindOptions", opts)
+	}
+}
+
+func Test_DecodeOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     bson.D
+		expected string
+	}{
+		{"unknown option", bson.D{{Key: "limt", Value: int64(1)}}, "find options: unknown option `limt`"},
+		{"wrong type", bson.D{{Key: "limit", Value: "ten"}}, "find options: option `limit`: expected a number, got string"},
+		{"fractional integer", bson.D{{Key: "limit", Value: 1.5}}, "find options: option `limit`: expected an integer, got 1.5"},
+		{"unknown collation field", bson.D{{Key: "collation", Value: bson.D{{Key: "lang", Value: "en"}}}}, "find options: option `collation`: unknown field `lang`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				if !ok {
+					t.Fatalf("decodeOptions() panicked with %v, want an error", r)
+				}
+				if !strings.Contains(err.Error(), test.expected) {
+					t.Errorf("decodeOptions() error = %q, want %q", err.Error(), test.expected)
+				}
+			}()
+			decodeOptions[options.FindOptions]("find", []any{test.opts})
+		})
+	}
+}
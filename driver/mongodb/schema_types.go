@@ -0,0 +1,362 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/telemetryos/graviton/schematypes"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// SCHEMA_TYPES_SAMPLE_SIZE is the number of documents of each collection
+// sampled to infer its type.
+const SCHEMA_TYPES_SAMPLE_SIZE = 1000
+
+// SchemaTypes describes the collections and views of the database as the
+// documents migrations read from them, for `graviton types`. A collection's
+// $jsonSchema validator gives the type of the fields it declares. Other fields
+// are inferred from a sample of the documents, and are optional when some of
+// the sampled documents lack them.
+func (d *Driver) SchemaTypes(ctx context.Context) (*schematypes.Schema, error) {
+	cur, err := d.database.ListCollections(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var specs []*collectionSpec
+	if err := cur.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(specs, func(a, b *collectionSpec) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	schema := &schematypes.Schema{Namespace: "Collections"}
+	for _, spec := range specs {
+		if strings.HasPrefix(spec.Name, "system.") || isGravitonCollection(spec.Name) {
+			continue
+		}
+
+		cur, err := d.database.Collection(spec.Name).Aggregate(ctx, bson.A{
+			bson.D{{Key: "$sample", Value: bson.D{{Key: "size", Value: SCHEMA_TYPES_SAMPLE_SIZE}}}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		sample := newObjectShape()
+		for cur.Next(ctx) {
+			sample.add(cur.Current)
+		}
+		if err := cur.Err(); err != nil {
+			cur.Close(ctx)
+			return nil, err
+		}
+		cur.Close(ctx)
+
+		validator, _ := spec.Options.Lookup("validator", "$jsonSchema").DocumentOK()
+		schema.Types = append(schema.Types, &schematypes.Type{
+			Name:   spec.Name,
+			Fields: documentFields(validator, sample),
+		})
+	}
+	return schema, nil
+}
+
+type collectionSpec struct {
+	Name    string   `bson:"name"`
+	Options bson.Raw `bson:"options"`
+}
+
+func isGravitonCollection(name string) bool {
+	return name == MIGRATIONS_COLLECTION || name == SEEDS_COLLECTION || name == BACKFILLS_COLLECTION
+}
+
+// documentFields returns the fields of a collection's documents, first those
+// declared by its validator, if it has one, and then those only seen in the
+// sample.
+func documentFields(validator bson.Raw, sample *objectShape) []*schematypes.Field {
+	var fields []*schematypes.Field
+	declared := map[string]bool{}
+	if validator != nil {
+		for _, field := range jsonSchemaFields(validator) {
+			fields = append(fields, field)
+			declared[field.Name] = true
+		}
+	}
+	for _, field := range sample.fields() {
+		if !declared[field.Name] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// valueShape collects the types of the values of a field across the sampled
+// documents.
+type valueShape struct {
+	types []string
+	// object is set once a value is an embedded document, and elements once a
+	// value is an array.
+	object   *objectShape
+	elements *valueShape
+}
+
+// objectShape collects the fields of the sampled documents, or of the values
+// of a field that are embedded documents.
+type objectShape struct {
+	count  int
+	names  []string
+	counts map[string]int
+	values map[string]*valueShape
+}
+
+func newObjectShape() *objectShape {
+	return &objectShape{counts: map[string]int{}, values: map[string]*valueShape{}}
+}
+
+func (o *objectShape) add(doc bson.Raw) {
+	o.count++
+	elements, err := doc.Elements()
+	if err != nil {
+		return
+	}
+	for _, element := range elements {
+		key := element.Key()
+		value, ok := o.values[key]
+		if !ok {
+			value = &valueShape{}
+			o.names = append(o.names, key)
+			o.values[key] = value
+		}
+		o.counts[key]++
+		value.add(element.Value())
+	}
+}
+
+func (o *objectShape) fields() []*schematypes.Field {
+	fields := []*schematypes.Field{}
+	for _, name := range o.names {
+		fields = append(fields, &schematypes.Field{
+			Name:     name,
+			Type:     o.values[name].typeScript(),
+			Optional: o.counts[name] < o.count,
+		})
+	}
+	return fields
+}
+
+func (v *valueShape) add(value bson.RawValue) {
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		if v.object == nil {
+			v.object = newObjectShape()
+			v.types = append(v.types, "")
+		}
+		v.object.add(value.Document())
+	case bsontype.Array:
+		if v.elements == nil {
+			v.elements = &valueShape{}
+			v.types = append(v.types, "[]")
+		}
+		elements, _ := value.Array().Values()
+		for _, element := range elements {
+			v.elements.add(element)
+		}
+	default:
+		if t := bsonTypeScriptType(value); !slices.Contains(v.types, t) {
+			v.types = append(v.types, t)
+		}
+	}
+}
+
+// typeScript returns the type of the values, with embedded documents and
+// arrays in the order they were first seen among the other types.
+func (v *valueShape) typeScript() string {
+	var types []string
+	for _, t := range v.types {
+		switch t {
+		case "":
+			types = append(types, objectTypeScript(v.object.fields()))
+		case "[]":
+			types = append(types, arrayTypeScript(v.elements.typeScript()))
+		default:
+			types = append(types, t)
+		}
+	}
+	return schematypes.Union(types...)
+}
+
+// bsonTypeScriptType returns the type of a value as migrations see it, with
+// the classes of the BSON types they keep.
+func bsonTypeScriptType(value bson.RawValue) string {
+	switch value.Type {
+	case bsontype.Double, bsontype.Int32:
+		return "number"
+	case bsontype.Int64:
+		return "Long"
+	case bsontype.Decimal128:
+		return "Decimal128"
+	case bsontype.String, bsontype.Symbol, bsontype.JavaScript:
+		return "string"
+	case bsontype.Boolean:
+		return "boolean"
+	case bsontype.DateTime:
+		return "Date"
+	case bsontype.Null:
+		return "null"
+	case bsontype.Undefined:
+		return "undefined"
+	case bsontype.ObjectID:
+		return "ObjectId"
+	case bsontype.Binary:
+		if subtype, data := value.Binary(); subtype == bson.TypeBinaryUUID && len(data) == 16 {
+			return "UUID"
+		}
+		return "Binary"
+	case bsontype.Regex:
+		return "RegExp"
+	case bsontype.Timestamp:
+		return "Timestamp"
+	default:
+		return "any"
+	}
+}
+
+// jsonSchemaBSONTypes gives the type of each bsonType, and JSON Schema type,
+// a $jsonSchema validator can require. Objects and arrays are typed from the
+// rest of the schema.
+var jsonSchemaBSONTypes = map[string]string{
+	"double":    "number",
+	"int":       "number",
+	"number":    "number",
+	"integer":   "number",
+	"long":      "Long",
+	"decimal":   "Decimal128",
+	"string":    "string",
+	"bool":      "boolean",
+	"boolean":   "boolean",
+	"date":      "Date",
+	"null":      "null",
+	"objectId":  "ObjectId",
+	"binData":   "Binary | UUID",
+	"regex":     "RegExp",
+	"timestamp": "Timestamp",
+}
+
+// jsonSchemaFields returns the fields declared by the properties of an object
+// schema, which are optional unless they are required.
+func jsonSchemaFields(schema bson.Raw) []*schematypes.Field {
+	var required []string
+	if values, ok := schema.Lookup("required").ArrayOK(); ok {
+		elements, _ := values.Values()
+		for _, element := range elements {
+			required = append(required, element.StringValue())
+		}
+	}
+
+	fields := []*schematypes.Field{}
+	properties, ok := schema.Lookup("properties").DocumentOK()
+	if !ok {
+		return fields
+	}
+	elements, _ := properties.Elements()
+	for _, element := range elements {
+		property, _ := element.Value().DocumentOK()
+		fields = append(fields, &schematypes.Field{
+			Name:     element.Key(),
+			Type:     jsonSchemaTypeScript(property),
+			Optional: !slices.Contains(required, element.Key()),
+		})
+	}
+	return fields
+}
+
+// jsonSchemaTypeScript returns the type a $jsonSchema validator's schema
+// allows, from its enum, bsonType or type.
+func jsonSchemaTypeScript(schema bson.Raw) string {
+	if values, ok := schema.Lookup("enum").ArrayOK(); ok {
+		var types []string
+		elements, _ := values.Values()
+		for _, element := range elements {
+			types = append(types, jsonSchemaLiteral(element))
+		}
+		return schematypes.Union(types...)
+	}
+
+	var names []string
+	for _, key := range []string{"bsonType", "type"} {
+		value := schema.Lookup(key)
+		if name, ok := value.StringValueOK(); ok {
+			names = append(names, name)
+		} else if values, ok := value.ArrayOK(); ok {
+			elements, _ := values.Values()
+			for _, element := range elements {
+				names = append(names, element.StringValue())
+			}
+		}
+		if len(names) > 0 {
+			break
+		}
+	}
+
+	var types []string
+	for _, name := range names {
+		switch name {
+		case "object":
+			if _, ok := schema.Lookup("properties").DocumentOK(); ok {
+				types = append(types, objectTypeScript(jsonSchemaFields(schema)))
+			} else {
+				types = append(types, "Record<string, any>")
+			}
+		case "array":
+			items, _ := schema.Lookup("items").DocumentOK()
+			types = append(types, arrayTypeScript(jsonSchemaTypeScript(items)))
+		default:
+			types = append(types, jsonSchemaBSONTypes[name])
+		}
+	}
+	return schematypes.Union(types...)
+}
+
+// jsonSchemaLiteral returns an enum value as a literal type. Values that have
+// no literal type, such as documents, are typed by their BSON type.
+func jsonSchemaLiteral(value bson.RawValue) string {
+	var literal any
+	switch value.Type {
+	case bsontype.String:
+		literal = value.StringValue()
+	case bsontype.Boolean:
+		literal = value.Boolean()
+	case bsontype.Int32:
+		literal = value.Int32()
+	case bsontype.Double:
+		literal = value.Double()
+	case bsontype.Null:
+		return "null"
+	default:
+		return bsonTypeScriptType(value)
+	}
+	encoded, _ := json.Marshal(literal)
+	return string(encoded)
+}
+
+func objectTypeScript(fields []*schematypes.Field) string {
+	if len(fields) == 0 {
+		return "Record<string, any>"
+	}
+	var properties []string
+	for _, field := range fields {
+		properties = append(properties, schematypes.Property(field.Name, field.Optional)+": "+field.Type)
+	}
+	return "{ " + strings.Join(properties, "; ") + " }"
+}
+
+func arrayTypeScript(elementType string) string {
+	if strings.Contains(elementType, " | ") {
+		return "(" + elementType + ")[]"
+	}
+	return elementType + "[]"
+}
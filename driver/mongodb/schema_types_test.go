@@ -0,0 +1,124 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/telemetryos/graviton/schematypes"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func mustMarshal(t *testing.T, doc any) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	return raw
+}
+
+func Test_DocumentFields_Sample(t *testing.T) {
+	sample := newObjectShape()
+	for _, doc := range []bson.D{
+		{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "name", Value: "Ada"},
+			{Key: "age", Value: int32(36)},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "London"}}},
+			{Key: "tags", Value: bson.A{"admin", int64(1)}},
+			{Key: "created-at", Value: primitive.NewDateTimeFromTime(time.Now())},
+		},
+		{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "name", Value: nil},
+			{Key: "age", Value: 36.5},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}, {Key: "zip", Value: "75001"}}},
+			{Key: "tags", Value: bson.A{}},
+		},
+	} {
+		sample.add(mustMarshal(t, doc))
+	}
+
+	want := []*schematypes.Field{
+		{Name: "_id", Type: "ObjectId"},
+		{Name: "name", Type: "string | null"},
+		{Name: "age", Type: "number"},
+		{Name: "address", Type: "{ city: string; zip?: string }"},
+		{Name: "tags", Type: "(string | Long)[]"},
+		{Name: "created-at", Type: "Date", Optional: true},
+	}
+	if fields := documentFields(nil, sample); !reflect.DeepEqual(fields, want) {
+		t.Errorf("documentFields() = %v, want %v", fields, want)
+	}
+}
+
+func Test_DocumentFields_Validator(t *testing.T) {
+	validator := mustMarshal(t, bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"email", "status"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "email", Value: bson.D{{Key: "bsonType", Value: "string"}}},
+			{Key: "status", Value: bson.D{{Key: "enum", Value: bson.A{"active", "banned"}}}},
+			{Key: "score", Value: bson.D{{Key: "bsonType", Value: bson.A{"int", "long", "null"}}}},
+			{Key: "roles", Value: bson.D{{Key: "bsonType", Value: "array"}, {Key: "items", Value: bson.D{{Key: "bsonType", Value: "string"}}}}},
+			{Key: "profile", Value: bson.D{
+				{Key: "bsonType", Value: "object"},
+				{Key: "properties", Value: bson.D{{Key: "bio", Value: bson.D{}}}},
+			}},
+		}},
+	})
+
+	sample := newObjectShape()
+	sample.add(mustMarshal(t, bson.D{{Key: "email", Value: int32(1)}, {Key: "legacy", Value: true}}))
+	sample.add(mustMarshal(t, bson.D{{Key: "email", Value: "ada@example.com"}}))
+
+	want := []*schematypes.Field{
+		{Name: "email", Type: "string"},
+		{Name: "status", Type: `"active" | "banned"`},
+		{Name: "score", Type: "number | Long | null", Optional: true},
+		{Name: "roles", Type: "string[]", Optional: true},
+		{Name: "profile", Type: "{ bio?: any }", Optional: true},
+		{Name: "legacy", Type: "boolean", Optional: true},
+	}
+	if fields := documentFields(validator, sample); !reflect.DeepEqual(fields, want) {
+		t.Errorf("documentFields() = %v, want %v", fields, want)
+	}
+}
+
+func Test_DocumentFields_NoOptions(t *testing.T) {
+	var spec collectionSpec
+	validator, _ := spec.Options.Lookup("validator", "$jsonSchema").DocumentOK()
+	if fields := documentFields(validator, newObjectShape()); len(fields) != 0 {
+		t.Errorf("documentFields() = %v, want none", fields)
+	}
+}
+
+func Test_Driver_SchemaTypes(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	handle := drv.Handle(ctx).(*MongoHandle)
+	handle.CreateCollection("users", bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: bson.D{
+		{Key: "required", Value: bson.A{"email"}},
+		{Key: "properties", Value: bson.D{{Key: "email", Value: bson.D{{Key: "bsonType", Value: "string"}}}}},
+	}}}}})
+	handle.Collection("users").InsertOne(bson.D{{Key: "email", Value: "ada@example.com"}, {Key: "age", Value: int32(36)}})
+
+	schema, err := drv.SchemaTypes(ctx)
+	if err != nil {
+		t.Fatalf("SchemaTypes() error = %v", err)
+	}
+	if len(schema.Types) != 1 || schema.Types[0].Name != "users" {
+		t.Fatalf("SchemaTypes() = %+v, want the users collection", schema)
+	}
+	want := []*schematypes.Field{
+		{Name: "email", Type: "string"},
+		{Name: "_id", Type: "ObjectId"},
+		{Name: "age", Type: "number"},
+	}
+	if fields := schema.Types[0].Fields; !reflect.DeepEqual(fields, want) {
+		t.Errorf("SchemaTypes() fields = %v, want %v", fields, want)
+	}
+}
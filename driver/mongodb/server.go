@@ -0,0 +1,182 @@
+package mongodb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	TopologyReplicaSet = "replicaSet"
+	TopologySharded    = "sharded"
+	TopologyStandalone = "standalone"
+)
+
+// MIN_SERVER_VERSION is the oldest MongoDB release Graviton supports.
+var MIN_SERVER_VERSION = ServerVersion{Major: 4}
+
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseServerVersion parses versions as reported by buildInfo, such as
+// "7.0.2", "4.4.0-rc1" or "6.0.5-ent".
+func ParseServerVersion(version string) (ServerVersion, error) {
+	release, _, _ := strings.Cut(version, "-")
+	parts := strings.Split(release, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return ServerVersion{}, fmt.Errorf("invalid MongoDB version `%s`", version)
+	}
+
+	numbers := [3]int{}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return ServerVersion{}, fmt.Errorf("invalid MongoDB version `%s`", version)
+		}
+		numbers[i] = number
+	}
+
+	return ServerVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func (v ServerVersion) Compare(other ServerVersion) int {
+	switch {
+	case v.Major != other.Major:
+		return v.Major - other.Major
+	case v.Minor != other.Minor:
+		return v.Minor - other.Minor
+	default:
+		return v.Patch - other.Patch
+	}
+}
+
+func (v ServerVersion) AtLeast(other ServerVersion) bool {
+	return v.Compare(other) >= 0
+}
+
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// serverFeature describes a feature migrations can check for with
+// db.server.require.
+type serverFeature struct {
+	name        string
+	description string
+	minVersion  ServerVersion
+}
+
+var serverFeatures = []*serverFeature{
+	{name: "transactions", description: "transactions", minVersion: ServerVersion{Major: 4}},
+	{name: "merge", description: "the $merge aggregation stage", minVersion: ServerVersion{Major: 4, Minor: 2}},
+	{name: "timeSeries", description: "time-series collections", minVersion: ServerVersion{Major: 5}},
+	{name: "clusteredIndexes", description: "clustered collections", minVersion: ServerVersion{Major: 5, Minor: 3}},
+}
+
+type helloResult struct {
+	IsWritablePrimary bool   `bson:"isWritablePrimary"`
+	Secondary         bool   `bson:"secondary"`
+	SetName           string `bson:"setName"`
+	Msg               string `bson:"msg"`
+}
+
+func (h *helloResult) topology() string {
+	switch {
+	case h.SetName != "":
+		return TopologyReplicaSet
+	case h.Msg == "isdbgrid":
+		return TopologySharded
+	default:
+		return TopologyStandalone
+	}
+}
+
+// Server describes the MongoDB server migrations run against and the features
+// it supports. Migrations see it as db.server.
+type Server struct {
+	Version          string
+	Topology         string
+	Transactions     bool
+	Merge            bool
+	TimeSeries       bool
+	ClusteredIndexes bool
+
+	parsedVersion      ServerVersion
+	transactionsReason string
+}
+
+func newServer(version string, hello *helloResult, transactionsEnabled bool) (*Server, error) {
+	parsedVersion, err := ParseServerVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		Version:          version,
+		Topology:         hello.topology(),
+		Merge:            parsedVersion.AtLeast(findServerFeature("merge").minVersion),
+		TimeSeries:       parsedVersion.AtLeast(findServerFeature("timeSeries").minVersion),
+		ClusteredIndexes: parsedVersion.AtLeast(findServerFeature("clusteredIndexes").minVersion),
+		parsedVersion:    parsedVersion,
+	}
+
+	switch {
+	case !transactionsEnabled:
+		server.transactionsReason = "transactions are disabled by `transactions = false`"
+	case server.Topology == TopologyStandalone:
+		server.transactionsReason = "transactions need a replica set or sharded cluster, the server is standalone"
+	case server.Topology == TopologySharded && !parsedVersion.AtLeast(ServerVersion{Major: 4, Minor: 2}):
+		server.transactionsReason = "transactions on sharded clusters need MongoDB 4.2 or later, the server is MongoDB " + version
+	default:
+		server.Transactions = true
+	}
+
+	return server, nil
+}
+
+// AtLeast reports whether the server is the given version or later, for
+// example db.server.atLeast("6.0").
+func (s *Server) AtLeast(version string) bool {
+	other, err := ParseServerVersion(version)
+	if err != nil {
+		panic(err)
+	}
+	return s.parsedVersion.AtLeast(other)
+}
+
+// Require fails the migration unless the server supports all of the given
+// features, so a migration stops before making any changes it cannot finish.
+func (s *Server) Require(features ...string) {
+	for _, name := range features {
+		feature := findServerFeature(name)
+		if feature == nil {
+			panic(fmt.Errorf("unknown server feature `%s`", name))
+		}
+
+		if name == "transactions" {
+			if !s.Transactions {
+				panic(fmt.Errorf("this migration requires transactions, but %s", s.transactionsReason))
+			}
+			continue
+		}
+
+		if !s.parsedVersion.AtLeast(feature.minVersion) {
+			panic(fmt.Errorf(
+				"this migration requires %s (MongoDB %d.%d or later), but the server is MongoDB %s",
+				feature.description, feature.minVersion.Major, feature.minVersion.Minor, s.Version,
+			))
+		}
+	}
+}
+
+func findServerFeature(name string) *serverFeature {
+	for _, feature := range serverFeatures {
+		if feature.name == name {
+			return feature
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected ServerVersion
+	}{
+		{"7.0.2", ServerVersion{Major: 7, Minor: 0, Patch: 2}},
+		{"10.0.0", ServerVersion{Major: 10}},
+		{"4.4.0-rc1", ServerVersion{Major: 4, Minor: 4}},
+		{"6.0.5-ent", ServerVersion{Major: 6, Minor: 0, Patch: 5}},
+		{"5.3", ServerVersion{Major: 5, Minor: 3}},
+	}
+
+	for _, test := range tests {
+		version, err := ParseServerVersion(test.version)
+		if err != nil {
+			t.Errorf("ParseServerVersion(%q) error = %v", test.version, err)
+			continue
+		}
+		if version != test.expected {
+			t.Errorf("ParseServerVersion(%q) = %v, want %v", test.version, version, test.expected)
+		}
+	}
+
+	for _, version := range []string{"", "v7.0", "7.0.2.1", "seven"} {
+		if _, err := ParseServerVersion(version); err == nil {
+			t.Errorf("ParseServerVersion(%q) error = nil, want an error", version)
+		}
+	}
+}
+
+func Test_ServerVersion_AtLeast(t *testing.T) {
+	ten, _ := ParseServerVersion("10.0.0")
+	four, _ := ParseServerVersion("4.0.0")
+
+	if !ten.AtLeast(four) {
+		t.Error("10.0.0 should be at least 4.0.0")
+	}
+	if four.AtLeast(ten) {
+		t.Error("4.0.0 should not be at least 10.0.0")
+	}
+	if !four.AtLeast(four) {
+		t.Error("4.0.0 should be at least itself")
+	}
+}
+
+func Test_Server_Features(t *testing.T) {
+	tests := []struct {
+		version             string
+		hello               helloResult
+		transactionsEnabled bool
+		expected            Server
+	}{
+		{"4.0.28", helloResult{SetName: "rs0"}, true, Server{Topology: TopologyReplicaSet, Transactions: true}},
+		{"4.0.28", helloResult{Msg: "isdbgrid"}, true, Server{Topology: TopologySharded}},
+		{"5.0.1", helloResult{}, true, Server{Topology: TopologyStandalone, Merge: true, TimeSeries: true}},
+		{"7.0.2", helloResult{SetName: "rs0"}, false, Server{Topology: TopologyReplicaSet, Merge: true, TimeSeries: true, ClusteredIndexes: true}},
+		{"10.0.0", helloResult{Msg: "isdbgrid"}, true, Server{Topology: TopologySharded, Transactions: true, Merge: true, TimeSeries: true, ClusteredIndexes: true}},
+	}
+
+	for _, test := range tests {
+		server, err := newServer(test.version, &test.hello, test.transactionsEnabled)
+		if err != nil {
+			t.Fatalf("newServer(%q) error = %v", test.version, err)
+		}
+		if server.Topology != test.expected.Topology ||
+			server.Transactions != test.expected.Transactions ||
+			server.Merge != test.expected.Merge ||
+			server.TimeSeries != test.expected.TimeSeries ||
+			server.ClusteredIndexes != test.expected.ClusteredIndexes {
+			t.Errorf("newServer(%q, %+v) = %+v, want %+v", test.version, test.hello, *server, test.expected)
+		}
+	}
+}
+
+func Test_Server_Require(t *testing.T) {
+	server, err := newServer("4.4.1", &helloResult{}, true)
+	if err != nil {
+		t.Fatalf("newServer() error = %v", err)
+	}
+
+	server.Require("merge")
+	if !server.AtLeast("4.4") || server.AtLeast("5.0") {
+		t.Error("AtLeast() does not compare against 4.4.1")
+	}
+
+	tests := []struct {
+		feature  string
+		expected string
+	}{
+		{"timeSeries", "this migration requires time-series collections (MongoDB 5.0 or later), but the server is MongoDB 4.4.1"},
+		{"transactions", "this migration requires transactions, but transactions need a replica set or sharded cluster, the server is standalone"},
+		{"changeStreams", "unknown server feature `changeStreams`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.feature, func(t *testing.T) {
+			defer func() {
+				err, ok := recover().(error)
+				if !ok {
+					t.Fatalf("Require(%q) did not fail", test.feature)
+				}
+				if !strings.Contains(err.Error(), test.expected) {
+					t.Errorf("Require(%q) error = %q, want %q", test.feature, err.Error(), test.expected)
+				}
+			}()
+			server.Require(test.feature)
+		})
+	}
+}
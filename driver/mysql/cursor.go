@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+)
+
+const DEFAULT_CURSOR_BATCH_SIZE = 1000
+
+// CURSOR_ROW_COLUMN numbers the rows a cursor copies into its temporary table.
+const CURSOR_ROW_COLUMN = "graviton_cursor_row"
+
+var cursorCount atomic.Int64
+
+// Cursor reads the rows of a query a batch at a time, so results too large to
+// hold in memory can be processed row by row.
+type Cursor struct {
+	handle    *Handle
+	statement string
+	rows      *sql.Rows
+	batchSize int
+	batch     []map[string]any
+	done      bool
+
+	// table is the temporary table the rows are copied into inside a
+	// transaction, and lastRow the number of the last row read from it.
+	table   string
+	lastRow int64
+}
+
+// Cursor runs the query and returns a cursor over its rows. Outside a
+// transaction the cursor reads the result as it arrives, holding a connection
+// until it is read to the end or closed. MySQL cannot run other statements on
+// a connection while a result is being read, so inside a transaction the rows
+// are copied into a temporary table first and read from it a batch at a time,
+// which lets the migration write while it goes through them. The copy is a
+// snapshot, so the cursor does not see those writes.
+func (h *Handle) Cursor(sqlQuery *SQLQuery, batchSize ...int) *Cursor {
+	cursor := &Cursor{
+		handle:    h,
+		statement: sqlQuery.Query,
+		batchSize: cursorBatchSize(batchSize),
+	}
+	if h.driver.getTxFromContext(h.ctx) == nil {
+		cursor.rows = h.queryRows(sqlQuery)
+		return cursor
+	}
+
+	cursor.table = fmt.Sprintf("graviton_cursor_%d", cursorCount.Add(1))
+	h.Exec(&SQLQuery{
+		Query:  "CREATE TEMPORARY TABLE " + cursor.table + " (" + CURSOR_ROW_COLUMN + " BIGINT AUTO_INCREMENT PRIMARY KEY) " + sqlQuery.Query,
+		Params: sqlQuery.Params,
+	})
+	return cursor
+}
+
+// ForEach calls fn with each row of the query. Returning false from fn stops
+// the iteration.
+func (h *Handle) ForEach(sqlQuery *SQLQuery, fn func(row map[string]any) any, batchSize ...int) {
+	h.Cursor(sqlQuery, batchSize...).ForEach(fn)
+}
+
+func (c *Cursor) HasNext() bool {
+	if len(c.batch) == 0 && !c.done {
+		c.batch = c.readBatch()
+	}
+	return len(c.batch) != 0
+}
+
+// Next returns the next row, or nil when there are no rows left.
+func (c *Cursor) Next() map[string]any {
+	if !c.HasNext() {
+		return nil
+	}
+	row := c.batch[0]
+	c.batch = c.batch[1:]
+	return row
+}
+
+// ForEach calls fn with each remaining row and then closes the cursor.
+// Returning false from fn stops the iteration.
+func (c *Cursor) ForEach(fn func(row map[string]any) any) {
+	defer c.Close()
+	for c.HasNext() {
+		if proceed, ok := fn(c.Next()).(bool); ok && !proceed {
+			return
+		}
+	}
+}
+
+func (c *Cursor) ToArray() []map[string]any {
+	results := []map[string]any{}
+	for c.HasNext() {
+		results = append(results, c.Next())
+	}
+	return results
+}
+
+func (c *Cursor) Close() {
+	c.batch = nil
+	if c.done {
+		return
+	}
+	c.done = true
+	if c.table != "" {
+		// The table is dropped with the session if the transaction has failed.
+		c.handle.getExecutor().ExecContext(c.handle.ctx, "DROP TEMPORARY TABLE IF EXISTS "+c.table)
+		return
+	}
+	c.rows.Close()
+}
+
+func (c *Cursor) readBatch() []map[string]any {
+	if c.table != "" {
+		return c.readTableBatch()
+	}
+
+	batch, err := readRows(c.rows, c.batchSize)
+	if err != nil {
+		c.Close()
+		panic(&DatabaseError{Err: err, Statement: c.statement})
+	}
+	if len(batch) < c.batchSize {
+		c.Close()
+	}
+	return batch
+}
+
+// readTableBatch reads the next batch of rows from the cursor's temporary
+// table, in the order the query returned them.
+func (c *Cursor) readTableBatch() []map[string]any {
+	batch := c.handle.Query(&SQLQuery{
+		Query:  "SELECT * FROM " + c.table + " WHERE " + CURSOR_ROW_COLUMN + " > ? ORDER BY " + CURSOR_ROW_COLUMN + " LIMIT ?",
+		Params: []any{c.lastRow, c.batchSize},
+	})
+	for _, row := range batch {
+		c.lastRow = row[CURSOR_ROW_COLUMN].(int64)
+		delete(row, CURSOR_ROW_COLUMN)
+	}
+	if len(batch) < c.batchSize {
+		c.Close()
+	}
+	return batch
+}
+
+func cursorBatchSize(batchSize []int) int {
+	if len(batchSize) != 0 && batchSize[0] > 0 {
+		return batchSize[0]
+	}
+	return DEFAULT_CURSOR_BATCH_SIZE
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/telemetryos/graviton/diagnostics"
+)
+
+const DIAGNOSE_PROBE_TABLE = "graviton_doctor_probe"
+
+func (d *Driver) Diagnose(ctx context.Context) []*diagnostics.Check {
+	db, err := sql.Open("mysql", d.config.ConnectionUrl)
+	if err == nil {
+		err = db.PingContext(ctx)
+	}
+	if err != nil {
+		return []*diagnostics.Check{
+			diagnostics.Fail("connection", err.Error(), "Check connection_url and that the server is reachable"),
+		}
+	}
+	defer db.Close()
+
+	checks := []*diagnostics.Check{diagnostics.Pass("connection", "connected")}
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		checks = append(checks, diagnostics.Warn("server version", err.Error(), "The server version could not be determined"))
+	} else {
+		checks = append(checks, diagnostics.Pass("server version", "MySQL "+version))
+	}
+
+	var databaseName sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&databaseName); err != nil || !databaseName.Valid {
+		checks = append(checks, diagnostics.Fail("database", "no database selected", "Include the database name in connection_url, e.g. user@tcp(host:3306)/mydb"))
+		return checks
+	}
+	checks = append(checks, diagnostics.Pass("database", "using `"+databaseName.String+"`"))
+
+	checks = append(checks, d.diagnoseReadOnly(ctx, db))
+	for _, tableName := range []string{MIGRATIONS_TABLE, SEEDS_TABLE} {
+		checks = append(checks, d.diagnoseTrackingTable(ctx, db, tableName))
+	}
+	checks = append(checks, d.diagnoseWritePermission(ctx, db))
+
+	return checks
+}
+
+func (d *Driver) diagnoseReadOnly(ctx context.Context, db *sql.DB) *diagnostics.Check {
+	var readOnly bool
+	if err := db.QueryRowContext(ctx, "SELECT @@global.read_only").Scan(&readOnly); err != nil {
+		return diagnostics.Warn("read-only", err.Error(), "Read-only status could not be determined")
+	}
+
+	// super_read_only only exists on MySQL 5.7 and later, so an error here is
+	// not a problem.
+	var superReadOnly bool
+	db.QueryRowContext(ctx, "SELECT @@global.super_read_only").Scan(&superReadOnly)
+
+	if readOnly || superReadOnly {
+		return diagnostics.Fail("read-only", "server is read-only, it may be a replica", "Point connection_url at the primary server")
+	}
+
+	return diagnostics.Pass("read-only", "server accepts writes")
+}
+
+func (d *Driver) diagnoseTrackingTable(ctx context.Context, db *sql.DB, tableName string) *diagnostics.Check {
+	var exists bool
+	existsSQL := "SELECT COUNT(*) > 0 FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	if err := db.QueryRowContext(ctx, existsSQL, tableName).Scan(&exists); err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "Check the user can read information_schema")
+	}
+	if !exists {
+		return diagnostics.Pass(tableName, "does not exist yet and will be created on first use")
+	}
+
+	query, err := d.renderSQL(getMigrationsSQL, tableName)
+	if err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "")
+	}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "Grant SELECT, INSERT and DELETE on "+tableName+" to the user Graviton connects as")
+	}
+	rows.Close()
+
+	return diagnostics.Pass(tableName, "readable")
+}
+
+// diagnoseWritePermission creates and drops a probe table. DDL implicitly
+// commits in MySQL so the probe cannot be rolled back like it is for the
+// other SQL drivers.
+func (d *Driver) diagnoseWritePermission(ctx context.Context, db *sql.DB) *diagnostics.Check {
+	if _, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+DIAGNOSE_PROBE_TABLE+" (id INT)"); err != nil {
+		return diagnostics.Fail("write permission", "cannot create tables: "+err.Error(), "Grant CREATE on the database to the user Graviton connects as")
+	}
+	defer db.ExecContext(ctx, "DROP TABLE IF EXISTS "+DIAGNOSE_PROBE_TABLE)
+
+	if _, err := db.ExecContext(ctx, "INSERT INTO "+DIAGNOSE_PROBE_TABLE+" (id) VALUES (1)"); err != nil {
+		return diagnostics.Fail("write permission", "cannot insert rows: "+err.Error(), "Grant INSERT on the database to the user Graviton connects as")
+	}
+
+	return diagnostics.Pass("write permission", "can create tables and insert rows")
+}
@@ -55,7 +55,7 @@ func cleanDatabase(t *testing.T, drv *Driver, ctx context.Context) {
 		if err := rows.Scan(&table); err != nil {
 			t.Fatalf("Failed to scan table name: %v", err)
 		}
-		if table != MIGRATIONS_TABLE {
+		if table != MIGRATIONS_TABLE && table != BACKFILLS_TABLE {
 			tables = append(tables, table)
 		}
 	}
@@ -69,6 +69,10 @@ func cleanDatabase(t *testing.T, drv *Driver, ctx context.Context) {
 	if _, err := drv.db.ExecContext(ctx, "DELETE FROM "+MIGRATIONS_TABLE); err != nil {
 		t.Fatalf("Failed to clean migrations table: %v", err)
 	}
+
+	if _, err := drv.db.ExecContext(ctx, "DELETE FROM "+BACKFILLS_TABLE); err != nil {
+		t.Fatalf("Failed to clean backfills table: %v", err)
+	}
 }
 
 func Test_Driver_Connect(t *testing.T) {
@@ -186,3 +190,38 @@ func Test_Driver_SetAppliedMigrationsMetadata(t *testing.T) {
 		t.Errorf("GetAppliedMigrationsMetadata() returned %d migrations, want 2", len(retrieved))
 	}
 }
+
+func Test_Driver_BackfillCheckpoint(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	filename := "20240101000000-backfill.migration.ts"
+
+	_, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+	if err != nil {
+		t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+	}
+	if found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint before one was set")
+	}
+
+	for _, checkpoint := range []string{"", "42"} {
+		if err := drv.SetBackfillCheckpoint(ctx, filename, checkpoint); err != nil {
+			t.Fatalf("SetBackfillCheckpoint() error = %v", err)
+		}
+
+		retrieved, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+		if err != nil {
+			t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+		}
+		if !found || retrieved != checkpoint {
+			t.Errorf("GetBackfillCheckpoint() = %q, %v, want %q, true", retrieved, found, checkpoint)
+		}
+	}
+
+	if err := drv.DeleteBackfillCheckpoint(ctx, filename); err != nil {
+		t.Fatalf("DeleteBackfillCheckpoint() error = %v", err)
+	}
+	if _, found, _ := drv.GetBackfillCheckpoint(ctx, filename); found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint after it was deleted")
+	}
+}
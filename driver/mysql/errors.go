@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/dop251/goja"
+	"github.com/go-sql-driver/mysql"
+)
+
+// constraintNameRegexes find the constraint or key a MySQL error message
+// names. MySQL has no separate field for it.
+var constraintNameRegexes = []*regexp.Regexp{
+	// ER_DUP_ENTRY: Duplicate entry 'a' for key 'users.email'
+	regexp.MustCompile(`for key '([^']+)'$`),
+	// ER_ROW_IS_REFERENCED_2, ER_NO_REFERENCED_ROW_2
+	regexp.MustCompile("CONSTRAINT `([^`]+)`"),
+	// ER_CHECK_CONSTRAINT_VIOLATED: Check constraint 'positive' is violated.
+	regexp.MustCompile(`^Check constraint '([^']+)' is violated`),
+}
+
+// DatabaseError is the error a migration's statement failed with. Migrations
+// receive it as an Error carrying the MySQL error number and SQLSTATE, so they
+// can catch specific errors.
+type DatabaseError struct {
+	Err       error
+	Statement string
+}
+
+func (e *DatabaseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DatabaseError) Unwrap() error {
+	return e.Err
+}
+
+// databaseErrorIntoJSValue converts a database error into a JavaScript Error
+// named DatabaseError. Other errors are not converted.
+func databaseErrorIntoJSValue(runtime *goja.Runtime, err error) (goja.Value, bool) {
+	var databaseErr *DatabaseError
+	var mysqlErr *mysql.MySQLError
+	isDatabaseErr := errors.As(err, &databaseErr)
+	isMySQLErr := errors.As(err, &mysqlErr)
+	if !isDatabaseErr && !isMySQLErr {
+		return nil, false
+	}
+
+	jsErr := runtime.NewGoError(err)
+	jsErr.Set("name", "DatabaseError")
+	if isDatabaseErr {
+		jsErr.Set("statement", databaseErr.Statement)
+	}
+	if isMySQLErr {
+		jsErr.Set("code", mysqlErr.Number)
+		if mysqlErr.SQLState != [5]byte{} {
+			jsErr.Set("sqlState", string(mysqlErr.SQLState[:]))
+		}
+		if constraint := constraintName(mysqlErr.Message); constraint != "" {
+			jsErr.Set("constraint", constraint)
+		}
+	}
+	return jsErr, true
+}
+
+// constraintName returns the constraint or key named in an error message.
+// MySQL 8 prefixes duplicate keys with their table, which is removed.
+func constraintName(message string) string {
+	for _, regex := range constraintNameRegexes {
+		if match := regex.FindStringSubmatch(message); match != nil {
+			return match[1][strings.LastIndexByte(match[1], '.')+1:]
+		}
+	}
+	return ""
+}
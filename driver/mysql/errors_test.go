@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/go-sql-driver/mysql"
+)
+
+func Test_DatabaseErrorIntoJSValue(t *testing.T) {
+	runtime := goja.New()
+	err := &DatabaseError{
+		Err: &mysql.MySQLError{
+			Number:   1062,
+			SQLState: [5]byte{'2', '3', '0', '0', '0'},
+			Message:  "Duplicate entry 'a@example.com' for key 'users.users_email_key'",
+		},
+		Statement: "INSERT INTO users (email) VALUES (?)",
+	}
+
+	val, ok := databaseErrorIntoJSValue(runtime, err)
+	if !ok {
+		t.Fatal("databaseErrorIntoJSValue() ok = false, want true")
+	}
+	runtime.Set("err", val)
+
+	expected := map[string]any{
+		"err instanceof Error": true,
+		"err.name":             "DatabaseError",
+		"err.code":             int64(1062),
+		"err.sqlState":         "23000",
+		"err.constraint":       "users_email_key",
+		"err.statement":        "INSERT INTO users (email) VALUES (?)",
+	}
+	for src, want := range expected {
+		got, err := runtime.RunString(src)
+		if err != nil {
+			t.Fatalf("RunString(%q) error = %v", src, err)
+		}
+		if got.Export() != want {
+			t.Errorf("%s = %#v, want %#v", src, got.Export(), want)
+		}
+	}
+
+	if _, ok := databaseErrorIntoJSValue(runtime, errors.New("other")); ok {
+		t.Error("databaseErrorIntoJSValue() converted an error that is not from the database")
+	}
+}
+
+func Test_ConstraintName(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"Duplicate entry 'a' for key 'users.users_email_key'", "users_email_key"},
+		{"Duplicate entry '1' for key 'PRIMARY'", "PRIMARY"},
+		{"Cannot add or update a child row: a foreign key constraint fails (`app`.`posts`, CONSTRAINT `posts_user_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))", "posts_user_fk"},
+		{"Check constraint 'positive_price' is violated.", "positive_price"},
+		{"Table 'app.missing' doesn't exist", ""},
+	}
+
+	for _, test := range tests {
+		if got := constraintName(test.message); got != test.expected {
+			t.Errorf("constraintName(%q) = %q, want %q", test.message, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"strings"
+)
+
+const DEFAULT_DELIMITER = ";"
+
+// ExecScript runs each statement of a SQL script in order, such as a schema
+// dump. A failed statement stops the script, and its error carries the
+// statement that failed.
+func (h *Handle) ExecScript(script string) {
+	for _, statement := range splitStatements(script) {
+		h.Exec(&SQLQuery{Query: statement})
+	}
+}
+
+// splitStatements splits a script on the delimiters that end its statements,
+// as the mysql client does. Delimiters in strings, quoted identifiers and
+// comments do not end a statement. A DELIMITER line changes the delimiter, so
+// stored programs whose bodies contain semicolons can be defined. Statements
+// holding nothing but comments are dropped. Version comments, such as the
+// /*!40101 SET ... */ that mysqldump writes, and optimizer hints are run by
+// MySQL, so they are statement content.
+func splitStatements(script string) []string {
+	var statements []string
+	delimiter := DEFAULT_DELIMITER
+	start := 0
+	hasContent := false
+
+	endStatement := func(end int, next int) {
+		if hasContent {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = next
+		hasContent = false
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case !hasContent && isDelimiterCommand(script[i:]):
+			lineEnd := skipPast(script, i, "\n")
+			delimiter = strings.TrimSpace(script[i+len("DELIMITER") : lineEnd])
+			if delimiter == "" {
+				delimiter = DEFAULT_DELIMITER
+			}
+			i = lineEnd
+			start = i
+		case strings.HasPrefix(script[i:], delimiter):
+			endStatement(i, i+len(delimiter))
+			i += len(delimiter)
+		case c == '#' || isDashComment(script[i:]):
+			i = skipPast(script, i, "\n")
+		case strings.HasPrefix(script[i:], "/*!") || strings.HasPrefix(script[i:], "/*+"):
+			i = skipPast(script, i+3, "*/")
+			hasContent = true
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipPast(script, i+2, "*/")
+		case c == '\'' || c == '"':
+			i = skipQuoted(script, i, c, true)
+			hasContent = true
+		case c == '`':
+			i = skipQuoted(script, i, c, false)
+			hasContent = true
+		default:
+			if c > ' ' {
+				hasContent = true
+			}
+			i += 1
+		}
+	}
+	endStatement(len(script), len(script))
+
+	return statements
+}
+
+// isDelimiterCommand reports whether s starts with the mysql client's
+// DELIMITER command.
+func isDelimiterCommand(s string) bool {
+	const command = "DELIMITER"
+	if len(s) <= len(command) || !strings.EqualFold(s[:len(command)], command) {
+		return false
+	}
+	return s[len(command)] == ' ' || s[len(command)] == '\t'
+}
+
+// isDashComment reports whether s starts with a -- comment. MySQL requires
+// whitespace after the dashes.
+func isDashComment(s string) bool {
+	if !strings.HasPrefix(s, "--") {
+		return false
+	}
+	return len(s) == 2 || strings.IndexByte(" \t\r\n", s[2]) >= 0
+}
+
+// skipQuoted returns the position after the string or quoted identifier
+// starting at i. A doubled quote is part of the text, as is any character
+// after a backslash when escapes is set.
+func skipQuoted(script string, i int, quote byte, escapes bool) int {
+	for i += 1; i < len(script); i += 1 {
+		switch script[i] {
+		case '\\':
+			if escapes {
+				i += 1
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i += 1
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// skipPast returns the position after the next end found after i, or the end
+// of the script.
+func skipPast(script string, i int, end string) int {
+	next := strings.Index(script[i:], end)
+	if next < 0 {
+		return len(script)
+	}
+	return i + next + len(end)
+}
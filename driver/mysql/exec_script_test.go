@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			"statements",
+			"CREATE TABLE a (id int);\nINSERT INTO a VALUES (1);\n",
+			[]string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			"strings and identifiers",
+			"INSERT INTO `a;b` VALUES ('x;\\'y', \"z;\"\"\");SELECT 1",
+			[]string{"INSERT INTO `a;b` VALUES ('x;\\'y', \"z;\"\"\")", "SELECT 1"},
+		},
+		{
+			"comments",
+			"# a; b\nSELECT 1; /* c; */ SELECT 2 -- d;\n;SELECT 3--4;",
+			[]string{"# a; b\nSELECT 1", "/* c; */ SELECT 2 -- d;", "SELECT 3--4"},
+		},
+		{
+			"delimiter",
+			"DROP PROCEDURE IF EXISTS p;\nDELIMITER $$\nCREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND$$\ndelimiter ;\nCALL p();",
+			[]string{"DROP PROCEDURE IF EXISTS p", "CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND", "CALL p()"},
+		},
+		{
+			"mysqldump",
+			"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+				"/*!50001 CREATE VIEW `v` AS SELECT 1 AS `a` */;\n" +
+				"SELECT /*+ MAX_EXECUTION_TIME(1000) */ 1;\n" +
+				"/* plain; */;\n",
+			[]string{
+				"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */",
+				"/*!50001 CREATE VIEW `v` AS SELECT 1 AS `a` */",
+				"SELECT /*+ MAX_EXECUTION_TIME(1000) */ 1",
+			},
+		},
+		{
+			"empty",
+			" ;\n; -- nothing\n",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := splitStatements(test.script); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("splitStatements() = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
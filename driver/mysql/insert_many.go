@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DEFAULT_INSERT_BATCH_SIZE = 1000
+
+// MAX_INSERT_PARAMS is the most parameters MySQL allows in one prepared
+// statement. Batches are made smaller when their rows would need more.
+const MAX_INSERT_PARAMS = 65535
+
+type InsertManyOptions struct {
+	// BatchSize is the most rows inserted by one statement.
+	BatchSize int
+}
+
+// InsertMany inserts rows into a table with multi-row INSERT statements of up
+// to batchSize rows each, and returns the number of rows inserted. Each row is
+// an object whose keys are column names, and all rows must have the same
+// columns. Outside a migration's transaction the rows are inserted in a
+// transaction of their own, so either all of them are inserted or none.
+func (h *Handle) InsertMany(table string, rows []any, opts ...any) int64 {
+	options := decodeInsertManyOptions(opts)
+	columns, values := insertManyValues(rows)
+	if len(values) == 0 {
+		return 0
+	}
+
+	if h.driver.getTxFromContext(h.ctx) == nil {
+		var inserted int64
+		err := h.driver.WithTransaction(h.ctx, func(txCtx context.Context) error {
+			inserted = newHandle(txCtx, h.driver).InsertMany(table, rows, opts...)
+			return nil
+		})
+		if err != nil {
+			panic(err)
+		}
+		return inserted
+	}
+
+	batchSize := min(options.BatchSize, MAX_INSERT_PARAMS/len(columns))
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = quoteIdentifier([]string{column})
+	}
+	insertSQL := "INSERT INTO " + quoteIdentifier(strings.Split(table, ".")) + " (" + strings.Join(quotedColumns, ", ") + ") VALUES "
+	rowSQL := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	var inserted int64
+	for start := 0; start < len(values); start += batchSize {
+		batch := values[start:min(start+batchSize, len(values))]
+		params := make([]any, 0, len(batch)*len(columns))
+		for _, rowValues := range batch {
+			params = append(params, rowValues...)
+		}
+		query := insertSQL + strings.TrimSuffix(strings.Repeat(rowSQL+", ", len(batch)), ", ")
+		inserted += h.Exec(&SQLQuery{Query: query, Params: params}).RowsAffected
+	}
+	return inserted
+}
+
+// decodeInsertManyOptions reads the options passed to InsertMany. When called
+// from a migration they are an object, and unknown keys are an error.
+func decodeInsertManyOptions(opts []any) *InsertManyOptions {
+	options := &InsertManyOptions{BatchSize: DEFAULT_INSERT_BATCH_SIZE}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case nil:
+		case *InsertManyOptions:
+			if opt.BatchSize > 0 {
+				options.BatchSize = opt.BatchSize
+			}
+		case map[string]any:
+			for key, value := range opt {
+				switch key {
+				case "batchSize":
+					batchSize, ok := value.(int64)
+					if !ok || batchSize <= 0 {
+						panic(fmt.Errorf("insertMany options: batchSize must be a positive integer"))
+					}
+					options.BatchSize = int(batchSize)
+				default:
+					panic(fmt.Errorf("insertMany options: unknown option `%s`", key))
+				}
+			}
+		default:
+			panic(fmt.Errorf("insertMany options: must be an object"))
+		}
+	}
+	return options
+}
+
+// insertManyValues returns the columns of the rows, in sorted order, and the
+// values of each row in the order of the columns.
+func insertManyValues(rows []any) ([]string, [][]any) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var columns []string
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		rowMap, ok := row.(map[string]any)
+		if !ok {
+			panic(fmt.Errorf("insertMany: row %d is not an object", i))
+		}
+		if i == 0 {
+			for column := range rowMap {
+				columns = append(columns, column)
+			}
+			if len(columns) == 0 {
+				panic(fmt.Errorf("insertMany: row 0 has no columns"))
+			}
+			sort.Strings(columns)
+		}
+		if len(rowMap) != len(columns) {
+			panic(fmt.Errorf("insertMany: row %d has different columns than the first row", i))
+		}
+
+		values[i] = make([]any, len(columns))
+		for j, column := range columns {
+			value, ok := rowMap[column]
+			if !ok {
+				panic(fmt.Errorf("insertMany: row %d has different columns than the first row", i))
+			}
+			values[i][j] = value
+		}
+	}
+	return columns, values
+}
@@ -0,0 +1,32 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Handle_InsertMany(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	drv.db.ExecContext(ctx, "CREATE TABLE items (id INT PRIMARY KEY, name VARCHAR(64))")
+
+	rows := make([]any, 2500)
+	for i := range rows {
+		rows[i] = map[string]any{"id": int64(i + 1), "name": "item"}
+	}
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		inserted := drv.Handle(txCtx).(*Handle).InsertMany("items", rows, map[string]any{"batchSize": int64(1000)})
+		if inserted != 2500 {
+			t.Errorf("InsertMany() = %d, want 2500", inserted)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	row := drv.Handle(ctx).(*Handle).QueryOne(&SQLQuery{Query: "SELECT COUNT(*) AS count FROM items"})
+	if row["count"] != int64(2500) {
+		t.Errorf("count = %v, want 2500", row["count"])
+	}
+}
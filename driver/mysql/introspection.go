@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+// ColumnInfo describes a column of a table, as returned by ListColumns.
+type ColumnInfo struct {
+	Name string
+	// Type is the column's full type, such as varchar(255) or int unsigned.
+	Type     string
+	Nullable bool
+	// Default is the column's default, or nil when it has none. MySQL gives
+	// literal defaults as their value rather than as SQL.
+	Default    any
+	PrimaryKey bool
+}
+
+// IndexInfo describes an index of a table, as returned by ListIndexes.
+type IndexInfo struct {
+	Name string
+	// Columns are the indexed columns in order. Expressions are left out.
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// TableExists reports whether a table exists in the connection's database, or
+// in the database it is qualified with.
+func (h *Handle) TableExists(table string) bool {
+	schema, name := introspectionTableName(table)
+	return h.QueryOne(&SQLQuery{
+		Query:  "SELECT 1 AS found FROM information_schema.TABLES WHERE TABLE_SCHEMA = COALESCE(?, DATABASE()) AND TABLE_NAME = ? AND TABLE_TYPE = 'BASE TABLE'",
+		Params: []any{schema, name},
+	}) != nil
+}
+
+// ColumnExists reports whether a table has a column. Column names are matched
+// without regard to case, as MySQL does.
+func (h *Handle) ColumnExists(table string, column string) bool {
+	return slices.ContainsFunc(h.ListColumns(table), func(info ColumnInfo) bool {
+		return strings.EqualFold(info.Name, column)
+	})
+}
+
+// IndexExists reports whether a table has an index, including the indexes of
+// its UNIQUE constraints and foreign keys. The primary key's index is named
+// PRIMARY.
+func (h *Handle) IndexExists(table string, index string) bool {
+	return slices.ContainsFunc(h.ListIndexes(table), func(info IndexInfo) bool {
+		return strings.EqualFold(info.Name, index)
+	})
+}
+
+// ListTables returns the names of the tables in the connection's database in
+// order, apart from Graviton's own.
+func (h *Handle) ListTables() []string {
+	tables := []string{}
+	for _, row := range h.Query(&SQLQuery{Query: "SELECT TABLE_NAME AS name FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"}) {
+		name := row["name"].(string)
+		if !isGravitonTable(name) {
+			tables = append(tables, name)
+		}
+	}
+	return tables
+}
+
+// ListColumns returns the columns of a table in order, or none when there is
+// no such table.
+func (h *Handle) ListColumns(table string) []ColumnInfo {
+	schema, name := introspectionTableName(table)
+	columns := []ColumnInfo{}
+	for _, row := range h.Query(&SQLQuery{
+		Query: "SELECT COLUMN_NAME AS name, COLUMN_TYPE AS type, IS_NULLABLE AS nullable, COLUMN_DEFAULT AS `default`, COLUMN_KEY AS column_key" +
+			" FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = COALESCE(?, DATABASE()) AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+		Params: []any{schema, name},
+	}) {
+		columns = append(columns, ColumnInfo{
+			Name:       row["name"].(string),
+			Type:       row["type"].(string),
+			Nullable:   row["nullable"] == "YES",
+			Default:    row["default"],
+			PrimaryKey: row["column_key"] == "PRI",
+		})
+	}
+	return columns
+}
+
+// ListIndexes returns the indexes of a table by name, or none when there is no
+// such table.
+func (h *Handle) ListIndexes(table string) []IndexInfo {
+	schema, name := introspectionTableName(table)
+	indexes := []IndexInfo{}
+	for _, row := range h.Query(&SQLQuery{
+		Query: "SELECT INDEX_NAME AS name, NON_UNIQUE AS non_unique, COLUMN_NAME AS column_name" +
+			" FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = COALESCE(?, DATABASE()) AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX",
+		Params: []any{schema, name},
+	}) {
+		indexName := row["name"].(string)
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != indexName {
+			indexes = append(indexes, IndexInfo{
+				Name:    indexName,
+				Columns: []string{},
+				Unique:  row["non_unique"] == int64(0),
+				Primary: indexName == "PRIMARY",
+			})
+		}
+		if column, ok := row["column_name"].(string); ok {
+			index := &indexes[len(indexes)-1]
+			index.Columns = append(index.Columns, column)
+		}
+	}
+	return indexes
+}
+
+// introspectionTableName splits a table name into its database, which is nil
+// for the connection's database unless one is given, and its name.
+func introspectionTableName(table string) (any, string) {
+	schema, name := sqlschema.SplitTableName(table)
+	if schema == "" {
+		return nil, name
+	}
+	return schema, name
+}
+
+func isGravitonTable(name string) bool {
+	return name == MIGRATIONS_TABLE || name == SEEDS_TABLE || name == BACKFILLS_TABLE
+}
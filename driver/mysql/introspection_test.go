@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Handle_Introspection(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`
+		CREATE TABLE users (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			email VARCHAR(320) NOT NULL UNIQUE,
+			active BOOLEAN DEFAULT TRUE
+		);
+		CREATE INDEX users_active_email ON users (active, email);
+	`)
+
+	if !h.TableExists("users") || !h.TableExists(testDatabaseName+".users") || h.TableExists("posts") {
+		t.Errorf("TableExists() is wrong for users or posts")
+	}
+	if !h.ColumnExists("users", "EMAIL") || h.ColumnExists("users", "name") {
+		t.Errorf("ColumnExists() is wrong for email or name")
+	}
+	if !h.IndexExists("users", "email") || !h.IndexExists("users", "PRIMARY") || h.IndexExists("users", "users_email") {
+		t.Errorf("IndexExists() is wrong for email, PRIMARY or users_email")
+	}
+
+	if tables := h.ListTables(); !reflect.DeepEqual(tables, []string{"users"}) {
+		t.Errorf("ListTables() = %v, want [users]", tables)
+	}
+
+	wantColumns := []ColumnInfo{
+		{Name: "id", Type: "int", Nullable: false, PrimaryKey: true},
+		{Name: "email", Type: "varchar(320)", Nullable: false},
+		{Name: "active", Type: "tinyint(1)", Nullable: true, Default: "1"},
+	}
+	if columns := h.ListColumns("users"); !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("ListColumns() = %+v, want %+v", columns, wantColumns)
+	}
+
+	wantIndexes := []IndexInfo{
+		{Name: "email", Columns: []string{"email"}, Unique: true},
+		{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true},
+		{Name: "users_active_email", Columns: []string{"active", "email"}},
+	}
+	if indexes := h.ListIndexes("users"); !reflect.DeepEqual(indexes, wantIndexes) {
+		t.Errorf("ListIndexes() = %+v, want %+v", indexes, wantIndexes)
+	}
+}
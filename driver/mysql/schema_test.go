@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+func Test_Schema_Statements(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{
+			name: "create table",
+			got: func() string {
+				return createTableStatement("app.posts", sqlschema.DecodeTable("schema.createTable", map[string]any{
+					"columns": []any{
+						map[string]any{"name": "id", "type": "bigIncrements"},
+						map[string]any{"name": "author_id", "type": "integer", "nullable": false, "references": map[string]any{"table": "app.users", "onDelete": "cascade"}},
+						map[string]any{"name": "title", "type": "string", "length": int64(100), "default": `it's C:\new`},
+						map[string]any{"name": "published", "type": "boolean", "default": false},
+						map[string]any{"name": "price", "type": "decimal", "precision": int64(10), "scale": int64(2)},
+						map[string]any{"name": "body", "type": "text"},
+						map[string]any{"name": "created_at", "type": "timestamp", "defaultNow": true},
+					},
+					"unique": []any{[]any{"author_id", "title"}},
+				}))
+			},
+			want: "CREATE TABLE `app`.`posts` (\n" +
+				"  `id` BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
+				"  `author_id` INT NOT NULL,\n" +
+				"  `title` VARCHAR(100) DEFAULT 'it''s C:\\\\new',\n" +
+				"  `published` BOOLEAN DEFAULT FALSE,\n" +
+				"  `price` DECIMAL(10, 2),\n" +
+				"  `body` LONGTEXT,\n" +
+				"  `created_at` DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),\n" +
+				"  UNIQUE (`author_id`, `title`),\n" +
+				"  CONSTRAINT `posts_author_id_foreign` FOREIGN KEY (`author_id`) REFERENCES `app`.`users` (`id`) ON DELETE CASCADE\n" +
+				")",
+		},
+		{
+			name: "add column",
+			got: func() string {
+				return addColumnStatement("posts", sqlschema.DecodeColumn("schema.addColumn", map[string]any{
+					"name": "editor_id", "type": "integer",
+					"references": map[string]any{"table": "users"},
+				}))
+			},
+			want: "ALTER TABLE `posts` ADD COLUMN `editor_id` INT, ADD CONSTRAINT `posts_editor_id_foreign` FOREIGN KEY (`editor_id`) REFERENCES `users` (`id`)",
+		},
+		{
+			name: "create index",
+			got: func() string {
+				return createIndexStatement("app.users", sqlschema.DecodeIndex("schema.createIndex", []any{"last_name", "first_name"}, nil))
+			},
+			want: "CREATE INDEX `users_last_name_first_name_index` ON `app`.`users` (`last_name`, `first_name`)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); got != tt.want {
+				t.Errorf("statement =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Schema_CreateIndex_IfNotExists(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		want := "schema.createIndex: MySQL does not support ifNotExists for indexes"
+		if !ok || err.Error() != want {
+			t.Errorf("panic = %v, want %q", err, want)
+		}
+	}()
+	createIndexStatement("users", sqlschema.DecodeIndex("schema.createIndex", []any{"email"}, []any{map[string]any{"ifNotExists": true}}))
+}
+
+func Test_Schema_CreateTable(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	h.Schema.CreateTable("users", map[string]any{
+		"columns": []any{
+			map[string]any{"name": "id", "type": "increments"},
+			map[string]any{"name": "email", "type": "string", "nullable": false, "unique": true},
+			map[string]any{"name": "created_at", "type": "timestamp", "defaultNow": true},
+		},
+	})
+	h.Schema.AddColumn("users", map[string]any{"name": "score", "type": "integer", "default": int64(10)})
+	h.Schema.CreateIndex("users", []any{"score"})
+	h.Exec(&SQLQuery{Query: "INSERT INTO users (email) VALUES ('ada@example.com')"})
+
+	user := h.QueryOne(&SQLQuery{Query: "SELECT id, score FROM users"})
+	if user["id"] != int64(1) || user["score"] != int64(10) {
+		t.Errorf("user = %v, want id 1 and score 10", user)
+	}
+}
@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/telemetryos/graviton/schematypes"
+)
+
+// SchemaTypes describes the tables of the connection's database as the rows
+// migrations read from them, for `graviton types`.
+func (d *Driver) SchemaTypes(ctx context.Context) (schema *schematypes.Schema, returnErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				returnErr = e
+			} else {
+				returnErr = fmt.Errorf("panic in schema types: %v", r)
+			}
+		}
+	}()
+
+	h := newHandle(ctx, d)
+	schema = &schematypes.Schema{Namespace: "Tables"}
+	for _, table := range h.ListTables() {
+		t := &schematypes.Type{Name: table}
+		for _, column := range h.ListColumns(table) {
+			fieldType := columnTypeScriptType(column.Type)
+			if column.Nullable {
+				fieldType = schematypes.Nullable(fieldType)
+			}
+			t.Fields = append(t.Fields, &schematypes.Field{Name: column.Name, Type: fieldType})
+		}
+		schema.Types = append(schema.Types, t)
+	}
+	return schema, nil
+}
+
+// columnTypeScriptType returns the TypeScript type of the values decodeValue
+// reads from a column, given its COLUMN_TYPE such as int unsigned. ENUM
+// columns are typed as their values. A BIGINT, or a BIT wider than 53 bits,
+// outside the safe range is read as a string.
+func columnTypeScriptType(columnType string) string {
+	typeName, _, _ := strings.Cut(columnType, " ")
+	typeName, args, _ := strings.Cut(typeName, "(")
+	switch typeName {
+	case "bigint":
+		return "number | string"
+	case "bit":
+		if width, err := strconv.Atoi(strings.TrimSuffix(args, ")")); err == nil && width > 53 {
+			return "number | string"
+		}
+		return "number"
+	case "tinyint", "smallint", "mediumint", "int", "year", "float", "double":
+		return "number"
+	case "json":
+		return "any"
+	case "date", "datetime", "timestamp":
+		return "Date"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "vector",
+		"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection":
+		return "Uint8Array"
+	case "enum":
+		var values []string
+		for _, value := range enumValues(strings.TrimSuffix(args, ")")) {
+			quoted, _ := json.Marshal(value)
+			values = append(values, string(quoted))
+		}
+		return schematypes.Union(values...)
+	default:
+		return "string"
+	}
+}
+
+// enumValues splits the quoted values of an ENUM column's type, such as
+// 'active','banned'. Quotes within a value are doubled.
+func enumValues(list string) []string {
+	var values []string
+	var value strings.Builder
+	quoted := false
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; {
+		case c == '\'' && quoted && i+1 < len(list) && list[i+1] == '\'':
+			value.WriteByte('\'')
+			i++
+		case c == '\'' && quoted:
+			values = append(values, value.String())
+			value.Reset()
+			quoted = false
+		case c == '\'':
+			quoted = true
+		case quoted:
+			value.WriteByte(c)
+		}
+	}
+	return values
+}
@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func Test_ColumnTypeScriptType(t *testing.T) {
+	tests := map[string]string{
+		"int":                 "number",
+		"bigint unsigned":     "number | string",
+		"bit(1)":              "number",
+		"bit(64)":             "number | string",
+		"tinyint(1)":          "number",
+		"decimal(10,2)":       "string",
+		"varchar(255)":        "string",
+		"json":                "any",
+		"datetime(3)":         "Date",
+		"time":                "string",
+		"longblob":            "Uint8Array",
+		"point":               "Uint8Array",
+		"enum('a','it''s')":   `"a" | "it's"`,
+		"set('read','write')": "string",
+	}
+	for columnType, want := range tests {
+		if got := columnTypeScriptType(columnType); got != want {
+			t.Errorf("columnTypeScriptType(%q) = %q, want %q", columnType, got, want)
+		}
+	}
+}
+
+func Test_Driver_SchemaTypes(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`CREATE TABLE users (id INT AUTO_INCREMENT PRIMARY KEY, email TEXT, status ENUM('active', 'banned') NOT NULL)`)
+
+	schema, err := drv.SchemaTypes(ctx)
+	if err != nil {
+		t.Fatalf("SchemaTypes() error = %v", err)
+	}
+	if len(schema.Types) != 1 || len(schema.Types[0].Fields) != 3 {
+		t.Fatalf("SchemaTypes() = %+v, want the users table", schema)
+	}
+	for i, want := range []string{"number", "string | null", `"active" | "banned"`} {
+		if field := schema.Types[0].Fields[i]; field.Type != want {
+			t.Errorf("field %s type = %q, want %q", field.Name, field.Type, want)
+		}
+	}
+}
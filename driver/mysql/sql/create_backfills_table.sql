@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS {{.TableName}} (
+    filename VARCHAR(255) PRIMARY KEY,
+    checkpoint TEXT NOT NULL,
+    updated_at DATETIME NOT NULL
+);
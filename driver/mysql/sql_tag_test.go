@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/telemetryos/graviton/config"
+
+	"github.com/dop251/goja"
+)
+
+func setupTestRuntime(t *testing.T) (*Driver, *goja.Runtime) {
+	t.Helper()
+
+	drv := New(&config.DatabaseConfig{})
+	runtime := goja.New()
+	ctx := context.Background()
+
+	drv.Init(ctx, runtime)
+	for name, value := range drv.Globals(ctx, runtime) {
+		runtime.Set(name, value)
+	}
+
+	return drv, runtime
+}
+
+func Test_SQLTag(t *testing.T) {
+	drv, runtime := setupTestRuntime(t)
+
+	tests := []struct {
+		name           string
+		src            string
+		expectedQuery  string
+		expectedParams []any
+	}{
+		{
+			"parameters",
+			"sql`SELECT * FROM users WHERE id = ${1} AND name = ${'Ada'}`",
+			"SELECT * FROM users WHERE id = ? AND name = ?",
+			[]any{int64(1), "Ada"},
+		},
+		{
+			"identifiers",
+			"sql`SELECT ${sql.ident('full name')} FROM ${sql.ident('shop', 'users')} WHERE ${sql.ident('a`b')} = ${1}`",
+			"SELECT `full name` FROM `shop`.`users` WHERE `a``b` = ?",
+			[]any{int64(1)},
+		},
+		{
+			"raw",
+			"sql`SELECT * FROM users ORDER BY id ${sql.raw('DESC')}`",
+			"SELECT * FROM users ORDER BY id DESC",
+			nil,
+		},
+		{
+			"nested queries",
+			"const where = sql`status = ${'active'} AND age > ${18}`; sql`SELECT * FROM users WHERE id > ${5} AND ${where} LIMIT ${10}`",
+			"SELECT * FROM users WHERE id > ? AND status = ? AND age > ? LIMIT ?",
+			[]any{int64(5), "active", int64(18), int64(10)},
+		},
+		{
+			"array expansion",
+			"sql`SELECT * FROM users WHERE id IN (${[1, 2, 3]})`",
+			"SELECT * FROM users WHERE id IN (?, ?, ?)",
+			[]any{int64(1), int64(2), int64(3)},
+		},
+		{
+			"values lists",
+			"sql`INSERT INTO users (id, name) VALUES ${[[1, 'Ada'], [2, 'Grace']]}`",
+			"INSERT INTO users (id, name) VALUES (?, ?), (?, ?)",
+			[]any{int64(1), "Ada", int64(2), "Grace"},
+		},
+		{
+			"join",
+			"sql`UPDATE users SET ${sql.join([sql`name = ${'Ada'}`, sql`age = ${36}`])} WHERE id = ${1}`",
+			"UPDATE users SET name = ?, age = ? WHERE id = ?",
+			[]any{"Ada", int64(36), int64(1)},
+		},
+		{
+			"join with separator",
+			"sql`SELECT * FROM users WHERE ${sql.join([sql`a = ${1}`, sql`b = ${2}`], ' AND ')}`",
+			"SELECT * FROM users WHERE a = ? AND b = ?",
+			[]any{int64(1), int64(2)},
+		},
+		{
+			"join of identifiers",
+			"sql`SELECT ${sql.join(['id', 'name'].map(c => sql.ident(c)))} FROM users`",
+			"SELECT `id`, `name` FROM users",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			val, err := runtime.RunString(test.src)
+			if err != nil {
+				t.Fatalf("RunString() error = %v", err)
+			}
+			sqlQuery := SQLQueryFromJSValue(runtime, val)
+			if sqlQuery.Query != test.expectedQuery {
+				t.Errorf("query = %q, want %q", sqlQuery.Query, test.expectedQuery)
+			}
+			if len(sqlQuery.Params) != 0 || len(test.expectedParams) != 0 {
+				if !reflect.DeepEqual(sqlQuery.Params, test.expectedParams) {
+					t.Errorf("params = %#v, want %#v", sqlQuery.Params, test.expectedParams)
+				}
+			}
+			if _, ok := drv.MaybeFromJSValue(context.Background(), runtime, val); !ok {
+				t.Error("MaybeFromJSValue() ok = false, want true")
+			}
+		})
+	}
+}
+
+func Test_SQLTag_Errors(t *testing.T) {
+	_, runtime := setupTestRuntime(t)
+
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"empty array", "sql`SELECT * FROM users WHERE id IN (${[]})`", "sql tag: cannot expand an empty array"},
+		{"empty identifier", "sql.ident('')", "sql.ident: invalid identifier"},
+		{"non-string identifier", "sql.ident(1)", "sql.ident: invalid identifier 1"},
+		{"non-string raw", "sql.raw(1)", "sql.raw: argument must be a string"},
+		{"join without array", "sql.join('a')", "sql.join: first argument must be an array"},
+		{"nested constructed query", "sql`SELECT ${new SQLQuery('1', [])}`", "only queries created with the sql tag can be nested"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runtime.RunString(test.src)
+			if err == nil {
+				t.Fatal("RunString() error = nil, want an error")
+			}
+			if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("RunString() error = %q, want %q", err.Error(), test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package mysql
+
+import (
+	_ "embed"
+)
+
+//go:embed migration.d.ts
+var MigrationTypeDefTemplate []byte
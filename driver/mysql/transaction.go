@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// SAVEPOINT_DOES_NOT_EXIST_NUMBER is the error number MySQL reports for a
+// savepoint that was released, as it is by the implicit commit of DDL.
+const SAVEPOINT_DOES_NOT_EXIST_NUMBER = 1305
+
+var savepointCount atomic.Int64
+
+// Transaction calls fn with a handle whose work can be rolled back on its own.
+// Inside a migration's transaction fn runs in a savepoint. When fn throws, only
+// its changes are rolled back and the error is rethrown, so the migration can
+// catch it and continue. Outside a transaction fn runs in a transaction of its
+// own.
+//
+// MySQL commits the transaction implicitly before and after most DDL
+// statements, which also releases the savepoint, so only data changes can be
+// rolled back this way. A savepoint released by DDL is not an error, and when
+// fn throws after DDL its error is rethrown unchanged.
+func (h *Handle) Transaction(fn func(db *Handle) any) (result any) {
+	tx := h.driver.getTxFromContext(h.ctx)
+	if tx == nil {
+		err := h.driver.WithTransaction(h.ctx, func(txCtx context.Context) error {
+			result = fn(newHandle(txCtx, h.driver))
+			return nil
+		})
+		if err != nil {
+			panic(err)
+		}
+		return result
+	}
+
+	savepoint := fmt.Sprintf("graviton_savepoint_%d", savepointCount.Add(1))
+	if _, err := tx.ExecContext(h.ctx, "SAVEPOINT "+savepoint); err != nil {
+		panic(err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if _, err := tx.ExecContext(h.ctx, "ROLLBACK TO SAVEPOINT "+savepoint); isSavepointReleased(err) {
+				// DDL already committed fn's work, so there is nothing left
+				// to roll back.
+				panic(r)
+			} else if err != nil {
+				panic(fmt.Errorf("failed to roll back to savepoint after %v: %w", r, err))
+			}
+			if _, err := tx.ExecContext(h.ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+				panic(err)
+			}
+			panic(r)
+		}
+	}()
+
+	result = fn(h)
+
+	if _, err := tx.ExecContext(h.ctx, "RELEASE SAVEPOINT "+savepoint); err != nil && !isSavepointReleased(err) {
+		panic(err)
+	}
+	return result
+}
+
+func isSavepointReleased(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == SAVEPOINT_DOES_NOT_EXIST_NUMBER
+}
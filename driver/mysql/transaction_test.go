@@ -0,0 +1,115 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func Test_Handle_Transaction_RollsBackToSavepoint(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	drv.db.ExecContext(ctx, "CREATE TABLE test (value VARCHAR(64) UNIQUE)")
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		h := drv.Handle(txCtx).(*Handle)
+		h.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES (?)", Params: []any{"outer"}})
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Transaction() did not rethrow the error")
+				}
+			}()
+			h.Transaction(func(db *Handle) any {
+				db.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES (?)", Params: []any{"inner"}})
+				db.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES (?)", Params: []any{"outer"}})
+				return nil
+			})
+		}()
+
+		result := h.Transaction(func(db *Handle) any {
+			db.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES (?)", Params: []any{"after"}})
+			return "done"
+		})
+		if result != "done" {
+			t.Errorf("Transaction() = %v, want done", result)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	rows := drv.Handle(ctx).(*Handle).Query(&SQLQuery{Query: "SELECT value FROM test ORDER BY value"})
+	var values []string
+	for _, row := range rows {
+		values = append(values, row["value"].(string))
+	}
+	if len(values) != 2 || values[0] != "after" || values[1] != "outer" {
+		t.Errorf("values = %v, want [after outer]", values)
+	}
+}
+
+func Test_Handle_Transaction_DDL(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	drv.db.ExecContext(ctx, "CREATE TABLE test (value VARCHAR(64))")
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		h := drv.Handle(txCtx).(*Handle)
+		h.Transaction(func(db *Handle) any {
+			db.Exec(&SQLQuery{Query: "CREATE INDEX test_value ON test (value)"})
+			return nil
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v, want the savepoint released by DDL to be ignored", err)
+	}
+
+	if !drv.Handle(ctx).(*Handle).IndexExists("test", "test_value") {
+		t.Error("IndexExists() = false, want the index created in Transaction()")
+	}
+}
+
+func Test_Handle_Transaction_ThrowsAfterDDL(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	drv.db.ExecContext(ctx, "CREATE TABLE test (value VARCHAR(64))")
+
+	thrown := errors.New("thrown after DDL")
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		h := drv.Handle(txCtx).(*Handle)
+		func() {
+			defer func() {
+				if r := recover(); r != thrown {
+					t.Errorf("Transaction() threw %v, want %v", r, thrown)
+				}
+			}()
+			h.Transaction(func(db *Handle) any {
+				db.Exec(&SQLQuery{Query: "CREATE INDEX test_value ON test (value)"})
+				panic(thrown)
+			})
+		}()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	if !drv.Handle(ctx).(*Handle).IndexExists("test", "test_value") {
+		t.Error("IndexExists() = false, want the index committed by DDL")
+	}
+}
+
+func Test_IsSavepointReleased(t *testing.T) {
+	if !isSavepointReleased(&mysql.MySQLError{Number: SAVEPOINT_DOES_NOT_EXIST_NUMBER}) {
+		t.Error("isSavepointReleased() = false for error 1305")
+	}
+	if isSavepointReleased(&mysql.MySQLError{Number: 1062}) || isSavepointReleased(errors.New("1305")) {
+		t.Error("isSavepointReleased() = true for another error")
+	}
+}
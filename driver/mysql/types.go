@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MAX_SAFE_INTEGER is the largest integer a JavaScript number holds exactly.
+// The migration runtime has no BigInt, so larger integers are returned as
+// strings rather than silently rounded.
+const MAX_SAFE_INTEGER = 1<<53 - 1
+
+// decodeValue converts a value scanned from a column of the given type, as
+// reported by ColumnType.DatabaseTypeName, into the value migrations see. The
+// MySQL driver returns most columns as raw bytes, so the column type decides
+// how they are read.
+//
+//   - integer types, including YEAR and BIT, become numbers, or strings outside
+//     the safe integer range
+//   - FLOAT and DOUBLE become numbers
+//   - DECIMAL becomes a string so no precision is lost
+//   - JSON is parsed
+//   - DATE, DATETIME and TIMESTAMP become Dates, with zero dates as null; TIME
+//     becomes a string
+//   - BLOB, BINARY, VARBINARY and GEOMETRY become Uint8Arrays
+//   - everything else becomes a string
+func decodeValue(typeName string, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch typeName {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "YEAR":
+		switch v := value.(type) {
+		case int64:
+			return safeInteger(v), nil
+		case []byte:
+			n, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return safeInteger(n), nil
+		}
+	case "UNSIGNED TINYINT", "UNSIGNED SMALLINT", "UNSIGNED MEDIUMINT", "UNSIGNED INT", "UNSIGNED BIGINT":
+		switch v := value.(type) {
+		case int64:
+			return safeInteger(v), nil
+		case uint64:
+			return safeUnsignedInteger(v), nil
+		case []byte:
+			n, err := strconv.ParseUint(string(v), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return safeUnsignedInteger(n), nil
+		}
+	case "BIT":
+		if v, ok := value.([]byte); ok {
+			if len(v) > 8 {
+				return nil, fmt.Errorf("BIT value of %d bytes is too large", len(v))
+			}
+			padded := make([]byte, 8)
+			copy(padded[8-len(v):], v)
+			return safeUnsignedInteger(binary.BigEndian.Uint64(padded)), nil
+		}
+	case "FLOAT", "DOUBLE":
+		switch v := value.(type) {
+		case float32:
+			return float64(v), nil
+		case []byte:
+			return strconv.ParseFloat(string(v), 64)
+		}
+	case "JSON":
+		v, ok := value.([]byte)
+		if !ok {
+			break
+		}
+		var parsed any
+		if err := json.Unmarshal(v, &parsed); err != nil {
+			return nil, err
+		}
+		return parsed, nil
+	case "DATE", "DATETIME", "TIMESTAMP":
+		if v, ok := value.([]byte); ok {
+			return parseTime(string(v))
+		}
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "GEOMETRY", "VECTOR":
+		return value, nil
+	}
+
+	if v, ok := value.([]byte); ok {
+		return string(v), nil
+	}
+	return value, nil
+}
+
+// parseTime parses DATE and DATETIME values read without parseTime=true in the
+// connection URL. Like the driver itself, they are read as UTC.
+func parseTime(value string) (any, error) {
+	if strings.HasPrefix(value, "0000-00-00") {
+		return nil, nil
+	}
+	layout := "2006-01-02 15:04:05.999999"
+	if len(value) == len("2006-01-02") {
+		layout = "2006-01-02"
+	}
+	return time.ParseInLocation(layout, value, time.UTC)
+}
+
+func safeInteger(n int64) any {
+	if n > MAX_SAFE_INTEGER || n < -MAX_SAFE_INTEGER {
+		return strconv.FormatInt(n, 10)
+	}
+	return n
+}
+
+func safeUnsignedInteger(n uint64) any {
+	if n > MAX_SAFE_INTEGER {
+		return strconv.FormatUint(n, 10)
+	}
+	return int64(n)
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_DecodeValue(t *testing.T) {
+	tests := []struct {
+		typeName string
+		value    any
+		expected any
+	}{
+		{"INT", []byte("42"), int64(42)},
+		{"INT", int64(-7), int64(-7)},
+		{"BIGINT", []byte("9007199254740993"), "9007199254740993"},
+		{"UNSIGNED BIGINT", []byte("18446744073709551615"), "18446744073709551615"},
+		{"UNSIGNED BIGINT", uint64(18446744073709551615), "18446744073709551615"},
+		{"UNSIGNED INT", []byte("4294967295"), int64(4294967295)},
+		{"YEAR", []byte("2024"), int64(2024)},
+		{"BIT", []byte{0x01, 0x00}, int64(256)},
+		{"DOUBLE", []byte("1.5"), 1.5},
+		{"FLOAT", float32(0.5), 0.5},
+		{"DECIMAL", []byte("12345678901234567890.01"), "12345678901234567890.01"},
+		{"JSON", []byte(`{"a": [1, null]}`), map[string]any{"a": []any{1.0, nil}}},
+		{"VARCHAR", []byte("Ada"), "Ada"},
+		{"TEXT", []byte("notes"), "notes"},
+		{"ENUM", []byte("small"), "small"},
+		{"TIME", []byte("10:30:00"), "10:30:00"},
+		{"BLOB", []byte{0, 1, 2}, []byte{0, 1, 2}},
+		{"VARBINARY", []byte{3}, []byte{3}},
+		{"DATETIME", []byte("0000-00-00 00:00:00"), nil},
+		{"VARCHAR", nil, nil},
+	}
+
+	for _, test := range tests {
+		got, err := decodeValue(test.typeName, test.value)
+		if err != nil {
+			t.Errorf("decodeValue(%s, %q) error = %v", test.typeName, test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("decodeValue(%s, %q) = %#v, want %#v", test.typeName, test.value, got, test.expected)
+		}
+	}
+}
+
+func Test_DecodeValue_Times(t *testing.T) {
+	tests := []struct {
+		typeName string
+		value    any
+		expected time.Time
+	}{
+		{"DATETIME", []byte("2024-03-01 10:30:00.25"), time.Date(2024, 3, 1, 10, 30, 0, 250000000, time.UTC)},
+		{"TIMESTAMP", []byte("2024-03-01 10:30:00"), time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"DATE", []byte("2024-03-01"), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"DATETIME", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		got, err := decodeValue(test.typeName, test.value)
+		if err != nil {
+			t.Errorf("decodeValue(%s, %q) error = %v", test.typeName, test.value, err)
+			continue
+		}
+		if ts, ok := got.(time.Time); !ok || !ts.Equal(test.expected) {
+			t.Errorf("decodeValue(%s, %q) = %#v, want %v", test.typeName, test.value, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// PARSE_ERROR_NUMBER is the error number MySQL reports for statements it
+// cannot parse.
+const PARSE_ERROR_NUMBER = 1064
+
+// ValidateSQL checks the syntax of a statement by having the server parse it
+// as a prepared statement. Only syntax errors are reported. Errors such as a
+// missing table are ignored, since the table may be created by an earlier
+// statement in the same migration. Several statements cannot be prepared
+// together, so each is checked on its own.
+func (d *Driver) ValidateSQL(ctx context.Context, statement string) error {
+	if d.db == nil {
+		return nil
+	}
+	for _, statement := range splitStatements(statement) {
+		if err := d.validateStatement(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) validateStatement(ctx context.Context, statement string) error {
+	stmt, err := d.db.PrepareContext(ctx, statement)
+	if err == nil {
+		return stmt.Close()
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != PARSE_ERROR_NUMBER {
+		return nil
+	}
+	return errors.New(mysqlErr.Message)
+}
+
+// StrictSQLValidation reports whether invalid SQL fails a migration when it is
+// compiled, rather than printing a warning.
+func (d *Driver) StrictSQLValidation() bool {
+	return d.config.StrictSQLValidation
+}
+
+func (d *Driver) Placeholder(index int) string {
+	return "?"
+}
+
+func (d *Driver) QuoteIdentifier(names ...string) string {
+	return quoteIdentifier(names)
+}
+
+func quoteIdentifier(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return strings.Join(quoted, ".")
+}
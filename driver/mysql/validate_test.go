@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Driver_ValidateSQL(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	valid := []string{
+		"CREATE TABLE users (id INT AUTO_INCREMENT PRIMARY KEY, email VARCHAR(255) NOT NULL)",
+		"INSERT INTO users (email) VALUES (?) ON DUPLICATE KEY UPDATE email = VALUES(email)",
+		"SELECT * FROM missing WHERE id = ?",
+		"CREATE TABLE a (id INT); CREATE TABLE b (id INT)",
+		"INSERT INTO a VALUES (';')",
+	}
+	for _, statement := range valid {
+		if err := drv.ValidateSQL(ctx, statement); err != nil {
+			t.Errorf("ValidateSQL(%q) error = %v, want nil", statement, err)
+		}
+	}
+
+	invalid := []string{
+		"CREATE TABL users (id INT)",
+		"SELECT * FROM users WHERE",
+		"CREATE TABLE a (id INT); CREATE TABL b (id INT)",
+	}
+	for _, statement := range invalid {
+		err := drv.ValidateSQL(ctx, statement)
+		if err == nil || !strings.Contains(err.Error(), "syntax") {
+			t.Errorf("ValidateSQL(%q) error = %v, want a syntax error", statement, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+)
+
+const DEFAULT_CURSOR_BATCH_SIZE = 1000
+
+var cursorCount atomic.Int64
+
+// Cursor reads the rows of a query a batch at a time, so results too large to
+// hold in memory can be processed row by row.
+//
+// Inside a transaction the query is declared as a server-side cursor and each
+// batch is fetched with FETCH. The connection is free between batches, so
+// rows can be updated while the cursor is being read.
+type Cursor struct {
+	ctx       context.Context
+	statement string
+	tx        *sql.Tx
+	name      string
+	rows      *sql.Rows
+	batchSize int
+	batch     []map[string]any
+	done      bool
+}
+
+// Cursor runs the query and returns a cursor over its rows. Outside of a
+// transaction the cursor holds a connection until it is read to the end or
+// closed.
+func (h *Handle) Cursor(sqlQuery *SQLQuery, batchSize ...int) *Cursor {
+	cursor := &Cursor{
+		ctx:       h.ctx,
+		statement: sqlQuery.Query,
+		batchSize: cursorBatchSize(batchSize),
+	}
+
+	tx := h.driver.getTxFromContext(h.ctx)
+	if tx == nil {
+		cursor.rows = h.queryRows(sqlQuery)
+		return cursor
+	}
+
+	cursor.tx = tx
+	cursor.name = fmt.Sprintf("graviton_cursor_%d", cursorCount.Add(1))
+	declareSQL := "DECLARE " + cursor.name + " NO SCROLL CURSOR FOR " + sqlQuery.Query
+	if _, err := tx.ExecContext(h.ctx, declareSQL, sqlQuery.Params...); err != nil {
+		panic(&DatabaseError{Err: err, Statement: sqlQuery.Query})
+	}
+	return cursor
+}
+
+// ForEach calls fn with each row of the query. Returning false from fn stops
+// the iteration.
+func (h *Handle) ForEach(sqlQuery *SQLQuery, fn func(row map[string]any) any, batchSize ...int) {
+	h.Cursor(sqlQuery, batchSize...).ForEach(fn)
+}
+
+func (c *Cursor) HasNext() bool {
+	if len(c.batch) == 0 && !c.done {
+		c.batch = c.readBatch()
+	}
+	return len(c.batch) != 0
+}
+
+// Next returns the next row, or nil when there are no rows left.
+func (c *Cursor) Next() map[string]any {
+	if !c.HasNext() {
+		return nil
+	}
+	row := c.batch[0]
+	c.batch = c.batch[1:]
+	return row
+}
+
+// ForEach calls fn with each remaining row and then closes the cursor.
+// Returning false from fn stops the iteration.
+func (c *Cursor) ForEach(fn func(row map[string]any) any) {
+	defer c.Close()
+	for c.HasNext() {
+		if proceed, ok := fn(c.Next()).(bool); ok && !proceed {
+			return
+		}
+	}
+}
+
+func (c *Cursor) ToArray() []map[string]any {
+	results := []map[string]any{}
+	for c.HasNext() {
+		results = append(results, c.Next())
+	}
+	return results
+}
+
+func (c *Cursor) Close() {
+	c.batch = nil
+	if c.done {
+		return
+	}
+	c.done = true
+	if c.rows != nil {
+		c.rows.Close()
+		return
+	}
+	// The error is ignored as the cursor is closed with the transaction
+	// anyway, and Close runs after errors that abort the transaction.
+	c.tx.ExecContext(c.ctx, "CLOSE "+c.name)
+}
+
+func (c *Cursor) readBatch() []map[string]any {
+	rows := c.rows
+	if c.tx != nil {
+		var err error
+		rows, err = c.tx.QueryContext(c.ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", c.batchSize, c.name))
+		if err != nil {
+			c.Close()
+			panic(&DatabaseError{Err: err, Statement: c.statement})
+		}
+		defer rows.Close()
+	}
+
+	batch, err := readRows(rows, c.batchSize)
+	if err != nil {
+		c.Close()
+		panic(&DatabaseError{Err: err, Statement: c.statement})
+	}
+	if len(batch) < c.batchSize {
+		c.Close()
+	}
+	return batch
+}
+
+func cursorBatchSize(batchSize []int) int {
+	if len(batchSize) != 0 && batchSize[0] > 0 {
+		return batchSize[0]
+	}
+	return DEFAULT_CURSOR_BATCH_SIZE
+}
@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/telemetryos/graviton/diagnostics"
+)
+
+const DIAGNOSE_PROBE_TABLE = "graviton_doctor_probe"
+
+func (d *Driver) Diagnose(ctx context.Context) []*diagnostics.Check {
+	db, err := sql.Open("postgres", d.config.ConnectionUrl)
+	if err == nil {
+		err = db.PingContext(ctx)
+	}
+	if err != nil {
+		return []*diagnostics.Check{
+			diagnostics.Fail("connection", err.Error(), "Check connection_url and that the server is reachable"),
+		}
+	}
+	defer db.Close()
+
+	checks := []*diagnostics.Check{diagnostics.Pass("connection", "connected")}
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		checks = append(checks, diagnostics.Warn("server version", err.Error(), "The server version could not be determined"))
+	} else {
+		checks = append(checks, diagnostics.Pass("server version", "PostgreSQL "+version))
+	}
+
+	checks = append(checks, d.diagnoseReadOnly(ctx, db))
+	for _, tableName := range []string{MIGRATIONS_TABLE, SEEDS_TABLE} {
+		checks = append(checks, d.diagnoseTrackingTable(ctx, db, tableName))
+	}
+	checks = append(checks, d.diagnoseWritePermission(ctx, db))
+
+	return checks
+}
+
+func (d *Driver) diagnoseReadOnly(ctx context.Context, db *sql.DB) *diagnostics.Check {
+	var inRecovery bool
+	if err := db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err != nil {
+		return diagnostics.Warn("read-only", err.Error(), "Recovery status could not be determined")
+	}
+	if inRecovery {
+		return diagnostics.Fail("read-only", "server is a standby in recovery", "Point connection_url at the primary server")
+	}
+
+	var transactionReadOnly string
+	if err := db.QueryRowContext(ctx, "SHOW transaction_read_only").Scan(&transactionReadOnly); err != nil {
+		return diagnostics.Warn("read-only", err.Error(), "Read-only status could not be determined")
+	}
+	if transactionReadOnly == "on" {
+		return diagnostics.Fail(
+			"read-only",
+			"transactions are read-only by default",
+			"Disable default_transaction_read_only for the user or database Graviton connects as",
+		)
+	}
+
+	return diagnostics.Pass("read-only", "server accepts writes")
+}
+
+func (d *Driver) diagnoseTrackingTable(ctx context.Context, db *sql.DB, tableName string) *diagnostics.Check {
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", tableName).Scan(&exists); err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "Check the user can read the system catalogs")
+	}
+	if !exists {
+		return diagnostics.Pass(tableName, "does not exist yet and will be created on first use")
+	}
+
+	query, err := d.renderSQL(getMigrationsSQL, tableName)
+	if err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "")
+	}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "Grant SELECT, INSERT and DELETE on "+tableName+" to the user Graviton connects as")
+	}
+	rows.Close()
+
+	return diagnostics.Pass(tableName, "readable")
+}
+
+func (d *Driver) diagnoseWritePermission(ctx context.Context, db *sql.DB) *diagnostics.Check {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return diagnostics.Fail("write permission", err.Error(), "")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE "+DIAGNOSE_PROBE_TABLE+" (id integer)"); err != nil {
+		return diagnostics.Fail("write permission", "cannot create tables: "+err.Error(), "Grant CREATE on the schema to the user Graviton connects as")
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+DIAGNOSE_PROBE_TABLE+" (id) VALUES (1)"); err != nil {
+		return diagnostics.Fail("write permission", "cannot insert rows: "+err.Error(), "")
+	}
+
+	return diagnostics.Pass("write permission", "can create tables and insert rows")
+}
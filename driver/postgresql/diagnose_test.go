@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/telemetryos/graviton/diagnostics"
+)
+
+func Test_Driver_Diagnose(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	checks := drv.Diagnose(ctx)
+	if diagnostics.Failed(checks) {
+		for _, check := range checks {
+			t.Logf("%s %s: %s", check.Status, check.Name, check.Message)
+		}
+		t.Fatalf("Diagnose() reported failures for a writable database")
+	}
+
+	var probeTables int
+	drv.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_name = '"+DIAGNOSE_PROBE_TABLE+"'").Scan(&probeTables)
+	if probeTables != 0 {
+		t.Errorf("Diagnose() left the probe table behind")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	_ "embed"
 	"fmt"
 	"text/template"
+	"time"
 
 	"github.com/telemetryos/graviton/config"
 	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
@@ -16,6 +17,8 @@ import (
 )
 
 const MIGRATIONS_TABLE = "graviton_migrations"
+const SEEDS_TABLE = "graviton_seeds"
+const BACKFILLS_TABLE = "graviton_backfills"
 
 //go:embed sql/create_migrations_table.sql
 var createMigrationsTableSQL string
@@ -29,6 +32,18 @@ var deleteAllMigrationsSQL string
 //go:embed sql/insert_migration.sql
 var insertMigrationSQL string
 
+//go:embed sql/create_backfills_table.sql
+var createBackfillsTableSQL string
+
+//go:embed sql/get_backfill.sql
+var getBackfillSQL string
+
+//go:embed sql/delete_backfill.sql
+var deleteBackfillSQL string
+
+//go:embed sql/insert_backfill.sql
+var insertBackfillSQL string
+
 type contextKey string
 
 const txContextKey contextKey = "postgresql_tx"
@@ -63,13 +78,23 @@ func (d *Driver) Connect(ctx context.Context) error {
 
 	d.db = db
 
-	createSQL, err := d.renderSQL(createMigrationsTableSQL)
+	for _, tableName := range []string{MIGRATIONS_TABLE, SEEDS_TABLE} {
+		createSQL, err := d.renderSQL(createMigrationsTableSQL, tableName)
+		if err != nil {
+			return fmt.Errorf("failed to render create table SQL: %w", err)
+		}
+
+		if _, err := d.db.ExecContext(ctx, createSQL); err != nil {
+			return fmt.Errorf("failed to create %s table: %w", tableName, err)
+		}
+	}
+
+	createSQL, err := d.renderSQL(createBackfillsTableSQL, BACKFILLS_TABLE)
 	if err != nil {
 		return fmt.Errorf("failed to render create table SQL: %w", err)
 	}
-
 	if _, err := d.db.ExecContext(ctx, createSQL); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
+		return fmt.Errorf("failed to create %s table: %w", BACKFILLS_TABLE, err)
 	}
 
 	return nil
@@ -83,7 +108,23 @@ func (d *Driver) Disconnect(ctx context.Context) error {
 }
 
 func (d *Driver) GetAppliedMigrationsMetadata(ctx context.Context) ([]*migrationsmeta.MigrationMetadata, error) {
-	query, err := d.renderSQL(getMigrationsSQL)
+	return d.getMetadata(ctx, MIGRATIONS_TABLE)
+}
+
+func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMetadata []*migrationsmeta.MigrationMetadata) error {
+	return d.setMetadata(ctx, MIGRATIONS_TABLE, migrationsMetadata)
+}
+
+func (d *Driver) GetAppliedSeedsMetadata(ctx context.Context) ([]*migrationsmeta.MigrationMetadata, error) {
+	return d.getMetadata(ctx, SEEDS_TABLE)
+}
+
+func (d *Driver) SetAppliedSeedsMetadata(ctx context.Context, seedsMetadata []*migrationsmeta.MigrationMetadata) error {
+	return d.setMetadata(ctx, SEEDS_TABLE, seedsMetadata)
+}
+
+func (d *Driver) getMetadata(ctx context.Context, tableName string) ([]*migrationsmeta.MigrationMetadata, error) {
+	query, err := d.renderSQL(getMigrationsSQL, tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -110,18 +151,10 @@ func (d *Driver) GetAppliedMigrationsMetadata(ctx context.Context) ([]*migration
 	return migrations, nil
 }
 
-func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMetadata []*migrationsmeta.MigrationMetadata) error {
-	var execer interface {
-		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
-	}
-
-	if tx := d.getTxFromContext(ctx); tx != nil {
-		execer = tx
-	} else {
-		execer = d.db
-	}
+func (d *Driver) setMetadata(ctx context.Context, tableName string, migrationsMetadata []*migrationsmeta.MigrationMetadata) error {
+	execer := d.getExecer(ctx)
 
-	deleteSQL, err := d.renderSQL(deleteAllMigrationsSQL)
+	deleteSQL, err := d.renderSQL(deleteAllMigrationsSQL, tableName)
 	if err != nil {
 		return err
 	}
@@ -134,7 +167,7 @@ func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMet
 		return nil
 	}
 
-	insertSQL, err := d.renderSQL(insertMigrationSQL)
+	insertSQL, err := d.renderSQL(insertMigrationSQL, tableName)
 	if err != nil {
 		return err
 	}
@@ -148,6 +181,48 @@ func (d *Driver) SetAppliedMigrationsMetadata(ctx context.Context, migrationsMet
 	return nil
 }
 
+// GetBackfillCheckpoint returns the checkpoint of the migration's backfill.
+// found is false when no backfill is in progress for the migration.
+func (d *Driver) GetBackfillCheckpoint(ctx context.Context, filename string) (checkpoint string, found bool, err error) {
+	query, err := d.renderSQL(getBackfillSQL, BACKFILLS_TABLE)
+	if err != nil {
+		return "", false, err
+	}
+
+	err = d.db.QueryRowContext(ctx, query, filename).Scan(&checkpoint)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return checkpoint, true, nil
+}
+
+func (d *Driver) SetBackfillCheckpoint(ctx context.Context, filename string, checkpoint string) error {
+	if err := d.DeleteBackfillCheckpoint(ctx, filename); err != nil {
+		return err
+	}
+
+	insertSQL, err := d.renderSQL(insertBackfillSQL, BACKFILLS_TABLE)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.getExecer(ctx).ExecContext(ctx, insertSQL, filename, checkpoint, time.Now())
+	return err
+}
+
+func (d *Driver) DeleteBackfillCheckpoint(ctx context.Context, filename string) error {
+	deleteSQL, err := d.renderSQL(deleteBackfillSQL, BACKFILLS_TABLE)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.getExecer(ctx).ExecContext(ctx, deleteSQL, filename)
+	return err
+}
+
 func (d *Driver) WithTransaction(ctx context.Context, fn func(context.Context) error) (returnErr error) {
 	tx, err := d.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -179,13 +254,18 @@ func (d *Driver) WithTransaction(ctx context.Context, fn func(context.Context) e
 }
 
 func (d *Driver) Handle(ctx context.Context) any {
-	return &Handle{ctx: ctx, driver: d}
+	return newHandle(ctx, d)
 }
 
 func (d *Driver) Init(ctx context.Context, runtime *goja.Runtime) {
+	sqlTagFunctionObj := runtime.ToValue(createSQLTagFunction(d)).ToObject(runtime)
+	sqlTagFunctionObj.Set("ident", SQLIdentFunction)
+	sqlTagFunctionObj.Set("raw", SQLRawFunction)
+	sqlTagFunctionObj.Set("join", createSQLJoinFunction(d))
+
 	d.runtimeData[runtime] = &driverRuntimeData{
 		sqlQueryCtorVal:   runtime.ToValue(SQLQueryCtor),
-		sqlTagFunctionVal: runtime.ToValue(createSQLTagFunction(d)),
+		sqlTagFunctionVal: sqlTagFunctionObj,
 	}
 }
 
@@ -208,6 +288,22 @@ func (d *Driver) MaybeFromJSValue(ctx context.Context, runtime *goja.Runtime, va
 	return nil, false
 }
 
+func (d *Driver) MaybeIntoJSValue(ctx context.Context, runtime *goja.Runtime, value any, intoJs func(any) goja.Value) (goja.Value, bool) {
+	if err, ok := value.(error); ok {
+		return databaseErrorIntoJSValue(runtime, err)
+	}
+	return nil, false
+}
+
+func (d *Driver) getExecer(ctx context.Context) interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx := d.getTxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return d.db
+}
+
 func (d *Driver) getTxFromContext(ctx context.Context) *sql.Tx {
 	if tx, ok := ctx.Value(txContextKey).(*sql.Tx); ok {
 		return tx
@@ -215,7 +311,7 @@ func (d *Driver) getTxFromContext(ctx context.Context) *sql.Tx {
 	return nil
 }
 
-func (d *Driver) renderSQL(sqlTemplate string) (string, error) {
+func (d *Driver) renderSQL(sqlTemplate string, tableName string) (string, error) {
 	tmpl, err := template.New("sql").Parse(sqlTemplate)
 	if err != nil {
 		return "", err
@@ -223,7 +319,7 @@ func (d *Driver) renderSQL(sqlTemplate string) (string, error) {
 
 	var buf bytes.Buffer
 	data := map[string]string{
-		"TableName": MIGRATIONS_TABLE,
+		"TableName": tableName,
 	}
 
 	if err := tmpl.Execute(&buf, data); err != nil {
@@ -45,7 +45,7 @@ func cleanDatabase(t *testing.T, drv *Driver, ctx context.Context) {
 
 	rows, err := drv.db.QueryContext(ctx, `
 		SELECT tablename FROM pg_tables
-		WHERE schemaname = 'public' AND tablename != 'graviton_migrations'
+		WHERE schemaname = 'public' AND tablename NOT IN ('graviton_migrations', 'graviton_backfills')
 	`)
 	if err != nil {
 		t.Fatalf("Failed to list tables: %v", err)
@@ -70,6 +70,10 @@ func cleanDatabase(t *testing.T, drv *Driver, ctx context.Context) {
 	if _, err := drv.db.ExecContext(ctx, "DELETE FROM "+MIGRATIONS_TABLE); err != nil {
 		t.Fatalf("Failed to clean migrations table: %v", err)
 	}
+
+	if _, err := drv.db.ExecContext(ctx, "DELETE FROM "+BACKFILLS_TABLE); err != nil {
+		t.Fatalf("Failed to clean backfills table: %v", err)
+	}
 }
 
 func Test_Driver_Connect(t *testing.T) {
@@ -212,3 +216,38 @@ func Test_Driver_GetAppliedMigrationsMetadata_Empty(t *testing.T) {
 		t.Errorf("GetAppliedMigrationsMetadata() returned %d migrations, want 0", len(retrieved))
 	}
 }
+
+func Test_Driver_BackfillCheckpoint(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	filename := "20240101000000-backfill.migration.ts"
+
+	_, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+	if err != nil {
+		t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+	}
+	if found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint before one was set")
+	}
+
+	for _, checkpoint := range []string{"", "42"} {
+		if err := drv.SetBackfillCheckpoint(ctx, filename, checkpoint); err != nil {
+			t.Fatalf("SetBackfillCheckpoint() error = %v", err)
+		}
+
+		retrieved, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+		if err != nil {
+			t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+		}
+		if !found || retrieved != checkpoint {
+			t.Errorf("GetBackfillCheckpoint() = %q, %v, want %q, true", retrieved, found, checkpoint)
+		}
+	}
+
+	if err := drv.DeleteBackfillCheckpoint(ctx, filename); err != nil {
+		t.Fatalf("DeleteBackfillCheckpoint() error = %v", err)
+	}
+	if _, found, _ := drv.GetBackfillCheckpoint(ctx, filename); found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint after it was deleted")
+	}
+}
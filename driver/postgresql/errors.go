@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"errors"
+
+	"github.com/dop251/goja"
+	"github.com/lib/pq"
+)
+
+// DatabaseError is the error a migration's statement failed with. Migrations
+// receive it as an Error carrying the SQLSTATE and the other fields Postgres
+// reports, so they can catch specific errors.
+type DatabaseError struct {
+	Err       error
+	Statement string
+}
+
+func (e *DatabaseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DatabaseError) Unwrap() error {
+	return e.Err
+}
+
+// databaseErrorIntoJSValue converts a database error into a JavaScript Error
+// named DatabaseError. Other errors are not converted.
+func databaseErrorIntoJSValue(runtime *goja.Runtime, err error) (goja.Value, bool) {
+	var databaseErr *DatabaseError
+	var pqErr *pq.Error
+	isDatabaseErr := errors.As(err, &databaseErr)
+	isPqErr := errors.As(err, &pqErr)
+	if !isDatabaseErr && !isPqErr {
+		return nil, false
+	}
+
+	jsErr := runtime.NewGoError(err)
+	jsErr.Set("name", "DatabaseError")
+	if isDatabaseErr {
+		jsErr.Set("statement", databaseErr.Statement)
+	}
+	if isPqErr {
+		jsErr.Set("code", string(pqErr.Code))
+		jsErr.Set("codeName", pqErr.Code.Name())
+		setIfNotEmpty(jsErr, "detail", pqErr.Detail)
+		setIfNotEmpty(jsErr, "hint", pqErr.Hint)
+		setIfNotEmpty(jsErr, "schema", pqErr.Schema)
+		setIfNotEmpty(jsErr, "table", pqErr.Table)
+		setIfNotEmpty(jsErr, "column", pqErr.Column)
+		setIfNotEmpty(jsErr, "constraint", pqErr.Constraint)
+	}
+	return jsErr, true
+}
+
+func setIfNotEmpty(obj *goja.Object, name string, value string) {
+	if value != "" {
+		obj.Set(name, value)
+	}
+}
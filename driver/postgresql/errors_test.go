@@ -0,0 +1,54 @@
+package postgresql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/lib/pq"
+)
+
+func Test_DatabaseErrorIntoJSValue(t *testing.T) {
+	runtime := goja.New()
+	err := &DatabaseError{
+		Err: &pq.Error{
+			Code:       "23505",
+			Message:    "duplicate key value violates unique constraint \"users_email_key\"",
+			Detail:     "Key (email)=(a@example.com) already exists.",
+			Table:      "users",
+			Constraint: "users_email_key",
+		},
+		Statement: "INSERT INTO users (email) VALUES ($1)",
+	}
+
+	val, ok := databaseErrorIntoJSValue(runtime, err)
+	if !ok {
+		t.Fatal("databaseErrorIntoJSValue() ok = false, want true")
+	}
+	runtime.Set("err", val)
+
+	expected := map[string]any{
+		"err instanceof Error": true,
+		"err.name":             "DatabaseError",
+		"err.code":             "23505",
+		"err.codeName":         "unique_violation",
+		"err.constraint":       "users_email_key",
+		"err.table":            "users",
+		"err.detail":           "Key (email)=(a@example.com) already exists.",
+		"err.hint":             nil,
+		"err.statement":        "INSERT INTO users (email) VALUES ($1)",
+	}
+	for src, want := range expected {
+		got, err := runtime.RunString(src)
+		if err != nil {
+			t.Fatalf("RunString(%q) error = %v", src, err)
+		}
+		if got.Export() != want {
+			t.Errorf("%s = %v, want %v", src, got.Export(), want)
+		}
+	}
+
+	if _, ok := databaseErrorIntoJSValue(runtime, errors.New("other")); ok {
+		t.Error("databaseErrorIntoJSValue() converted an error that is not from the database")
+	}
+}
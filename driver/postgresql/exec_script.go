@@ -0,0 +1,169 @@
+package postgresql
+
+import (
+	"strings"
+)
+
+// ExecScript runs each statement of a SQL script in order, such as a schema
+// dump. A failed statement stops the script, and its error carries the
+// statement that failed.
+func (h *Handle) ExecScript(script string) {
+	for _, statement := range splitStatements(script) {
+		h.Exec(&SQLQuery{Query: statement})
+	}
+}
+
+// splitStatements splits a script on the semicolons that end its statements.
+// Semicolons in strings, quoted identifiers, comments, dollar-quoted bodies
+// and BEGIN ATOMIC bodies do not end a statement. Statements holding nothing
+// but comments are dropped.
+func splitStatements(script string) []string {
+	var statements []string
+	start := 0
+	hasContent := false
+	// depth counts the BEGIN ATOMIC bodies and the CASE expressions in them
+	// that are still open.
+	depth := 0
+	previousWord := ""
+
+	endStatement := func(end int) {
+		if hasContent {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = end + 1
+		hasContent = false
+		depth = 0
+		previousWord = ""
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case strings.HasPrefix(script[i:], "--"):
+			i = skipPast(script, i, "\n")
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipBlockComment(script, i)
+		case c == '\'':
+			escapes := i > 0 && (script[i-1] == 'E' || script[i-1] == 'e') && (i < 2 || !isWordChar(script[i-2]))
+			i = skipQuoted(script, i, '\'', escapes)
+			hasContent = true
+		case c == '"':
+			i = skipQuoted(script, i, '"', false)
+			hasContent = true
+		case c == '$' && (i == 0 || !isWordChar(script[i-1])):
+			tag, ok := dollarQuoteTag(script[i:])
+			if !ok {
+				i += 1
+				continue
+			}
+			i = skipPast(script, i+len(tag), tag)
+			hasContent = true
+		case c == ';':
+			if depth > 0 {
+				i += 1
+				continue
+			}
+			endStatement(i)
+			i += 1
+		case isWordChar(c):
+			wordStart := i
+			for i < len(script) && isWordChar(script[i]) {
+				i += 1
+			}
+			word := strings.ToUpper(script[wordStart:i])
+			switch {
+			case word == "ATOMIC" && previousWord == "BEGIN":
+				depth += 1
+			case word == "CASE" && depth > 0:
+				depth += 1
+			case word == "END" && depth > 0:
+				depth -= 1
+			}
+			previousWord = word
+			hasContent = true
+		default:
+			if c > ' ' {
+				hasContent = true
+			}
+			i += 1
+		}
+	}
+	endStatement(len(script))
+
+	return statements
+}
+
+// dollarQuoteTag returns the $tag$ opening a dollar-quoted string at the start
+// of s. The tag may be empty, as in $$.
+func dollarQuoteTag(s string) (string, bool) {
+	for i := 1; i < len(s); i += 1 {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1], true
+		case c >= '0' && c <= '9':
+			if i == 1 {
+				return "", false
+			}
+		case !isWordChar(c):
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// skipQuoted returns the position after the string or quoted identifier
+// starting at i. A doubled quote is part of the text, as is any character
+// after a backslash when escapes is set.
+func skipQuoted(script string, i int, quote byte, escapes bool) int {
+	for i += 1; i < len(script); i += 1 {
+		switch script[i] {
+		case '\\':
+			if escapes {
+				i += 1
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i += 1
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// skipBlockComment returns the position after the comment starting at i.
+// Block comments nest in PostgreSQL.
+func skipBlockComment(script string, i int) int {
+	depth := 0
+	for i < len(script) {
+		switch {
+		case strings.HasPrefix(script[i:], "/*"):
+			depth += 1
+			i += 2
+		case strings.HasPrefix(script[i:], "*/"):
+			depth -= 1
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i += 1
+		}
+	}
+	return len(script)
+}
+
+// skipPast returns the position after the next end found after i, or the end
+// of the script.
+func skipPast(script string, i int, end string) int {
+	next := strings.Index(script[i:], end)
+	if next < 0 {
+		return len(script)
+	}
+	return i + next + len(end)
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
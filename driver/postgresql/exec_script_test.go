@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			"statements",
+			"CREATE TABLE a (id int);\nINSERT INTO a VALUES (1);\n",
+			[]string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			"strings and identifiers",
+			`INSERT INTO "a;b" VALUES ('x;''y', E'z\';');SELECT 1`,
+			[]string{`INSERT INTO "a;b" VALUES ('x;''y', E'z\';')`, "SELECT 1"},
+		},
+		{
+			"comments",
+			"-- a; b\nSELECT 1; /* c; /* nested; */ d; */ SELECT 2;\n-- trailing;",
+			[]string{"-- a; b\nSELECT 1", "/* c; /* nested; */ d; */ SELECT 2"},
+		},
+		{
+			"dollar quotes",
+			"CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.a := 1; RETURN NEW; END; $body$ LANGUAGE plpgsql;\nDO $$ BEGIN PERFORM 1; END $$;SELECT $1",
+			[]string{
+				"CREATE FUNCTION f() RETURNS trigger AS $body$ BEGIN NEW.a := 1; RETURN NEW; END; $body$ LANGUAGE plpgsql",
+				"DO $$ BEGIN PERFORM 1; END $$",
+				"SELECT $1",
+			},
+		},
+		{
+			"begin atomic",
+			"CREATE FUNCTION g(x int) RETURNS int LANGUAGE sql BEGIN ATOMIC SELECT CASE WHEN x > 0 THEN 1 END; SELECT 2; END; SELECT 3",
+			[]string{"CREATE FUNCTION g(x int) RETURNS int LANGUAGE sql BEGIN ATOMIC SELECT CASE WHEN x > 0 THEN 1 END; SELECT 2; END", "SELECT 3"},
+		},
+		{
+			"transaction statements",
+			"BEGIN; SELECT 1; END;",
+			[]string{"BEGIN", "SELECT 1", "END"},
+		},
+		{
+			"empty",
+			" ;\n; -- nothing\n",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := splitStatements(test.script); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("splitStatements() = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
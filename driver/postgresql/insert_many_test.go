@@ -0,0 +1,44 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Handle_InsertMany(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	drv.db.ExecContext(ctx, "CREATE TABLE items (id integer PRIMARY KEY, name text, created_at timestamptz, data bytea)")
+
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	rows := make([]any, 2500)
+	for i := range rows {
+		rows[i] = map[string]any{"id": int64(i + 1), "name": "tab\there", "created_at": createdAt, "data": []byte{1, 2}}
+	}
+	rows[0].(map[string]any)["name"] = nil
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		inserted := drv.Handle(txCtx).(*Handle).InsertMany("public.items", rows)
+		if inserted != 2500 {
+			t.Errorf("InsertMany() = %d, want 2500", inserted)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	h := drv.Handle(ctx).(*Handle)
+	row := h.QueryOne(&SQLQuery{Query: "SELECT COUNT(*) AS count, COUNT(name) AS named FROM items"})
+	if row["count"] != int64(2500) || row["named"] != int64(2499) {
+		t.Errorf("row = %v, want 2500 rows with 2499 names", row)
+	}
+	row = h.QueryOne(&SQLQuery{Query: "SELECT name, created_at, data FROM items WHERE id = 2"})
+	if row["name"] != "tab\there" || !row["created_at"].(time.Time).Equal(createdAt) || string(row["data"].([]byte)) != "\x01\x02" {
+		t.Errorf("row = %v, want the values inserted", row)
+	}
+
+	if inserted := h.InsertMany("items", []any{map[string]any{"id": int64(2501)}}); inserted != 1 {
+		t.Errorf("InsertMany() outside a transaction = %d, want 1", inserted)
+	}
+}
@@ -0,0 +1,145 @@
+package postgresql
+
+import (
+	"slices"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+// ColumnInfo describes a column of a table, as returned by ListColumns.
+type ColumnInfo struct {
+	Name string
+	// Type is the column's type as PostgreSQL formats it, such as
+	// character varying(255).
+	Type     string
+	Nullable bool
+	// Default is the SQL of the column's default, or nil when it has none.
+	Default    any
+	PrimaryKey bool
+}
+
+// IndexInfo describes an index of a table, as returned by ListIndexes.
+type IndexInfo struct {
+	Name string
+	// Columns are the indexed columns in order. Expressions are left out.
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// tableOidSQL finds a table by its schema, the first parameter, and name, the
+// second. The table is looked for in the current schema when no schema is
+// given.
+const tableOidSQL = `(SELECT c.oid FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE n.nspname = COALESCE($1::text, current_schema()) AND c.relname = $2 AND c.relkind IN ('r', 'p', 'f'))`
+
+// TableExists reports whether a table exists. Names are matched exactly, so
+// a table created without quotes has to be given in lower case.
+func (h *Handle) TableExists(table string) bool {
+	schema, name := introspectionTableName(table)
+	row := h.QueryOne(&SQLQuery{Query: "SELECT " + tableOidSQL + " IS NOT NULL AS found", Params: []any{schema, name}})
+	return row["found"] == true
+}
+
+// ColumnExists reports whether a table has a column.
+func (h *Handle) ColumnExists(table string, column string) bool {
+	return slices.ContainsFunc(h.ListColumns(table), func(info ColumnInfo) bool {
+		return info.Name == column
+	})
+}
+
+// IndexExists reports whether a table has an index, including the indexes of
+// its UNIQUE and PRIMARY KEY constraints.
+func (h *Handle) IndexExists(table string, index string) bool {
+	return slices.ContainsFunc(h.ListIndexes(table), func(info IndexInfo) bool {
+		return info.Name == index
+	})
+}
+
+// ListTables returns the names of the tables in the current schema in order,
+// apart from Graviton's own.
+func (h *Handle) ListTables() []string {
+	tables := []string{}
+	for _, row := range h.Query(&SQLQuery{Query: `SELECT c.relname AS name FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p', 'f') AND NOT c.relispartition
+		ORDER BY c.relname`}) {
+		name := row["name"].(string)
+		if !isGravitonTable(name) {
+			tables = append(tables, name)
+		}
+	}
+	return tables
+}
+
+// ListColumns returns the columns of a table in order, or none when there is
+// no such table.
+func (h *Handle) ListColumns(table string) []ColumnInfo {
+	schema, name := introspectionTableName(table)
+	columns := []ColumnInfo{}
+	for _, row := range h.Query(&SQLQuery{
+		Query: `SELECT a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type, NOT a.attnotnull AS nullable,
+			pg_get_expr(d.adbin, d.adrelid) AS "default",
+			EXISTS (SELECT 1 FROM pg_catalog.pg_index i WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY (i.indkey)) AS primary_key
+			FROM pg_catalog.pg_attribute a
+			LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+			WHERE a.attrelid = ` + tableOidSQL + ` AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum`,
+		Params: []any{schema, name},
+	}) {
+		columns = append(columns, ColumnInfo{
+			Name:       row["name"].(string),
+			Type:       row["type"].(string),
+			Nullable:   row["nullable"] == true,
+			Default:    row["default"],
+			PrimaryKey: row["primary_key"] == true,
+		})
+	}
+	return columns
+}
+
+// ListIndexes returns the indexes of a table by name, or none when there is no
+// such table.
+func (h *Handle) ListIndexes(table string) []IndexInfo {
+	schema, name := introspectionTableName(table)
+	indexes := []IndexInfo{}
+	for _, row := range h.Query(&SQLQuery{
+		Query: `SELECT ic.relname AS name, i.indisunique AS "unique", i.indisprimary AS "primary",
+			ARRAY(SELECT a.attname::text FROM unnest(i.indkey) WITH ORDINALITY AS k(attnum, n)
+				JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+				ORDER BY k.n) AS columns
+			FROM pg_catalog.pg_index i
+			JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid
+			WHERE i.indrelid = ` + tableOidSQL + `
+			ORDER BY ic.relname`,
+		Params: []any{schema, name},
+	}) {
+		index := IndexInfo{
+			Name:    row["name"].(string),
+			Columns: []string{},
+			Unique:  row["unique"] == true,
+			Primary: row["primary"] == true,
+		}
+		columns, _ := row["columns"].([]any)
+		for _, column := range columns {
+			index.Columns = append(index.Columns, column.(string))
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+// introspectionTableName splits a table name into its schema, which is nil
+// for the current schema unless one is given, and its name.
+func introspectionTableName(table string) (any, string) {
+	schema, name := sqlschema.SplitTableName(table)
+	if schema == "" {
+		return nil, name
+	}
+	return schema, name
+}
+
+func isGravitonTable(name string) bool {
+	return name == MIGRATIONS_TABLE || name == SEEDS_TABLE || name == BACKFILLS_TABLE
+}
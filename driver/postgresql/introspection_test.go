@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Handle_Introspection(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`
+		CREATE TABLE users (
+			id SERIAL PRIMARY KEY,
+			email VARCHAR(320) NOT NULL UNIQUE,
+			active BOOLEAN DEFAULT TRUE
+		);
+		CREATE INDEX users_active_email ON users (active, lower(email), email);
+	`)
+
+	if !h.TableExists("users") || !h.TableExists("public.users") || h.TableExists("posts") {
+		t.Errorf("TableExists() is wrong for users or posts")
+	}
+	if !h.ColumnExists("users", "email") || h.ColumnExists("users", "name") {
+		t.Errorf("ColumnExists() is wrong for email or name")
+	}
+	if !h.IndexExists("users", "users_email_key") || h.IndexExists("users", "users_email") {
+		t.Errorf("IndexExists() is wrong for users_email_key or users_email")
+	}
+
+	if tables := h.ListTables(); !reflect.DeepEqual(tables, []string{"users"}) {
+		t.Errorf("ListTables() = %v, want [users]", tables)
+	}
+
+	wantColumns := []ColumnInfo{
+		{Name: "id", Type: "integer", Nullable: false, Default: "nextval('users_id_seq'::regclass)", PrimaryKey: true},
+		{Name: "email", Type: "character varying(320)", Nullable: false},
+		{Name: "active", Type: "boolean", Nullable: true, Default: "true"},
+	}
+	if columns := h.ListColumns("users"); !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("ListColumns() = %+v, want %+v", columns, wantColumns)
+	}
+
+	wantIndexes := []IndexInfo{
+		{Name: "users_active_email", Columns: []string{"active", "email"}},
+		{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+		{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true},
+	}
+	if indexes := h.ListIndexes("users"); !reflect.DeepEqual(indexes, wantIndexes) {
+		t.Errorf("ListIndexes() = %+v, want %+v", indexes, wantIndexes)
+	}
+}
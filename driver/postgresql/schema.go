@@ -0,0 +1,276 @@
+package postgresql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+// Schema makes the common schema changes with DDL written for the database, so
+// migrations written with it run unchanged on PostgreSQL, MySQL and SQLite. It
+// is db.schema in migrations, and like the other Handle methods its
+// statements run in the migration's transaction.
+type Schema struct {
+	handle *Handle
+}
+
+// CreateTable creates a table with the given columns, in order, and its
+// primary key, unique and foreign key constraints.
+func (s *Schema) CreateTable(table string, spec any) {
+	s.exec(createTableStatement(table, sqlschema.DecodeTable("schema.createTable", spec)))
+}
+
+// DropTable drops a table. The ifExists option makes dropping a missing table
+// do nothing.
+func (s *Schema) DropTable(table string, opts ...any) {
+	options := sqlschema.DecodeOptions("schema.dropTable", opts, "ifExists")
+	statement := "DROP TABLE "
+	if sqlschema.Bool("schema.dropTable", options, "ifExists") {
+		statement += "IF EXISTS "
+	}
+	s.exec(statement + quoteIdentifier(strings.Split(table, ".")))
+}
+
+// RenameTable renames a table. The new name is in the table's schema.
+func (s *Schema) RenameTable(table string, newName string) {
+	s.exec("ALTER TABLE " + quoteIdentifier(strings.Split(table, ".")) + " RENAME TO " + quoteIdentifier([]string{newName}))
+}
+
+// AddColumn adds a column to the end of a table.
+func (s *Schema) AddColumn(table string, column any) {
+	s.exec(addColumnStatement(table, sqlschema.DecodeColumn("schema.addColumn", column)))
+}
+
+// DropColumn drops a column from a table.
+func (s *Schema) DropColumn(table string, column string) {
+	s.exec("ALTER TABLE " + quoteIdentifier(strings.Split(table, ".")) + " DROP COLUMN " + quoteIdentifier([]string{column}))
+}
+
+// RenameColumn renames a column of a table.
+func (s *Schema) RenameColumn(table string, column string, newName string) {
+	s.exec("ALTER TABLE " + quoteIdentifier(strings.Split(table, ".")) +
+		" RENAME COLUMN " + quoteIdentifier([]string{column}) + " TO " + quoteIdentifier([]string{newName}))
+}
+
+// CreateIndex creates an index on columns of a table. Unless the name option
+// is given, the index is named after the table and its columns, such as
+// users_email_index, or users_email_unique for a unique index.
+func (s *Schema) CreateIndex(table string, columns []any, opts ...any) {
+	s.exec(createIndexStatement(table, sqlschema.DecodeIndex("schema.createIndex", columns, opts)))
+}
+
+// DropIndex drops an index of a table. The ifExists option makes dropping a
+// missing index do nothing.
+func (s *Schema) DropIndex(table string, index string, opts ...any) {
+	options := sqlschema.DecodeOptions("schema.dropIndex", opts, "ifExists")
+	statement := "DROP INDEX "
+	if sqlschema.Bool("schema.dropIndex", options, "ifExists") {
+		statement += "IF EXISTS "
+	}
+	schema, _ := sqlschema.SplitTableName(table)
+	s.exec(statement + qualifiedName(schema, index))
+}
+
+// AddForeignKey adds a foreign key constraint to a table. Unless the name
+// option is given, it is named after the table and its columns, such as
+// posts_author_id_foreign.
+func (s *Schema) AddForeignKey(table string, foreignKey any) {
+	spec := sqlschema.DecodeForeignKey("schema.addForeignKey", foreignKey)
+	s.exec("ALTER TABLE " + quoteIdentifier(strings.Split(table, ".")) + " ADD " + foreignKeyConstraint(table, spec))
+}
+
+// DropForeignKey drops a foreign key constraint of a table.
+func (s *Schema) DropForeignKey(table string, name string) {
+	s.exec("ALTER TABLE " + quoteIdentifier(strings.Split(table, ".")) + " DROP CONSTRAINT " + quoteIdentifier([]string{name}))
+}
+
+func (s *Schema) exec(statement string) {
+	s.handle.Exec(&SQLQuery{Query: statement})
+}
+
+func createTableStatement(table string, spec *sqlschema.Table) string {
+	var definitions []string
+	foreignKeys := spec.ForeignKeys
+	for _, column := range spec.Columns {
+		definitions = append(definitions, columnDefinition("schema.createTable", column))
+		if column.References != nil {
+			foreignKeys = append(foreignKeys, column.References)
+		}
+	}
+	if len(spec.PrimaryKey) > 0 {
+		definitions = append(definitions, "PRIMARY KEY ("+quoteColumns(spec.PrimaryKey)+")")
+	}
+	for _, columns := range spec.Unique {
+		definitions = append(definitions, "UNIQUE ("+quoteColumns(columns)+")")
+	}
+	for _, foreignKey := range foreignKeys {
+		definitions = append(definitions, foreignKeyConstraint(table, foreignKey))
+	}
+
+	statement := "CREATE TABLE "
+	if spec.IfNotExists {
+		statement += "IF NOT EXISTS "
+	}
+	return statement + quoteIdentifier(strings.Split(table, ".")) + " (\n  " + strings.Join(definitions, ",\n  ") + "\n)"
+}
+
+func addColumnStatement(table string, column *sqlschema.Column) string {
+	statement := "ALTER TABLE " + quoteIdentifier(strings.Split(table, ".")) + " ADD COLUMN " + columnDefinition("schema.addColumn", column)
+	if column.References != nil {
+		name := sqlschema.ObjectName(column.References.Name, table, column.References.Columns, "foreign")
+		statement += " CONSTRAINT " + quoteIdentifier([]string{name}) + " " + foreignKeyReferences(column.References)
+	}
+	return statement
+}
+
+// createIndexStatement leaves the schema out of the index name, as
+// PostgreSQL always creates an index in the schema of its table.
+func createIndexStatement(table string, spec *sqlschema.Index) string {
+	statement := "CREATE INDEX "
+	suffix := "index"
+	if spec.Unique {
+		statement = "CREATE UNIQUE INDEX "
+		suffix = "unique"
+	}
+	if spec.IfNotExists {
+		statement += "IF NOT EXISTS "
+	}
+	name := sqlschema.ObjectName(spec.Name, table, spec.Columns, suffix)
+	return statement + quoteIdentifier([]string{name}) + " ON " + quoteIdentifier(strings.Split(table, ".")) + " (" + quoteColumns(spec.Columns) + ")"
+}
+
+func columnDefinition(context string, column *sqlschema.Column) string {
+	definition := quoteIdentifier([]string{column.Name}) + " " + columnType(column)
+	if column.IsIncrements() {
+		return definition + " PRIMARY KEY"
+	}
+	if !column.Nullable {
+		definition += " NOT NULL"
+	}
+	if column.HasDefault {
+		definition += " DEFAULT " + defaultValueSQL(context, column.Default)
+	}
+	if column.DefaultNow {
+		definition += " DEFAULT CURRENT_TIMESTAMP"
+	}
+	if column.Unique {
+		definition += " UNIQUE"
+	}
+	if column.PrimaryKey {
+		definition += " PRIMARY KEY"
+	}
+	return definition
+}
+
+// columnType returns the PostgreSQL type of a column. Strings are VARCHAR(255)
+// unless another length is given, and timestamps keep their time zone.
+func columnType(column *sqlschema.Column) string {
+	switch column.Type {
+	case "increments":
+		return "SERIAL"
+	case "bigIncrements":
+		return "BIGSERIAL"
+	case "bigInteger":
+		return "BIGINT"
+	case "smallInteger":
+		return "SMALLINT"
+	case "float":
+		return "REAL"
+	case "double":
+		return "DOUBLE PRECISION"
+	case "decimal":
+		if column.Precision > 0 {
+			return fmt.Sprintf("NUMERIC(%d, %d)", column.Precision, column.Scale)
+		}
+		return "NUMERIC"
+	case "boolean":
+		return "BOOLEAN"
+	case "string":
+		return fmt.Sprintf("VARCHAR(%d)", stringLength(column))
+	case "text":
+		return "TEXT"
+	case "uuid":
+		return "UUID"
+	case "date":
+		return "DATE"
+	case "timestamp":
+		return "TIMESTAMPTZ"
+	case "json":
+		return "JSONB"
+	case "binary":
+		return "BYTEA"
+	default:
+		return "INTEGER"
+	}
+}
+
+func stringLength(column *sqlschema.Column) int64 {
+	if column.Length > 0 {
+		return column.Length
+	}
+	return 255
+}
+
+// defaultValueSQL returns the SQL of a column's default. A query is used as
+// the default expression, and must not have parameters.
+func defaultValueSQL(context string, value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if value {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case string:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case *SQLQuery:
+		if len(value.Params) > 0 {
+			panic(fmt.Errorf("%s: default expressions cannot have parameters", context))
+		}
+		return "(" + value.Query + ")"
+	default:
+		panic(fmt.Errorf("%s: default must be a string, number, boolean, null or sql expression", context))
+	}
+}
+
+func foreignKeyConstraint(table string, foreignKey *sqlschema.ForeignKey) string {
+	name := sqlschema.ObjectName(foreignKey.Name, table, foreignKey.Columns, "foreign")
+	return "CONSTRAINT " + quoteIdentifier([]string{name}) +
+		" FOREIGN KEY (" + quoteColumns(foreignKey.Columns) + ") " + foreignKeyReferences(foreignKey)
+}
+
+func foreignKeyReferences(foreignKey *sqlschema.ForeignKey) string {
+	clause := "REFERENCES " + quoteIdentifier(strings.Split(foreignKey.Table, ".")) + " (" + quoteColumns(foreignKey.ReferencedColumns) + ")"
+	if foreignKey.OnDelete != "" {
+		clause += " ON DELETE " + foreignKey.OnDelete
+	}
+	if foreignKey.OnUpdate != "" {
+		clause += " ON UPDATE " + foreignKey.OnUpdate
+	}
+	return clause
+}
+
+// qualifiedName quotes a name, prefixed with a schema when one is given.
+func qualifiedName(schema string, name string) string {
+	if schema == "" {
+		return quoteIdentifier([]string{name})
+	}
+	return quoteIdentifier([]string{schema, name})
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier([]string{column})
+	}
+	return strings.Join(quoted, ", ")
+}
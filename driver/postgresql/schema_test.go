@@ -0,0 +1,102 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+func Test_Schema_Statements(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{
+			name: "create table",
+			got: func() string {
+				return createTableStatement("app.posts", sqlschema.DecodeTable("schema.createTable", map[string]any{
+					"columns": []any{
+						map[string]any{"name": "id", "type": "bigIncrements"},
+						map[string]any{"name": "author_id", "type": "integer", "nullable": false, "references": map[string]any{"table": "app.users", "onDelete": "cascade"}},
+						map[string]any{"name": "title", "type": "string", "length": int64(100), "default": "it's new"},
+						map[string]any{"name": "published", "type": "boolean", "default": false},
+						map[string]any{"name": "price", "type": "decimal", "precision": int64(10), "scale": int64(2)},
+						map[string]any{"name": "metadata", "type": "json"},
+						map[string]any{"name": "created_at", "type": "timestamp", "defaultNow": true},
+					},
+					"unique": []any{[]any{"author_id", "title"}},
+				}))
+			},
+			want: `CREATE TABLE "app"."posts" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "author_id" INTEGER NOT NULL,
+  "title" VARCHAR(100) DEFAULT 'it''s new',
+  "published" BOOLEAN DEFAULT FALSE,
+  "price" NUMERIC(10, 2),
+  "metadata" JSONB,
+  "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE ("author_id", "title"),
+  CONSTRAINT "posts_author_id_foreign" FOREIGN KEY ("author_id") REFERENCES "app"."users" ("id") ON DELETE CASCADE
+)`,
+		},
+		{
+			name: "add column",
+			got: func() string {
+				return addColumnStatement("posts", sqlschema.DecodeColumn("schema.addColumn", map[string]any{
+					"name": "editor_id", "type": "integer",
+					"references": map[string]any{"table": "users"},
+				}))
+			},
+			want: `ALTER TABLE "posts" ADD COLUMN "editor_id" INTEGER CONSTRAINT "posts_editor_id_foreign" REFERENCES "users" ("id")`,
+		},
+		{
+			name: "create index",
+			got: func() string {
+				return createIndexStatement("app.users", sqlschema.DecodeIndex("schema.createIndex", []any{"last_name", "first_name"}, []any{map[string]any{"ifNotExists": true}}))
+			},
+			want: `CREATE INDEX IF NOT EXISTS "users_last_name_first_name_index" ON "app"."users" ("last_name", "first_name")`,
+		},
+		{
+			name: "foreign key",
+			got: func() string {
+				return foreignKeyConstraint("memberships", sqlschema.DecodeForeignKey("schema.addForeignKey", map[string]any{
+					"columns":    []any{"group_id", "tenant_id"},
+					"references": map[string]any{"table": "groups", "columns": []any{"id", "tenant_id"}},
+					"name":       "memberships_group",
+					"onUpdate":   "restrict",
+				}))
+			},
+			want: `CONSTRAINT "memberships_group" FOREIGN KEY ("group_id", "tenant_id") REFERENCES "groups" ("id", "tenant_id") ON UPDATE RESTRICT`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); got != tt.want {
+				t.Errorf("statement =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Schema_CreateTable(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	h.Schema.CreateTable("users", map[string]any{
+		"columns": []any{
+			map[string]any{"name": "id", "type": "increments"},
+			map[string]any{"name": "email", "type": "string", "nullable": false, "unique": true},
+			map[string]any{"name": "created_at", "type": "timestamp", "defaultNow": true},
+		},
+	})
+	h.Schema.AddColumn("users", map[string]any{"name": "active", "type": "boolean", "default": true})
+	h.Schema.CreateIndex("users", []any{"active"})
+	h.Exec(&SQLQuery{Query: "INSERT INTO users (email) VALUES ('ada@example.com')"})
+
+	user := h.QueryOne(&SQLQuery{Query: "SELECT id, active FROM users"})
+	if user["id"] != int64(1) || user["active"] != true {
+		t.Errorf("user = %v, want id 1 and active", user)
+	}
+}
@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/telemetryos/graviton/schematypes"
+)
+
+// SchemaTypes describes the tables of the current schema as the rows
+// migrations read from them, for `graviton types`.
+func (d *Driver) SchemaTypes(ctx context.Context) (schema *schematypes.Schema, returnErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				returnErr = e
+			} else {
+				returnErr = fmt.Errorf("panic in schema types: %v", r)
+			}
+		}
+	}()
+
+	h := newHandle(ctx, d)
+	schema = &schematypes.Schema{Namespace: "Tables"}
+	for _, table := range h.ListTables() {
+		t := &schematypes.Type{Name: table}
+		for _, column := range h.ListColumns(table) {
+			fieldType := columnTypeScriptType(column.Type)
+			if column.Nullable {
+				fieldType = schematypes.Nullable(fieldType)
+			}
+			t.Fields = append(t.Fields, &schematypes.Field{Name: column.Name, Type: fieldType})
+		}
+		schema.Types = append(schema.Types, t)
+	}
+	return schema, nil
+}
+
+// columnTypeScriptType returns the TypeScript type of the values decodeValue
+// reads from a column, given its type as format_type writes it. A bigint
+// outside the safe range is read as a string.
+func columnTypeScriptType(columnType string) string {
+	if elementType, ok := strings.CutSuffix(columnType, "[]"); ok {
+		elementTypeScript := columnTypeScriptType(elementType)
+		if strings.Contains(elementTypeScript, " | ") {
+			return "(" + elementTypeScript + ")[]"
+		}
+		return elementTypeScript + "[]"
+	}
+	switch {
+	case columnType == "bigint":
+		return "number | string"
+	case columnType == "smallint", columnType == "integer",
+		columnType == "real", columnType == "double precision":
+		return "number"
+	case columnType == "boolean":
+		return "boolean"
+	case columnType == "json", columnType == "jsonb":
+		return "any"
+	case columnType == "date", strings.HasPrefix(columnType, "timestamp"):
+		return "Date"
+	case columnType == "bytea":
+		return "Uint8Array"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"testing"
+)
+
+func Test_ColumnTypeScriptType(t *testing.T) {
+	tests := map[string]string{
+		"integer":                        "number",
+		"bigint":                         "number | string",
+		"bigint[]":                       "(number | string)[]",
+		"double precision":               "number",
+		"numeric(10,2)":                  "string",
+		"boolean":                        "boolean",
+		"jsonb":                          "any",
+		"timestamp with time zone":       "Date",
+		"timestamp(3) without time zone": "Date",
+		"date":                           "Date",
+		"time without time zone":         "string",
+		"bytea":                          "Uint8Array",
+		"uuid":                           "string",
+		"character varying(255)":         "string",
+		"integer[]":                      "number[]",
+		"text[]":                         "string[]",
+	}
+	for columnType, want := range tests {
+		if got := columnTypeScriptType(columnType); got != want {
+			t.Errorf("columnTypeScriptType(%q) = %q, want %q", columnType, got, want)
+		}
+	}
+}
+
+func Test_Driver_SchemaTypes(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`CREATE TABLE users (id SERIAL PRIMARY KEY, email TEXT, tags TEXT[] NOT NULL)`)
+
+	schema, err := drv.SchemaTypes(ctx)
+	if err != nil {
+		t.Fatalf("SchemaTypes() error = %v", err)
+	}
+	if len(schema.Types) != 1 || len(schema.Types[0].Fields) != 3 {
+		t.Fatalf("SchemaTypes() = %+v, want the users table", schema)
+	}
+	for i, want := range []string{"number", "string | null", "string[]"} {
+		if field := schema.Types[0].Fields[i]; field.Type != want {
+			t.Errorf("field %s type = %q, want %q", field.Name, field.Type, want)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS {{.TableName}} (
+    filename TEXT PRIMARY KEY,
+    checkpoint TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
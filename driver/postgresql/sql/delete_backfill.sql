@@ -0,0 +1,2 @@
+DELETE FROM {{.TableName}}
+WHERE filename = $1;
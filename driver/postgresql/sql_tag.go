@@ -1,13 +1,18 @@
 package postgresql
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/dop251/goja"
-	"github.com/xwb1989/sqlparser"
 )
 
+// SQL_FRAGMENT_KEY is the hidden property holding the fragment an SQLQuery
+// was rendered from.
+const SQL_FRAGMENT_KEY = "__fragment__"
+
 type SQLQuery struct {
 	Query     string
 	Params    []any
@@ -87,42 +92,185 @@ func createSQLTagFunction(d *Driver) func(goja.FunctionCall, *goja.Runtime) goja
 			panic(vm.ToValue("sql tag: first argument must be template strings array"))
 		}
 
-		var values []any
+		fragment := &sqlFragment{parts: parts}
 		for i := 1; i < len(call.Arguments); i++ {
-			values = append(values, call.Arguments[i].Export())
+			fragment.values = append(fragment.values, d.fragmentValue(vm, call.Arguments[i]))
 		}
 
-		query := buildPostgreSQLQuery(parts, len(values))
+		return d.newSQLQuery(vm, "sql tag", fragment)
+	}
+}
 
-		validateSQL(query)
+// createSQLJoinFunction implements sql.join(items, separator), which joins
+// values or fragments with a separator, ", " by default.
+func createSQLJoinFunction(d *Driver) func(goja.FunctionCall, *goja.Runtime) goja.Value {
+	return func(call goja.FunctionCall, vm *goja.Runtime) goja.Value {
+		items, ok := d.fragmentValue(vm, call.Argument(0)).([]any)
+		if !ok {
+			panic(vm.ToValue("sql.join: first argument must be an array"))
+		}
+
+		separator := ", "
+		if separatorVal := call.Argument(1); !goja.IsUndefined(separatorVal) {
+			separator = separatorVal.String()
+		}
 
-		sqlQueryInstance, err := vm.New(d.runtimeData[vm].sqlQueryCtorVal, vm.ToValue(query), vm.ToValue(values))
-		if err != nil {
-			panic(vm.ToValue(fmt.Sprintf("Failed to create SQLQuery instance: %v", err)))
+		fragment := &sqlFragment{parts: []string{""}, values: items}
+		for i := range items {
+			if i == len(items)-1 {
+				fragment.parts = append(fragment.parts, "")
+			} else {
+				fragment.parts = append(fragment.parts, separator)
+			}
 		}
 
-		return sqlQueryInstance
+		return d.newSQLQuery(vm, "sql.join", fragment)
 	}
 }
 
-func buildPostgreSQLQuery(parts []string, paramCount int) string {
-	var query strings.Builder
-	paramIndex := 1
+// SQLIdentFunction implements sql.ident(...names). Several names are joined
+// with dots, so sql.ident("public", "users") is "public"."users".
+func SQLIdentFunction(call goja.FunctionCall, vm *goja.Runtime) goja.Value {
+	if len(call.Arguments) == 0 {
+		panic(vm.ToValue("sql.ident requires at least one name"))
+	}
 
-	for i, part := range parts {
-		query.WriteString(part)
-		if i < paramCount {
-			query.WriteString(fmt.Sprintf("$%d", paramIndex))
-			paramIndex++
+	identifier := &sqlIdentifier{}
+	for _, arg := range call.Arguments {
+		name, ok := arg.Export().(string)
+		if !ok || name == "" || strings.ContainsRune(name, 0) {
+			panic(vm.ToValue(fmt.Sprintf("sql.ident: invalid identifier %s", arg.String())))
 		}
+		identifier.names = append(identifier.names, name)
 	}
+	return vm.ToValue(identifier)
+}
+
+// SQLRawFunction implements sql.raw(text), which inserts text into a query as
+// is. It must never be given untrusted input.
+func SQLRawFunction(call goja.FunctionCall, vm *goja.Runtime) goja.Value {
+	text, ok := call.Argument(0).Export().(string)
+	if !ok {
+		panic(vm.ToValue("sql.raw: argument must be a string"))
+	}
+	return vm.ToValue(&sqlRaw{text: text})
+}
 
-	return query.String()
+// sqlFragment is a query as written with the sql tag, before placeholders are
+// numbered, so it can be nested in another query. Each value is a parameter,
+// an identifier, raw SQL, another fragment or an array of these.
+type sqlFragment struct {
+	parts  []string
+	values []any
 }
 
-func validateSQL(query string) {
-	_, err := sqlparser.Parse(query)
+type sqlIdentifier struct {
+	names []string
+}
+
+type sqlRaw struct {
+	text string
+}
+
+func (d *Driver) newSQLQuery(vm *goja.Runtime, caller string, fragment *sqlFragment) goja.Value {
+	query, params, err := renderSQLFragment(fragment)
+	if err != nil {
+		panic(vm.ToValue(fmt.Sprintf("%s: %v", caller, err)))
+	}
+
+	sqlQueryInstance, err := vm.New(d.runtimeData[vm].sqlQueryCtorVal, vm.ToValue(query), vm.ToValue(params))
 	if err != nil {
-		fmt.Printf("SQL validation warning (continuing anyway): %v\n", err)
+		panic(vm.ToValue(fmt.Sprintf("Failed to create SQLQuery instance: %v", err)))
 	}
+	sqlQueryInstance.DefineDataProperty(SQL_FRAGMENT_KEY, vm.ToValue(fragment), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+
+	return sqlQueryInstance
+}
+
+// fragmentValue converts an interpolated value, keeping nested queries and
+// the elements of arrays as fragments.
+func (d *Driver) fragmentValue(vm *goja.Runtime, val goja.Value) any {
+	if val == nil || goja.IsUndefined(val) || goja.IsNull(val) {
+		return nil
+	}
+
+	if IsSQLQuery(vm, val, d.runtimeData[vm].sqlQueryCtorVal) {
+		fragmentVal := val.ToObject(vm).Get(SQL_FRAGMENT_KEY)
+		if fragmentVal == nil {
+			panic(vm.ToValue("sql tag: only queries created with the sql tag can be nested"))
+		}
+		return fragmentVal.Export()
+	}
+
+	if obj, ok := val.(*goja.Object); ok && obj.ClassName() == "Array" {
+		length := int(obj.Get("length").ToInteger())
+		values := make([]any, length)
+		for i := 0; i < length; i++ {
+			values[i] = d.fragmentValue(vm, obj.Get(strconv.Itoa(i)))
+		}
+		return values
+	}
+
+	return val.Export()
+}
+
+type sqlBuilder struct {
+	query  strings.Builder
+	params []any
+}
+
+func renderSQLFragment(fragment *sqlFragment) (string, []any, error) {
+	b := &sqlBuilder{}
+	if err := b.writeFragment(fragment); err != nil {
+		return "", nil, err
+	}
+	return b.query.String(), b.params, nil
+}
+
+func (b *sqlBuilder) writeFragment(fragment *sqlFragment) error {
+	for i, part := range fragment.parts {
+		b.query.WriteString(part)
+		if i < len(fragment.values) {
+			if err := b.writeValue(fragment.values[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeValue writes a value into the query. Arrays expand to a comma separated
+// list, and arrays within arrays to parenthesized lists, so an array of rows
+// can be used as a VALUES list.
+func (b *sqlBuilder) writeValue(value any) error {
+	switch v := value.(type) {
+	case *sqlFragment:
+		return b.writeFragment(v)
+	case *sqlIdentifier:
+		b.query.WriteString(quoteIdentifier(v.names))
+	case *sqlRaw:
+		b.query.WriteString(v.text)
+	case []any:
+		if len(v) == 0 {
+			return errors.New("cannot expand an empty array")
+		}
+		for i, elem := range v {
+			if i > 0 {
+				b.query.WriteString(", ")
+			}
+			if _, ok := elem.([]any); ok {
+				b.query.WriteString("(")
+			}
+			if err := b.writeValue(elem); err != nil {
+				return err
+			}
+			if _, ok := elem.([]any); ok {
+				b.query.WriteString(")")
+			}
+		}
+	default:
+		b.params = append(b.params, v)
+		b.query.WriteString(fmt.Sprintf("$%d", len(b.params)))
+	}
+	return nil
 }
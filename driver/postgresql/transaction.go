@@ -0,0 +1,52 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+var savepointCount atomic.Int64
+
+// Transaction calls fn with a handle whose work can be rolled back on its own.
+// Inside a migration's transaction fn runs in a savepoint. When fn throws, only
+// its changes are rolled back and the error is rethrown, so the migration can
+// catch it and continue. Outside a transaction fn runs in a transaction of its
+// own.
+func (h *Handle) Transaction(fn func(db *Handle) any) (result any) {
+	tx := h.driver.getTxFromContext(h.ctx)
+	if tx == nil {
+		err := h.driver.WithTransaction(h.ctx, func(txCtx context.Context) error {
+			result = fn(newHandle(txCtx, h.driver))
+			return nil
+		})
+		if err != nil {
+			panic(err)
+		}
+		return result
+	}
+
+	savepoint := fmt.Sprintf("graviton_savepoint_%d", savepointCount.Add(1))
+	if _, err := tx.ExecContext(h.ctx, "SAVEPOINT "+savepoint); err != nil {
+		panic(err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if _, err := tx.ExecContext(h.ctx, "ROLLBACK TO SAVEPOINT "+savepoint); err != nil {
+				panic(fmt.Errorf("failed to roll back to savepoint after %v: %w", r, err))
+			}
+			if _, err := tx.ExecContext(h.ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+				panic(err)
+			}
+			panic(r)
+		}
+	}()
+
+	result = fn(h)
+
+	if _, err := tx.ExecContext(h.ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		panic(err)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Handle_Transaction_RollsBackToSavepoint(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	drv.db.ExecContext(ctx, "CREATE TABLE test (value TEXT UNIQUE)")
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		h := drv.Handle(txCtx).(*Handle)
+		h.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES ($1)", Params: []any{"outer"}})
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Transaction() did not rethrow the error")
+				}
+			}()
+			h.Transaction(func(db *Handle) any {
+				db.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES ($1)", Params: []any{"inner"}})
+				db.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES ($1)", Params: []any{"outer"}})
+				return nil
+			})
+		}()
+
+		result := h.Transaction(func(db *Handle) any {
+			db.Exec(&SQLQuery{Query: "INSERT INTO test (value) VALUES ($1)", Params: []any{"after"}})
+			return "done"
+		})
+		if result != "done" {
+			t.Errorf("Transaction() = %v, want done", result)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	rows := drv.Handle(ctx).(*Handle).Query(&SQLQuery{Query: "SELECT value FROM test ORDER BY value"})
+	var values []string
+	for _, row := range rows {
+		values = append(values, row["value"].(string))
+	}
+	if len(values) != 2 || values[0] != "after" || values[1] != "outer" {
+		t.Errorf("values = %v, want [after outer]", values)
+	}
+}
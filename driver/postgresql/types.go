@@ -0,0 +1,142 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// MAX_SAFE_INTEGER is the largest integer a JavaScript number holds exactly.
+// The migration runtime has no BigInt, so larger integers are returned as
+// strings rather than silently rounded.
+const MAX_SAFE_INTEGER = 1<<53 - 1
+
+// decodeValue converts a value scanned from a column of the given type, as
+// reported by ColumnType.DatabaseTypeName, into the value migrations see.
+//
+//   - smallint, integer and bigint become numbers, or strings outside the safe
+//     integer range
+//   - real and double precision become numbers
+//   - numeric and money become strings so no precision is lost
+//   - json and jsonb are parsed
+//   - date, timestamp and timestamptz become Dates, time and timetz strings
+//   - bytea becomes a Uint8Array
+//   - arrays become arrays of their decoded elements
+//   - everything else, including uuid, becomes a string
+func decodeValue(typeName string, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if elemTypeName, ok := strings.CutPrefix(typeName, "_"); ok {
+		return decodeArray(elemTypeName, value)
+	}
+
+	switch typeName {
+	case "INT2", "INT4", "INT8":
+		switch v := value.(type) {
+		case int64:
+			return safeInteger(v), nil
+		case []byte:
+			n, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return safeInteger(n), nil
+		}
+	case "FLOAT4", "FLOAT8":
+		if v, ok := value.([]byte); ok {
+			return strconv.ParseFloat(string(v), 64)
+		}
+	case "BOOL":
+		if v, ok := value.([]byte); ok {
+			return string(v) == "t", nil
+		}
+	case "JSON", "JSONB":
+		var parsed any
+		if err := json.Unmarshal(textBytes(value), &parsed); err != nil {
+			return nil, err
+		}
+		return parsed, nil
+	case "DATE", "TIMESTAMP", "TIMESTAMPTZ":
+		if v, ok := value.([]byte); ok {
+			return pq.ParseTimestamp(nil, string(v))
+		}
+	case "TIME", "TIMETZ":
+		if v, ok := value.(time.Time); ok {
+			if typeName == "TIMETZ" {
+				return v.Format("15:04:05.999999Z07:00"), nil
+			}
+			return v.Format("15:04:05.999999"), nil
+		}
+	case "BYTEA":
+		return value, nil
+	}
+
+	if v, ok := value.([]byte); ok {
+		return string(v), nil
+	}
+	return value, nil
+}
+
+// decodeArray decodes a one-dimensional array. Multidimensional arrays are
+// returned in Postgres' text form.
+func decodeArray(elemTypeName string, value any) (any, error) {
+	if elemTypeName == "BYTEA" {
+		var elems pq.ByteaArray
+		if err := elems.Scan(value); err != nil {
+			return nil, err
+		}
+		results := make([]any, len(elems))
+		for i, elem := range elems {
+			if elem != nil {
+				results[i] = elem
+			}
+		}
+		return results, nil
+	}
+
+	var elems []sql.NullString
+	if err := (pq.GenericArray{A: &elems}).Scan(value); err != nil {
+		if strings.Contains(err.Error(), "multidimensional") {
+			return string(textBytes(value)), nil
+		}
+		return nil, err
+	}
+
+	results := make([]any, len(elems))
+	for i, elem := range elems {
+		if !elem.Valid {
+			continue
+		}
+		decoded, err := decodeValue(elemTypeName, []byte(elem.String))
+		if err != nil {
+			return nil, fmt.Errorf("array element %d: %w", i, err)
+		}
+		results[i] = decoded
+	}
+	return results, nil
+}
+
+func safeInteger(n int64) any {
+	if n > MAX_SAFE_INTEGER || n < -MAX_SAFE_INTEGER {
+		return strconv.FormatInt(n, 10)
+	}
+	return n
+}
+
+func textBytes(value any) []byte {
+	switch v := value.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
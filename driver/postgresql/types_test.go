@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_DecodeValue(t *testing.T) {
+	tests := []struct {
+		typeName string
+		value    any
+		expected any
+	}{
+		{"INT4", int64(42), int64(42)},
+		{"INT8", int64(9007199254740993), "9007199254740993"},
+		{"INT8", int64(-9007199254740993), "-9007199254740993"},
+		{"FLOAT8", 1.5, 1.5},
+		{"NUMERIC", []byte("12345678901234567890.01"), "12345678901234567890.01"},
+		{"MONEY", []byte("$1.50"), "$1.50"},
+		{"UUID", []byte("9b2f1c1e-3c1b-4b7a-9d6e-2f7c1e3b4a5d"), "9b2f1c1e-3c1b-4b7a-9d6e-2f7c1e3b4a5d"},
+		{"JSONB", []byte(`{"a": [1, null]}`), map[string]any{"a": []any{1.0, nil}}},
+		{"JSON", []byte(`"text"`), "text"},
+		{"BYTEA", []byte{0, 1, 2}, []byte{0, 1, 2}},
+		{"TIME", time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC), "10:30:00"},
+		{"TEXT", "hello", "hello"},
+		{"_INT4", []byte("{1,NULL,3}"), []any{int64(1), nil, int64(3)}},
+		{"_TEXT", []byte(`{a,"b c",NULL}`), []any{"a", "b c", nil}},
+		{"_BOOL", []byte("{t,f}"), []any{true, false}},
+		{"_NUMERIC", []byte("{1.10,2}"), []any{"1.10", "2"}},
+		{"_JSONB", []byte(`{"{\"a\": 1}"}`), []any{map[string]any{"a": 1.0}}},
+		{"_BYTEA", []byte(`{"\\x0102"}`), []any{[]byte{1, 2}}},
+		{"_INT4", []byte("{{1,2},{3,4}}"), "{{1,2},{3,4}}"},
+		{"TEXT", nil, nil},
+	}
+
+	for _, test := range tests {
+		got, err := decodeValue(test.typeName, test.value)
+		if err != nil {
+			t.Errorf("decodeValue(%s, %q) error = %v", test.typeName, test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("decodeValue(%s, %q) = %#v, want %#v", test.typeName, test.value, got, test.expected)
+		}
+	}
+}
+
+func Test_DecodeValue_TimestampArray(t *testing.T) {
+	got, err := decodeValue("_TIMESTAMPTZ", []byte(`{"2024-03-01 10:30:00+00"}`))
+	if err != nil {
+		t.Fatalf("decodeValue() error = %v", err)
+	}
+
+	times, ok := got.([]any)
+	if !ok || len(times) != 1 {
+		t.Fatalf("decodeValue() = %#v, want one timestamp", got)
+	}
+	if ts, ok := times[0].(time.Time); !ok || !ts.Equal(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("decodeValue() = %#v, want 2024-03-01 10:30:00 UTC", times[0])
+	}
+}
+
+func Test_DecodeValue_InvalidJSON(t *testing.T) {
+	if _, err := decodeValue("JSONB", []byte("{")); err == nil {
+		t.Error("decodeValue() error = nil, want an error")
+	}
+}
@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SYNTAX_ERROR_CODE is the SQLSTATE Postgres reports for statements it cannot
+// parse.
+const SYNTAX_ERROR_CODE = "42601"
+
+// ValidateSQL checks the syntax of a statement by having the server parse it
+// as a prepared statement. Only syntax errors are reported. Errors such as a
+// missing table are ignored, since the table may be created by an earlier
+// statement in the same migration. Several statements cannot be prepared
+// together, so each is checked on its own.
+func (d *Driver) ValidateSQL(ctx context.Context, statement string) error {
+	if d.db == nil {
+		return nil
+	}
+	for _, statement := range splitStatements(statement) {
+		if err := d.validateStatement(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) validateStatement(ctx context.Context, statement string) error {
+	stmt, err := d.db.PrepareContext(ctx, statement)
+	if err == nil {
+		return stmt.Close()
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != SYNTAX_ERROR_CODE {
+		return nil
+	}
+	return errors.New(pqErr.Message)
+}
+
+// StrictSQLValidation reports whether invalid SQL fails a migration when it is
+// compiled, rather than printing a warning.
+func (d *Driver) StrictSQLValidation() bool {
+	return d.config.StrictSQLValidation
+}
+
+func (d *Driver) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (d *Driver) QuoteIdentifier(names ...string) string {
+	return quoteIdentifier(names)
+}
+
+func quoteIdentifier(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, ".")
+}
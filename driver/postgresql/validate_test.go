@@ -0,0 +1,35 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Driver_ValidateSQL(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	valid := []string{
+		"CREATE TABLE users (id SERIAL PRIMARY KEY, email TEXT NOT NULL)",
+		"INSERT INTO users (email) VALUES ($1) ON CONFLICT (email) DO NOTHING RETURNING id",
+		"SELECT * FROM missing WHERE id = $1",
+		"CREATE FUNCTION one() RETURNS integer AS $$ SELECT 1 $$ LANGUAGE sql",
+		"CREATE TABLE a (id int); CREATE TABLE b (id int)",
+	}
+	for _, statement := range valid {
+		if err := drv.ValidateSQL(ctx, statement); err != nil {
+			t.Errorf("ValidateSQL(%q) error = %v, want nil", statement, err)
+		}
+	}
+
+	invalid := []string{
+		"CREATE TABL users (id int)",
+		"SELECT * FROM users WHERE",
+		"CREATE TABLE a (id int); CREATE TABL b (id int)",
+	}
+	for _, statement := range invalid {
+		err := drv.ValidateSQL(ctx, statement)
+		if err == nil || !strings.Contains(err.Error(), "syntax error") {
+			t.Errorf("ValidateSQL(%q) error = %v, want a syntax error", statement, err)
+		}
+	}
+}
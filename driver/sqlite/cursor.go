@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"database/sql"
+)
+
+const DEFAULT_CURSOR_BATCH_SIZE = 1000
+
+// Cursor reads the rows of a query a batch at a time, so results too large to
+// hold in memory can be processed row by row.
+type Cursor struct {
+	statement string
+	rows      *sql.Rows
+	batchSize int
+	batch     []map[string]any
+	done      bool
+}
+
+// Cursor runs the query and returns a cursor over its rows. The cursor holds
+// a connection until it is read to the end or closed.
+func (h *Handle) Cursor(sqlQuery *SQLQuery, batchSize ...int) *Cursor {
+	return &Cursor{
+		statement: sqlQuery.Query,
+		rows:      h.queryRows(sqlQuery),
+		batchSize: cursorBatchSize(batchSize),
+	}
+}
+
+// ForEach calls fn with each row of the query. Returning false from fn stops
+// the iteration.
+func (h *Handle) ForEach(sqlQuery *SQLQuery, fn func(row map[string]any) any, batchSize ...int) {
+	h.Cursor(sqlQuery, batchSize...).ForEach(fn)
+}
+
+func (c *Cursor) HasNext() bool {
+	if len(c.batch) == 0 && !c.done {
+		c.batch = c.readBatch()
+	}
+	return len(c.batch) != 0
+}
+
+// Next returns the next row, or nil when there are no rows left.
+func (c *Cursor) Next() map[string]any {
+	if !c.HasNext() {
+		return nil
+	}
+	row := c.batch[0]
+	c.batch = c.batch[1:]
+	return row
+}
+
+// ForEach calls fn with each remaining row and then closes the cursor.
+// Returning false from fn stops the iteration.
+func (c *Cursor) ForEach(fn func(row map[string]any) any) {
+	defer c.Close()
+	for c.HasNext() {
+		if proceed, ok := fn(c.Next()).(bool); ok && !proceed {
+			return
+		}
+	}
+}
+
+func (c *Cursor) ToArray() []map[string]any {
+	results := []map[string]any{}
+	for c.HasNext() {
+		results = append(results, c.Next())
+	}
+	return results
+}
+
+func (c *Cursor) Close() {
+	c.batch = nil
+	if c.done {
+		return
+	}
+	c.done = true
+	c.rows.Close()
+}
+
+func (c *Cursor) readBatch() []map[string]any {
+	batch, err := readRows(c.rows, c.batchSize)
+	if err != nil {
+		c.Close()
+		panic(&DatabaseError{Err: err, Statement: c.statement})
+	}
+	if len(batch) < c.batchSize {
+		c.Close()
+	}
+	return batch
+}
+
+func cursorBatchSize(batchSize []int) int {
+	if len(batchSize) != 0 && batchSize[0] > 0 {
+		return batchSize[0]
+	}
+	return DEFAULT_CURSOR_BATCH_SIZE
+}
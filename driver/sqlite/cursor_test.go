@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func seedCursorTable(t *testing.T, drv *Driver, ctx context.Context, count int) {
+	t.Helper()
+
+	if _, err := drv.db.ExecContext(ctx, "CREATE TABLE items (id INTEGER, done INTEGER)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for i := 1; i <= count; i += 1 {
+		if _, err := drv.db.ExecContext(ctx, "INSERT INTO items (id, done) VALUES (?, 0)", i); err != nil {
+			t.Fatalf("Failed to insert row: %v", err)
+		}
+	}
+}
+
+func Test_Handle_Cursor_ReadsInBatches(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	seedCursorTable(t, drv, ctx, 7)
+
+	handle := drv.Handle(ctx).(*Handle)
+	cursor := handle.Cursor(&SQLQuery{Query: "SELECT id FROM items ORDER BY id"}, 3)
+
+	var ids []string
+	for cursor.HasNext() {
+		if len(cursor.batch) > 3 {
+			t.Fatalf("cursor buffered %d rows, want at most 3", len(cursor.batch))
+		}
+		ids = append(ids, fmt.Sprint(cursor.Next()["id"]))
+	}
+
+	if fmt.Sprint(ids) != "[1 2 3 4 5 6 7]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5 6 7]", ids)
+	}
+	if cursor.Next() != nil {
+		t.Error("Next() after the last row should return nil")
+	}
+}
+
+func Test_Handle_ForEach_Stop(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	seedCursorTable(t, drv, ctx, 5)
+
+	handle := drv.Handle(ctx).(*Handle)
+
+	count := 0
+	handle.ForEach(&SQLQuery{Query: "SELECT id FROM items ORDER BY id"}, func(row map[string]any) any {
+		count += 1
+		return count < 2
+	}, 2)
+
+	if count != 2 {
+		t.Errorf("callback called %d times, want 2", count)
+	}
+}
+
+func Test_Handle_ForEach_UpdatesInTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	seedCursorTable(t, drv, ctx, 5)
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		handle := drv.Handle(txCtx).(*Handle)
+		handle.ForEach(&SQLQuery{Query: "SELECT id FROM items"}, func(row map[string]any) any {
+			handle.Exec(&SQLQuery{Query: "UPDATE items SET done = 1 WHERE id = ?", Params: []any{row["id"]}})
+			return nil
+		}, 2)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	var remaining int
+	drv.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM items WHERE done = 0").Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("%d rows were not updated, want 0", remaining)
+	}
+}
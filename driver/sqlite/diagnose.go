@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/telemetryos/graviton/diagnostics"
+)
+
+const DIAGNOSE_PROBE_TABLE = "graviton_doctor_probe"
+
+func (d *Driver) Diagnose(ctx context.Context) []*diagnostics.Check {
+	db, err := sql.Open("sqlite", d.config.ConnectionUrl)
+	if err == nil {
+		err = db.PingContext(ctx)
+	}
+	if err != nil {
+		return []*diagnostics.Check{
+			diagnostics.Fail("connection", err.Error(), "Check connection_url and that the server is reachable"),
+		}
+	}
+	defer db.Close()
+
+	checks := []*diagnostics.Check{diagnostics.Pass("connection", "connected")}
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		checks = append(checks, diagnostics.Warn("server version", err.Error(), "The SQLite version could not be determined"))
+	} else {
+		checks = append(checks, diagnostics.Pass("server version", "SQLite "+version))
+	}
+
+	checks = append(checks, d.diagnoseReadOnly(ctx, db))
+	for _, tableName := range []string{MIGRATIONS_TABLE, SEEDS_TABLE} {
+		checks = append(checks, d.diagnoseTrackingTable(ctx, db, tableName))
+	}
+	checks = append(checks, d.diagnoseWritePermission(ctx, db))
+
+	return checks
+}
+
+func (d *Driver) diagnoseReadOnly(ctx context.Context, db *sql.DB) *diagnostics.Check {
+	var queryOnly bool
+	if err := db.QueryRowContext(ctx, "PRAGMA query_only").Scan(&queryOnly); err != nil {
+		return diagnostics.Warn("read-only", err.Error(), "Read-only status could not be determined")
+	}
+	if queryOnly {
+		return diagnostics.Fail("read-only", "connection is query only", "Remove _pragma=query_only from connection_url")
+	}
+
+	return diagnostics.Pass("read-only", "connection accepts writes")
+}
+
+func (d *Driver) diagnoseTrackingTable(ctx context.Context, db *sql.DB, tableName string) *diagnostics.Check {
+	var exists bool
+	existsSQL := "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if err := db.QueryRowContext(ctx, existsSQL, tableName).Scan(&exists); err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "Check the database file is a valid SQLite database")
+	}
+	if !exists {
+		return diagnostics.Pass(tableName, "does not exist yet and will be created on first use")
+	}
+
+	query, err := d.renderSQL(getMigrationsSQL, tableName)
+	if err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "")
+	}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return diagnostics.Fail(tableName, err.Error(), "Check the database file is readable")
+	}
+	rows.Close()
+
+	return diagnostics.Pass(tableName, "readable")
+}
+
+func (d *Driver) diagnoseWritePermission(ctx context.Context, db *sql.DB) *diagnostics.Check {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return diagnostics.Fail("write permission", err.Error(), "")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE "+DIAGNOSE_PROBE_TABLE+" (id integer)"); err != nil {
+		return diagnostics.Fail("write permission", "cannot create tables: "+err.Error(), "Check the database file and its directory are writable")
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+DIAGNOSE_PROBE_TABLE+" (id) VALUES (1)"); err != nil {
+		return diagnostics.Fail("write permission", "cannot insert rows: "+err.Error(), "")
+	}
+
+	return diagnostics.Pass("write permission", "can create tables and insert rows")
+}
@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/diagnostics"
+)
+
+func Test_Driver_Diagnose(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	checks := drv.Diagnose(ctx)
+	if diagnostics.Failed(checks) {
+		for _, check := range checks {
+			t.Logf("%s %s: %s", check.Status, check.Name, check.Message)
+		}
+		t.Fatalf("Diagnose() reported failures for a writable database")
+	}
+
+	var probeTables int
+	drv.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = ?", DIAGNOSE_PROBE_TABLE).Scan(&probeTables)
+	if probeTables != 0 {
+		t.Errorf("Diagnose() left the probe table behind")
+	}
+}
+
+func Test_Driver_Diagnose_QueryOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	drv := New(&config.DatabaseConfig{
+		ConnectionUrl: "file:" + path + "?mode=rwc&_pragma=query_only(1)",
+	})
+
+	checks := drv.Diagnose(context.Background())
+	for _, check := range checks {
+		if check.Name == "read-only" {
+			if check.Status != diagnostics.StatusFail {
+				t.Errorf("read-only check status = %s, want fail", check.Status)
+			}
+			return
+		}
+	}
+	t.Errorf("Diagnose() did not run the read-only check")
+}
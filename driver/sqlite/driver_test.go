@@ -159,3 +159,38 @@ func Test_Driver_GetAppliedMigrationsMetadata_Empty(t *testing.T) {
 		t.Errorf("GetAppliedMigrationsMetadata() returned %d migrations, want 0", len(retrieved))
 	}
 }
+
+func Test_Driver_BackfillCheckpoint(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	filename := "20240101000000-backfill.migration.ts"
+
+	_, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+	if err != nil {
+		t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+	}
+	if found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint before one was set")
+	}
+
+	for _, checkpoint := range []string{"", "42"} {
+		if err := drv.SetBackfillCheckpoint(ctx, filename, checkpoint); err != nil {
+			t.Fatalf("SetBackfillCheckpoint() error = %v", err)
+		}
+
+		retrieved, found, err := drv.GetBackfillCheckpoint(ctx, filename)
+		if err != nil {
+			t.Fatalf("GetBackfillCheckpoint() error = %v", err)
+		}
+		if !found || retrieved != checkpoint {
+			t.Errorf("GetBackfillCheckpoint() = %q, %v, want %q, true", retrieved, found, checkpoint)
+		}
+	}
+
+	if err := drv.DeleteBackfillCheckpoint(ctx, filename); err != nil {
+		t.Fatalf("DeleteBackfillCheckpoint() error = %v", err)
+	}
+	if _, found, _ := drv.GetBackfillCheckpoint(ctx, filename); found {
+		t.Error("GetBackfillCheckpoint() found a checkpoint after it was deleted")
+	}
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/dop251/goja"
+	"modernc.org/sqlite"
+)
+
+// DatabaseError is the error a migration's statement failed with. Migrations
+// receive it as an Error carrying the SQLite result code, so they can catch
+// specific errors.
+type DatabaseError struct {
+	Err       error
+	Statement string
+}
+
+func (e *DatabaseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DatabaseError) Unwrap() error {
+	return e.Err
+}
+
+// databaseErrorIntoJSValue converts a database error into a JavaScript Error
+// named DatabaseError. Other errors are not converted.
+//
+// The code is SQLite's extended result code, such as 2067 for
+// SQLITE_CONSTRAINT_UNIQUE. SQLite names the columns of a failed constraint
+// rather than the constraint, so no constraint name is reported.
+func databaseErrorIntoJSValue(runtime *goja.Runtime, err error) (goja.Value, bool) {
+	var databaseErr *DatabaseError
+	var sqliteErr *sqlite.Error
+	isDatabaseErr := errors.As(err, &databaseErr)
+	isSQLiteErr := errors.As(err, &sqliteErr)
+	if !isDatabaseErr && !isSQLiteErr {
+		return nil, false
+	}
+
+	jsErr := runtime.NewGoError(err)
+	jsErr.Set("name", "DatabaseError")
+	if isDatabaseErr {
+		jsErr.Set("statement", databaseErr.Statement)
+	}
+	if isSQLiteErr {
+		jsErr.Set("code", sqliteErr.Code())
+	}
+	return jsErr, true
+}
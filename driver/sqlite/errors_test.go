@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/dop251/goja"
+)
+
+func Test_Handle_Exec_ThrowsDatabaseError(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	runtime := goja.New()
+
+	drv.db.ExecContext(ctx, "CREATE TABLE users (email TEXT UNIQUE)")
+	drv.db.ExecContext(ctx, "INSERT INTO users VALUES ('a@example.com')")
+
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		drv.Handle(ctx).(*Handle).Exec(&SQLQuery{Query: "INSERT INTO users VALUES (?)", Params: []any{"a@example.com"}})
+	}()
+	err, ok := recovered.(*DatabaseError)
+	if !ok {
+		t.Fatalf("Exec() panicked with %#v, want a *DatabaseError", recovered)
+	}
+
+	val, ok := drv.MaybeIntoJSValue(ctx, runtime, err, nil)
+	if !ok {
+		t.Fatal("MaybeIntoJSValue() ok = false, want true")
+	}
+	runtime.Set("err", val)
+
+	expected := map[string]any{
+		"err instanceof Error": true,
+		"err.name":             "DatabaseError",
+		"err.code":             int64(2067),
+		"err.statement":        "INSERT INTO users VALUES (?)",
+	}
+	for src, want := range expected {
+		got, err := runtime.RunString(src)
+		if err != nil {
+			t.Fatalf("RunString(%q) error = %v", src, err)
+		}
+		if got.Export() != want {
+			t.Errorf("%s = %#v, want %#v", src, got.Export(), want)
+		}
+	}
+}
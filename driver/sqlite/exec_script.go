@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"strings"
+)
+
+// ExecScript runs each statement of a SQL script in order, such as a schema
+// dump. A failed statement stops the script, and its error carries the
+// statement that failed.
+func (h *Handle) ExecScript(script string) {
+	for _, statement := range splitStatements(script) {
+		h.Exec(&SQLQuery{Query: statement})
+	}
+}
+
+// splitStatements splits a script on the semicolons that end its statements.
+// Semicolons in strings, quoted identifiers, comments and the BEGIN ... END
+// bodies of triggers do not end a statement. Statements holding nothing but
+// comments are dropped.
+func splitStatements(script string) []string {
+	var statements []string
+	start := 0
+	hasContent := false
+	// words holds the first words of the statement, to tell whether it
+	// creates a trigger.
+	var words []string
+	// depth counts the trigger bodies and the CASE expressions in them that
+	// are still open.
+	depth := 0
+
+	endStatement := func(end int) {
+		if hasContent {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = end + 1
+		hasContent = false
+		words = nil
+		depth = 0
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case strings.HasPrefix(script[i:], "--"):
+			i = skipPast(script, i, "\n")
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipPast(script, i+2, "*/")
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i, c)
+			hasContent = true
+		case c == '[':
+			i = skipPast(script, i, "]")
+			hasContent = true
+		case c == ';':
+			if depth > 0 {
+				i += 1
+				continue
+			}
+			endStatement(i)
+			i += 1
+		case isWordChar(c):
+			wordStart := i
+			for i < len(script) && isWordChar(script[i]) {
+				i += 1
+			}
+			word := strings.ToUpper(script[wordStart:i])
+			if len(words) < 4 {
+				words = append(words, word)
+			}
+			if isCreateTrigger(words) {
+				switch word {
+				case "BEGIN", "CASE":
+					depth += 1
+				case "END":
+					depth -= 1
+				}
+			}
+			hasContent = true
+		default:
+			if c > ' ' {
+				hasContent = true
+			}
+			i += 1
+		}
+	}
+	endStatement(len(script))
+
+	return statements
+}
+
+// isCreateTrigger reports whether the first words of a statement are those of
+// CREATE [TEMP | TEMPORARY] TRIGGER.
+func isCreateTrigger(words []string) bool {
+	if len(words) < 2 || words[0] != "CREATE" {
+		return false
+	}
+	if words[1] == "TEMP" || words[1] == "TEMPORARY" {
+		return len(words) > 2 && words[2] == "TRIGGER"
+	}
+	return words[1] == "TRIGGER"
+}
+
+// skipQuoted returns the position after the string or quoted identifier
+// starting at i. A doubled quote is part of the text.
+func skipQuoted(script string, i int, quote byte) int {
+	for i += 1; i < len(script); i += 1 {
+		if script[i] != quote {
+			continue
+		}
+		if i+1 < len(script) && script[i+1] == quote {
+			i += 1
+			continue
+		}
+		return i + 1
+	}
+	return len(script)
+}
+
+// skipPast returns the position after the next end found after i, or the end
+// of the script.
+func skipPast(script string, i int, end string) int {
+	next := strings.Index(script[i:], end)
+	if next < 0 {
+		return len(script)
+	}
+	return i + next + len(end)
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			"statements",
+			"CREATE TABLE a (id int);\nINSERT INTO a VALUES (1);\n",
+			[]string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			"strings and identifiers",
+			"INSERT INTO [a;b] VALUES ('x;''y', \"z;\", `w;`);SELECT 1",
+			[]string{"INSERT INTO [a;b] VALUES ('x;''y', \"z;\", `w;`)", "SELECT 1"},
+		},
+		{
+			"comments",
+			"-- a; b\nSELECT 1; /* c; */ SELECT 2;\n-- trailing;",
+			[]string{"-- a; b\nSELECT 1", "/* c; */ SELECT 2"},
+		},
+		{
+			"triggers",
+			"CREATE TEMP TRIGGER t AFTER INSERT ON a BEGIN UPDATE a SET id = CASE WHEN id > 0 THEN id END; DELETE FROM b; END;\nBEGIN; SELECT 1; END;",
+			[]string{
+				"CREATE TEMP TRIGGER t AFTER INSERT ON a BEGIN UPDATE a SET id = CASE WHEN id > 0 THEN id END; DELETE FROM b; END",
+				"BEGIN",
+				"SELECT 1",
+				"END",
+			},
+		},
+		{
+			"empty",
+			" ;\n; -- nothing\n",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := splitStatements(test.script); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("splitStatements() = %q, want %q", got, test.expected)
+			}
+		})
+	}
+}
+
+func Test_Handle_ExecScript(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	h.ExecScript(`
+		-- schema dump
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, renamed INTEGER DEFAULT 0);
+		CREATE TRIGGER users_renamed AFTER UPDATE OF name ON users BEGIN
+			UPDATE users SET renamed = renamed + 1 WHERE id = NEW.id;
+		END;
+		INSERT INTO users (id, name) VALUES (1, 'Ada; Lovelace');
+		UPDATE users SET name = 'Ada' WHERE id = 1;
+	`)
+
+	row := h.QueryOne(&SQLQuery{Query: "SELECT name, renamed FROM users WHERE id = 1"})
+	if row["name"] != "Ada" || row["renamed"] != int64(1) {
+		t.Errorf("row = %v, want name Ada renamed once", row)
+	}
+
+	defer func() {
+		err, ok := recover().(*DatabaseError)
+		if !ok || err.Statement != "INSERT INTO missing VALUES (1)" {
+			t.Errorf("ExecScript() panicked with %v, want a *DatabaseError for the failed statement", err)
+		}
+	}()
+	h.ExecScript("SELECT 1; INSERT INTO missing VALUES (1); SELECT 2;")
+}
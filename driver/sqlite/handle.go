@@ -3,11 +3,21 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
 )
 
 type Handle struct {
 	ctx    context.Context
 	driver *Driver
+
+	// Schema makes portable schema changes, as db.schema in migrations.
+	Schema *Schema
+}
+
+func newHandle(ctx context.Context, d *Driver) *Handle {
+	h := &Handle{ctx: ctx, driver: d}
+	h.Schema = &Schema{handle: h}
+	return h
 }
 
 type SQLResult struct {
@@ -20,12 +30,12 @@ func (h *Handle) Exec(sqlQuery *SQLQuery) *SQLResult {
 
 	result, err := execer.ExecContext(h.ctx, sqlQuery.Query, sqlQuery.Params...)
 	if err != nil {
-		panic(err)
+		panic(&DatabaseError{Err: err, Statement: sqlQuery.Query})
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		panic(err)
+		panic(&DatabaseError{Err: err, Statement: sqlQuery.Query})
 	}
 
 	lastInsertId, _ := result.LastInsertId()
@@ -37,6 +47,34 @@ func (h *Handle) Exec(sqlQuery *SQLQuery) *SQLResult {
 }
 
 func (h *Handle) Query(sqlQuery *SQLQuery) []map[string]any {
+	rows := h.queryRows(sqlQuery)
+	defer rows.Close()
+
+	results, err := readRows(rows, 0)
+	if err != nil {
+		panic(&DatabaseError{Err: err, Statement: sqlQuery.Query})
+	}
+	return results
+}
+
+func (h *Handle) QueryOne(sqlQuery *SQLQuery) map[string]any {
+	results := h.Query(sqlQuery)
+	if len(results) == 0 {
+		return nil
+	}
+	return results[0]
+}
+
+func (h *Handle) getExecutor() interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+} {
+	if tx := h.driver.getTxFromContext(h.ctx); tx != nil {
+		return tx
+	}
+	return h.driver.db
+}
+
+func (h *Handle) queryRows(sqlQuery *SQLQuery) *sql.Rows {
 	execer := h.getExecutor()
 
 	var rows *sql.Rows
@@ -50,54 +88,44 @@ func (h *Handle) Query(sqlQuery *SQLQuery) []map[string]any {
 	}
 
 	if err != nil {
-		panic(err)
+		panic(&DatabaseError{Err: err, Statement: sqlQuery.Query})
 	}
-	defer rows.Close()
+	return rows
+}
 
-	columns, err := rows.Columns()
+// readRows reads up to limit rows, or all of them when limit is 0. Fewer rows
+// than the limit means the result has been read to the end.
+func readRows(rows *sql.Rows, limit int) ([]map[string]any, error) {
+	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	var results []map[string]any
-	for rows.Next() {
-		values := make([]any, len(columns))
-		valuePtrs := make([]any, len(columns))
+	for limit == 0 || len(results) < limit {
+		if !rows.Next() {
+			return results, rows.Err()
+		}
+
+		values := make([]any, len(columnTypes))
+		valuePtrs := make([]any, len(columnTypes))
 		for i := range values {
 			valuePtrs[i] = &values[i]
 		}
 
 		if err := rows.Scan(valuePtrs...); err != nil {
-			panic(err)
+			return nil, err
 		}
 
 		row := make(map[string]any)
-		for i, col := range columns {
-			row[col] = values[i]
+		for i, columnType := range columnTypes {
+			value, err := decodeValue(columnType.DatabaseTypeName(), values[i])
+			if err != nil {
+				return nil, fmt.Errorf("column `%s`: %w", columnType.Name(), err)
+			}
+			row[columnType.Name()] = value
 		}
 		results = append(results, row)
 	}
-
-	if err := rows.Err(); err != nil {
-		panic(err)
-	}
-
-	return results
-}
-
-func (h *Handle) QueryOne(sqlQuery *SQLQuery) map[string]any {
-	results := h.Query(sqlQuery)
-	if len(results) == 0 {
-		return nil
-	}
-	return results[0]
-}
-
-func (h *Handle) getExecutor() interface {
-	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
-} {
-	if tx := h.driver.getTxFromContext(h.ctx); tx != nil {
-		return tx
-	}
-	return h.driver.db
+	return results, nil
 }
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_Handle_InsertMany(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	drv.db.ExecContext(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, price REAL)")
+
+	rows := make([]any, 2500)
+	for i := range rows {
+		rows[i] = map[string]any{"id": int64(i + 1), "name": "item", "price": 1.5}
+	}
+
+	err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+		inserted := drv.Handle(txCtx).(*Handle).InsertMany("items", rows, map[string]any{"batchSize": int64(1000)})
+		if inserted != 2500 {
+			t.Errorf("InsertMany() = %d, want 2500", inserted)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+
+	row := drv.Handle(ctx).(*Handle).QueryOne(&SQLQuery{Query: "SELECT COUNT(*) AS count, SUM(price) AS total FROM items"})
+	if row["count"] != int64(2500) || row["total"] != 3750.0 {
+		t.Errorf("row = %v, want 2500 rows totalling 3750", row)
+	}
+}
+
+func Test_Handle_InsertMany_OutsideTransaction(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	drv.db.ExecContext(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	h := drv.Handle(ctx).(*Handle)
+
+	func() {
+		defer func() {
+			if _, ok := recover().(*DatabaseError); !ok {
+				t.Error("InsertMany() did not fail with a *DatabaseError")
+			}
+		}()
+		h.InsertMany("items", []any{map[string]any{"id": int64(1)}, map[string]any{"id": int64(2)}, map[string]any{"id": int64(1)}}, &InsertManyOptions{BatchSize: 2})
+	}()
+
+	row := h.QueryOne(&SQLQuery{Query: "SELECT COUNT(*) AS count FROM items"})
+	if row["count"] != int64(0) {
+		t.Errorf("count = %v, want 0 as the failed insert is rolled back", row["count"])
+	}
+
+	if inserted := h.InsertMany("items", []any{}); inserted != 0 {
+		t.Errorf("InsertMany() of no rows = %d, want 0", inserted)
+	}
+}
+
+func Test_Handle_InsertMany_Errors(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	tests := []struct {
+		name     string
+		rows     []any
+		opts     []any
+		expected string
+	}{
+		{"row not an object", []any{"a"}, nil, "insertMany: row 0 is not an object"},
+		{"missing column", []any{map[string]any{"a": 1, "b": 2}, map[string]any{"a": 1}}, nil, "insertMany: row 1 has different columns than the first row"},
+		{"other column", []any{map[string]any{"a": 1}, map[string]any{"b": 1}}, nil, "insertMany: row 1 has different columns than the first row"},
+		{"invalid batch size", []any{map[string]any{"a": 1}}, []any{map[string]any{"batchSize": 2.5}}, "insertMany options: batchSize must be a positive integer"},
+		{"unknown option", []any{map[string]any{"a": 1}}, []any{map[string]any{"chunk": int64(1)}}, "insertMany options: unknown option `chunk`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if err == nil || !strings.Contains(err.Error(), test.expected) {
+					t.Errorf("InsertMany() panicked with %v, want %q", err, test.expected)
+				}
+			}()
+			h.InsertMany("items", test.rows, test.opts...)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+// ColumnInfo describes a column of a table, as returned by ListColumns.
+type ColumnInfo struct {
+	Name string
+	// Type is the column's declared type, such as VARCHAR(255).
+	Type     string
+	Nullable bool
+	// Default is the SQL of the column's default, or nil when it has none.
+	Default    any
+	PrimaryKey bool
+}
+
+// IndexInfo describes an index of a table, as returned by ListIndexes.
+type IndexInfo struct {
+	Name string
+	// Columns are the indexed columns in order. Expressions are left out.
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// TableExists reports whether a table exists. Like every name in SQLite, the
+// table name is matched without regard to case.
+func (h *Handle) TableExists(table string) bool {
+	schema, name := introspectionTableName(table)
+	return h.QueryOne(&SQLQuery{
+		Query:  "SELECT 1 AS found FROM " + qualifiedName(schema, "sqlite_master") + " WHERE type = 'table' AND name = ? COLLATE NOCASE",
+		Params: []any{name},
+	}) != nil
+}
+
+// ColumnExists reports whether a table has a column.
+func (h *Handle) ColumnExists(table string, column string) bool {
+	return slices.ContainsFunc(h.ListColumns(table), func(info ColumnInfo) bool {
+		return strings.EqualFold(info.Name, column)
+	})
+}
+
+// IndexExists reports whether a table has an index, including the indexes
+// SQLite creates for UNIQUE and PRIMARY KEY constraints.
+func (h *Handle) IndexExists(table string, index string) bool {
+	return slices.ContainsFunc(h.ListIndexes(table), func(info IndexInfo) bool {
+		return strings.EqualFold(info.Name, index)
+	})
+}
+
+// ListTables returns the names of the tables of the main database in order,
+// apart from SQLite's and Graviton's own.
+func (h *Handle) ListTables() []string {
+	tables := []string{}
+	for _, row := range h.Query(&SQLQuery{Query: `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY name`}) {
+		name := row["name"].(string)
+		if !isGravitonTable(name) {
+			tables = append(tables, name)
+		}
+	}
+	return tables
+}
+
+// ListColumns returns the columns of a table in order, or none when there is
+// no such table.
+func (h *Handle) ListColumns(table string) []ColumnInfo {
+	schema, name := introspectionTableName(table)
+	columns := []ColumnInfo{}
+	for _, row := range h.Query(&SQLQuery{
+		Query:  `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?, ?) ORDER BY cid`,
+		Params: []any{name, schema},
+	}) {
+		primaryKey := row["pk"] != int64(0)
+		columns = append(columns, ColumnInfo{
+			Name:       row["name"].(string),
+			Type:       row["type"].(string),
+			Nullable:   row["notnull"] == int64(0) && !primaryKey,
+			Default:    row["dflt_value"],
+			PrimaryKey: primaryKey,
+		})
+	}
+	return columns
+}
+
+// ListIndexes returns the indexes of a table by name, or none when there is no
+// such table.
+func (h *Handle) ListIndexes(table string) []IndexInfo {
+	schema, name := introspectionTableName(table)
+	indexes := []IndexInfo{}
+	for _, row := range h.Query(&SQLQuery{
+		Query:  `SELECT name, "unique", origin FROM pragma_index_list(?, ?) ORDER BY name`,
+		Params: []any{name, schema},
+	}) {
+		index := IndexInfo{
+			Name:    row["name"].(string),
+			Columns: []string{},
+			Unique:  row["unique"] != int64(0),
+			Primary: row["origin"] == "pk",
+		}
+		for _, column := range h.Query(&SQLQuery{
+			Query:  "SELECT name FROM pragma_index_info(?, ?) WHERE name IS NOT NULL ORDER BY seqno",
+			Params: []any{index.Name, schema},
+		}) {
+			index.Columns = append(index.Columns, column["name"].(string))
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+// introspectionTableName splits a table name into its schema, which is main
+// unless another is given, and its name.
+func introspectionTableName(table string) (string, string) {
+	schema, name := sqlschema.SplitTableName(table)
+	if schema == "" {
+		schema = "main"
+	}
+	return schema, name
+}
+
+func isGravitonTable(name string) bool {
+	return name == MIGRATIONS_TABLE || name == SEEDS_TABLE || name == BACKFILLS_TABLE
+}
@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Handle_Introspection(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			email VARCHAR(320) NOT NULL UNIQUE,
+			active BOOLEAN DEFAULT TRUE
+		);
+		CREATE INDEX users_active_email ON users (active, lower(email), email);
+		CREATE VIEW active_users AS SELECT * FROM users WHERE active;
+	`)
+
+	if !h.TableExists("users") || !h.TableExists("main.USERS") || h.TableExists("active_users") || h.TableExists("posts") {
+		t.Errorf("TableExists() is wrong for users, active_users or posts")
+	}
+	if !h.ColumnExists("users", "email") || h.ColumnExists("users", "name") || h.ColumnExists("posts", "id") {
+		t.Errorf("ColumnExists() is wrong for email, name or posts.id")
+	}
+	if !h.IndexExists("users", "users_active_email") || h.IndexExists("users", "users_email") {
+		t.Errorf("IndexExists() is wrong for users_active_email or users_email")
+	}
+
+	if tables := h.ListTables(); !reflect.DeepEqual(tables, []string{"users"}) {
+		t.Errorf("ListTables() = %v, want [users]", tables)
+	}
+
+	wantColumns := []ColumnInfo{
+		{Name: "id", Type: "INTEGER", Nullable: false, Default: nil, PrimaryKey: true},
+		{Name: "email", Type: "VARCHAR(320)", Nullable: false, Default: nil},
+		{Name: "active", Type: "BOOLEAN", Nullable: true, Default: "TRUE"},
+	}
+	if columns := h.ListColumns("users"); !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("ListColumns() = %+v, want %+v", columns, wantColumns)
+	}
+	if columns := h.ListColumns("posts"); len(columns) != 0 {
+		t.Errorf("ListColumns(posts) = %+v, want none", columns)
+	}
+
+	wantIndexes := []IndexInfo{
+		{Name: "sqlite_autoindex_users_1", Columns: []string{"email"}, Unique: true},
+		{Name: "users_active_email", Columns: []string{"active", "email"}},
+	}
+	if indexes := h.ListIndexes("users"); !reflect.DeepEqual(indexes, wantIndexes) {
+		t.Errorf("ListIndexes() = %+v, want %+v", indexes, wantIndexes)
+	}
+}
@@ -0,0 +1,180 @@
+type SQLQuery = {
+  query: string;
+  params: any[];
+  validated: boolean;
+}
+
+type SQLResult = {
+  rowsAffected: number;
+}
+
+type Cursor<T = any> = {
+  hasNext(): boolean;
+  next(): T | null;
+  forEach(callback: (row: T) => boolean | void): void;
+  toArray(): T[];
+  close(): void;
+}
+
+type InsertManyOptions = {
+  batchSize?: number;
+}
+
+type ColumnType =
+  | "increments"
+  | "bigIncrements"
+  | "integer"
+  | "bigInteger"
+  | "smallInteger"
+  | "float"
+  | "double"
+  | "decimal"
+  | "boolean"
+  | "string"
+  | "text"
+  | "uuid"
+  | "date"
+  | "timestamp"
+  | "json"
+  | "binary";
+
+type ReferentialAction = "CASCADE" | "SET NULL" | "SET DEFAULT" | "RESTRICT" | "NO ACTION";
+
+type ColumnReference = {
+  table: string;
+  column?: string;
+  name?: string;
+  onDelete?: ReferentialAction;
+  onUpdate?: ReferentialAction;
+}
+
+type Column = {
+  name: string;
+  type: ColumnType;
+  length?: number;
+  precision?: number;
+  scale?: number;
+  nullable?: boolean;
+  unique?: boolean;
+  primaryKey?: boolean;
+  default?: string | number | boolean | null | SQLQuery;
+  defaultNow?: boolean;
+  references?: ColumnReference;
+}
+
+type ForeignKey = {
+  columns: string[];
+  references: {
+    table: string;
+    columns?: string[];
+  };
+  name?: string;
+  onDelete?: ReferentialAction;
+  onUpdate?: ReferentialAction;
+}
+
+type TableDefinition = {
+  columns: Column[];
+  primaryKey?: string[];
+  unique?: string[][];
+  foreignKeys?: ForeignKey[];
+  ifNotExists?: boolean;
+}
+
+type IndexOptions = {
+  name?: string;
+  unique?: boolean;
+  ifNotExists?: boolean;
+}
+
+type DropOptions = {
+  ifExists?: boolean;
+}
+
+type Schema = {
+  createTable(table: string, definition: TableDefinition): void;
+  dropTable(table: string, options?: DropOptions): void;
+  renameTable(table: string, newName: string): void;
+  addColumn(table: string, column: Column): void;
+  dropColumn(table: string, column: string): void;
+  renameColumn(table: string, column: string, newName: string): void;
+  createIndex(table: string, columns: string[], options?: IndexOptions): void;
+  dropIndex(table: string, index: string, options?: DropOptions): void;
+  addForeignKey(table: string, foreignKey: ForeignKey): void;
+  dropForeignKey(table: string, name: string): void;
+}
+
+type ColumnInfo = {
+  name: string;
+  type: string;
+  nullable: boolean;
+  default: any;
+  primaryKey: boolean;
+}
+
+type IndexInfo = {
+  name: string;
+  columns: string[];
+  unique: boolean;
+  primary: boolean;
+}
+
+type Handle = {
+  schema: Schema;
+  exec(query: SQLQuery): SQLResult;
+  execScript(script: string): void;
+  insertMany(table: string, rows: Record<string, any>[], options?: InsertManyOptions): number;
+  query<T = any>(query: SQLQuery): T[];
+  queryOne<T = any>(query: SQLQuery): T | null;
+  cursor<T = any>(query: SQLQuery, batchSize?: number): Cursor<T>;
+  forEach<T = any>(query: SQLQuery, callback: (row: T) => boolean | void, batchSize?: number): void;
+  transaction<T = void>(fn: (db: Handle) => T): T;
+  tableExists(table: string): boolean;
+  columnExists(table: string, column: string): boolean;
+  indexExists(table: string, index: string): boolean;
+  listTables(): string[];
+  listColumns(table: string): ColumnInfo[];
+  listIndexes(table: string): IndexInfo[];
+  rebuildTable(table: string, definition: SQLQuery | string | TableDefinition, columnMapping?: Record<string, string | SQLQuery | null>): void;
+}
+
+type SQLFragment = {
+  readonly __sqlFragment: true;
+}
+
+declare function sql(strings: TemplateStringsArray, ...values: any[]): SQLQuery;
+
+declare namespace sql {
+  function ident(...names: string[]): SQLFragment;
+  function raw(text: string): SQLFragment;
+  function join(items: any[], separator?: string): SQLQuery;
+}
+
+type DatabaseError = Error & {
+  name: "DatabaseError";
+  code?: number;
+  statement?: string;
+}
+
+type Databases = Record<string, any>;
+
+type Backfill<Checkpoint = any> = {
+  batchSize?: number;
+  throttleMs?: number;
+  batch(db: Handle, checkpoint: Checkpoint | null, batchSize: number, databases: Databases): Checkpoint | null | undefined;
+}
+
+type Console = {
+  log(...args: any[]): void;
+}
+declare const console: Console;
+
+declare module "*.sql" {
+  const text: string;
+  export default text;
+}
+
+declare module "*.csv" {
+  const text: string;
+  export default text;
+}
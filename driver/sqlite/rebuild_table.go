@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+// REBUILD_TABLE_PREFIX prefixes the name of the temporary table holding the
+// rows of a table while it is rebuilt.
+const REBUILD_TABLE_PREFIX = "graviton_rebuild_"
+
+// RebuildTable makes the changes to a table that SQLite's ALTER TABLE cannot,
+// such as changing a column's type or dropping a constraint, by creating the
+// table again from a new definition and copying its rows, as described in
+// https://www.sqlite.org/lang_altertable.html#otheralter.
+//
+// The definition is a CREATE TABLE statement for the table, or a table
+// definition as given to schema.createTable. Each column of the new table is
+// copied from the old column of the same name, unless columnMapping maps it to
+// another old column by name, to a sql expression over the old columns, or to
+// null to leave it to its default. The table's indexes and triggers are created
+// again afterwards as they were, so those on columns the new table no longer
+// has must be dropped first. A foreign key check of the rows the table
+// references and the rows referencing it must pass.
+//
+// SQLite only lets foreign keys be switched off outside a transaction, so
+// rather than renaming a new table into place, the rows are copied aside and
+// back into the table created again under its own name. When foreign keys are
+// enforced their checks are deferred until the transaction commits, and tables
+// whose foreign keys to this one have an ON DELETE action, which dropping the
+// table would run, are an error. Outside a migration's transaction the table is
+// rebuilt in a transaction of its own.
+func (h *Handle) RebuildTable(table string, definition any, columnMapping ...any) {
+	if h.driver.getTxFromContext(h.ctx) == nil {
+		err := h.driver.WithTransaction(h.ctx, func(txCtx context.Context) error {
+			newHandle(txCtx, h.driver).RebuildTable(table, definition, columnMapping...)
+			return nil
+		})
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	createStatement := rebuildTableStatement(table, definition)
+	mapping := decodeColumnMapping(columnMapping)
+	schema, name := introspectionTableName(table)
+	qualifiedTable := qualifiedName(schema, name)
+
+	oldColumns := h.tableColumns(schema, name)
+	if len(oldColumns) == 0 {
+		panic(fmt.Errorf("rebuildTable: no such table: %s", table))
+	}
+
+	// deferring is set when foreign key checks are deferred for the rebuild,
+	// and undone once the table's rows are back. A failed rebuild rolls the
+	// transaction back, which undoes it as well.
+	deferring := false
+	if h.QueryOne(&SQLQuery{Query: "PRAGMA foreign_keys"})["foreign_keys"] == int64(1) {
+		h.checkReferencingActions(schema, name)
+		if h.QueryOne(&SQLQuery{Query: "PRAGMA defer_foreign_keys"})["defer_foreign_keys"] != int64(1) {
+			h.Exec(&SQLQuery{Query: "PRAGMA defer_foreign_keys = ON"})
+			deferring = true
+		}
+	}
+
+	var dependents []string
+	for _, row := range h.Query(&SQLQuery{
+		Query:  "SELECT sql FROM " + qualifiedName(schema, "sqlite_master") + " WHERE tbl_name = ? AND type IN ('index', 'trigger') AND sql IS NOT NULL ORDER BY type, name",
+		Params: []any{name},
+	}) {
+		dependents = append(dependents, row["sql"].(string))
+	}
+
+	backupTable := qualifiedName("temp", REBUILD_TABLE_PREFIX+name)
+	h.Exec(&SQLQuery{Query: "CREATE TABLE " + backupTable + " AS SELECT * FROM " + qualifiedTable})
+	h.Exec(&SQLQuery{Query: "DROP TABLE " + qualifiedTable})
+	h.Exec(&SQLQuery{Query: createStatement})
+
+	newColumns := h.tableColumns(schema, name)
+	if len(newColumns) == 0 {
+		panic(fmt.Errorf("rebuildTable: the new definition does not create %s", table))
+	}
+	columns, expressions := rebuildTableCopy(oldColumns, newColumns, mapping)
+	h.Exec(&SQLQuery{Query: "INSERT INTO " + qualifiedTable + " (" + quoteColumns(columns) + ") SELECT " + strings.Join(expressions, ", ") + " FROM " + backupTable})
+	h.Exec(&SQLQuery{Query: "DROP TABLE " + backupTable})
+
+	for _, statement := range dependents {
+		h.Exec(&SQLQuery{Query: statement})
+	}
+
+	h.checkForeignKeys(schema, name)
+	if deferring {
+		h.Exec(&SQLQuery{Query: "PRAGMA defer_foreign_keys = OFF"})
+	}
+}
+
+// tableColumns returns the names of the columns of a table, or none when
+// there is no such table.
+func (h *Handle) tableColumns(schema string, table string) []string {
+	var columns []string
+	for _, row := range h.Query(&SQLQuery{Query: "SELECT name FROM pragma_table_info(?, ?) ORDER BY cid", Params: []any{table, schema}}) {
+		columns = append(columns, row["name"].(string))
+	}
+	return columns
+}
+
+// checkReferencingActions panics when another table's foreign key to the table
+// has an ON DELETE action, which dropping the table would run on its rows.
+func (h *Handle) checkReferencingActions(schema string, table string) {
+	rows := h.Query(&SQLQuery{
+		Query: "SELECT m.name AS name, f.on_delete AS on_delete FROM " + qualifiedName(schema, "sqlite_master") + " AS m, pragma_foreign_key_list(m.name, ?) AS f" +
+			" WHERE m.type = 'table' AND f.\"table\" = ? COLLATE NOCASE AND m.name <> ? COLLATE NOCASE AND f.on_delete <> 'NO ACTION'",
+		Params: []any{schema, table, table},
+	})
+	if len(rows) > 0 {
+		panic(fmt.Errorf("rebuildTable: %s has a foreign key to %s with ON DELETE %s, which dropping %s would run; rebuild it with foreign keys switched off", rows[0]["name"], table, rows[0]["on_delete"], table))
+	}
+}
+
+// checkForeignKeys panics when a row of the table references a missing row,
+// or a row of another table references a missing row of the table.
+func (h *Handle) checkForeignKeys(schema string, table string) {
+	for _, row := range h.Query(&SQLQuery{Query: "PRAGMA " + quoteIdentifier([]string{schema}) + ".foreign_key_check"}) {
+		child, _ := row["table"].(string)
+		parent, _ := row["parent"].(string)
+		if strings.EqualFold(child, table) || strings.EqualFold(parent, table) {
+			panic(fmt.Errorf("rebuildTable: foreign key check failed: row %v of %s references a missing row of %s", row["rowid"], child, parent))
+		}
+	}
+}
+
+func rebuildTableStatement(table string, definition any) string {
+	switch definition := definition.(type) {
+	case string:
+		return definition
+	case *SQLQuery:
+		if len(definition.Params) > 0 {
+			panic(fmt.Errorf("rebuildTable: the CREATE TABLE statement cannot have parameters"))
+		}
+		return definition.Query
+	case map[string]any:
+		return createTableStatement(table, sqlschema.DecodeTable("rebuildTable", definition))
+	default:
+		panic(fmt.Errorf("rebuildTable: definition must be a CREATE TABLE statement or a table definition"))
+	}
+}
+
+// decodeColumnMapping reads the column mapping passed to RebuildTable. Each
+// value is the name of an old column, a *SQLQuery or nil.
+func decodeColumnMapping(opts []any) map[string]any {
+	mapping := map[string]any{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		fields, ok := opt.(map[string]any)
+		if !ok {
+			panic(fmt.Errorf("rebuildTable: columnMapping must be an object"))
+		}
+		for column, value := range fields {
+			switch value := value.(type) {
+			case nil, string:
+			case *SQLQuery:
+				if len(value.Params) > 0 {
+					panic(fmt.Errorf("rebuildTable: columnMapping: the expression for %s cannot have parameters", column))
+				}
+			default:
+				panic(fmt.Errorf("rebuildTable: columnMapping: %s must be a column name, a sql expression or null", column))
+			}
+			mapping[column] = value
+		}
+	}
+	return mapping
+}
+
+// rebuildTableCopy returns the columns of the new table that are copied, and
+// the expressions over the old columns that they are copied from.
+func rebuildTableCopy(oldColumns []string, newColumns []string, mapping map[string]any) ([]string, []string) {
+	for column := range mapping {
+		if !slices.Contains(newColumns, column) {
+			panic(fmt.Errorf("rebuildTable: columnMapping: %s is not a column of the new table", column))
+		}
+	}
+
+	var columns, expressions []string
+	for _, column := range newColumns {
+		value, mapped := mapping[column]
+		switch {
+		case !mapped && slices.Contains(oldColumns, column):
+			expressions = append(expressions, quoteIdentifier([]string{column}))
+		case !mapped, value == nil:
+			continue
+		default:
+			if query, ok := value.(*SQLQuery); ok {
+				expressions = append(expressions, "("+query.Query+")")
+			} else {
+				expressions = append(expressions, quoteIdentifier([]string{value.(string)}))
+			}
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		panic(fmt.Errorf("rebuildTable: no column of the new table is copied from the old one"))
+	}
+	return columns, expressions
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// setupForeignKeys creates users and the posts referencing them, with foreign
+// keys enforced when enforce is set. The pool is limited to one connection so
+// the PRAGMA applies to every statement.
+func setupForeignKeys(t *testing.T, enforce bool, onDelete string) (*Driver, context.Context, *Handle) {
+	t.Helper()
+
+	drv, ctx := setupTestDriver(t)
+	drv.db.SetMaxOpenConns(1)
+	if enforce {
+		drv.db.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(10) NOT NULL, age TEXT);
+		CREATE INDEX users_name ON users (name);
+		CREATE TABLE audit (message TEXT);
+		CREATE TRIGGER users_audit AFTER INSERT ON users BEGIN
+			INSERT INTO audit VALUES ('inserted ' || NEW.name);
+		END;
+		CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id) ` + onDelete + `);
+		INSERT INTO users (id, name, age) VALUES (1, 'Ada', '36'), (2, 'Grace', NULL);
+		INSERT INTO posts (user_id) VALUES (1), (2);
+	`)
+	return drv, ctx, h
+}
+
+func Test_Handle_RebuildTable(t *testing.T) {
+	for _, enforce := range []bool{false, true} {
+		name := "foreign keys off"
+		if enforce {
+			name = "foreign keys on"
+		}
+		t.Run(name, func(t *testing.T) {
+			drv, ctx, h := setupForeignKeys(t, enforce, "")
+
+			err := drv.WithTransaction(ctx, func(txCtx context.Context) error {
+				tx := drv.Handle(txCtx).(*Handle)
+				tx.Exec(&SQLQuery{Query: "DROP INDEX users_name"})
+				tx.RebuildTable("users", &SQLQuery{Query: `
+					CREATE TABLE users (
+						id INTEGER PRIMARY KEY,
+						full_name TEXT NOT NULL,
+						age INTEGER CHECK (age > 0),
+						admin BOOLEAN NOT NULL DEFAULT FALSE
+					)
+				`}, map[string]any{
+					"full_name": &SQLQuery{Query: "upper(name)"},
+					"age":       nil,
+				})
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WithTransaction() error = %v", err)
+			}
+
+			users := h.Query(&SQLQuery{Query: "SELECT * FROM users ORDER BY id"})
+			if len(users) != 2 || users[0]["full_name"] != "ADA" || users[0]["age"] != nil || users[1]["admin"] != false {
+				t.Errorf("users = %v, want Ada and Grace rebuilt", users)
+			}
+
+			var dependents []string
+			for _, row := range h.Query(&SQLQuery{Query: "SELECT name FROM sqlite_master WHERE tbl_name = 'users' AND type IN ('index', 'trigger') ORDER BY name"}) {
+				dependents = append(dependents, row["name"].(string))
+			}
+			if strings.Join(dependents, ",") != "users_audit" {
+				t.Errorf("indexes and triggers = %v, want users_audit", dependents)
+			}
+
+			posts := h.QueryOne(&SQLQuery{Query: "SELECT COUNT(*) AS count FROM posts JOIN users ON users.id = posts.user_id"})
+			if posts["count"] != int64(2) {
+				t.Errorf("posts = %v, want both posts kept", posts)
+			}
+		})
+	}
+}
+
+func Test_Handle_RebuildTable_KeepsIndexes(t *testing.T) {
+	_, _, h := setupForeignKeys(t, false, "")
+
+	h.RebuildTable("users", map[string]any{
+		"columns": []any{
+			map[string]any{"name": "id", "type": "integer", "primaryKey": true},
+			map[string]any{"name": "name", "type": "text", "nullable": false},
+		},
+	})
+
+	index := h.QueryOne(&SQLQuery{Query: "SELECT sql FROM sqlite_master WHERE name = 'users_name'"})
+	if index == nil || index["sql"] != "CREATE INDEX users_name ON users (name)" {
+		t.Errorf("index = %v, want users_name created again", index)
+	}
+	if tables := h.Query(&SQLQuery{Query: "SELECT name FROM temp.sqlite_master"}); len(tables) != 0 {
+		t.Errorf("temp tables = %v, want none left", tables)
+	}
+}
+
+func Test_Handle_RebuildTable_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		enforce  bool
+		onDelete string
+		rebuild  func(h *Handle)
+		want     string
+	}{
+		{
+			name:     "cascading foreign key",
+			enforce:  true,
+			onDelete: "ON DELETE CASCADE",
+			rebuild: func(h *Handle) {
+				h.RebuildTable("users", "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+			},
+			want: "rebuildTable: posts has a foreign key to users with ON DELETE CASCADE, which dropping users would run; rebuild it with foreign keys switched off",
+		},
+		{
+			name: "missing referenced rows",
+			rebuild: func(h *Handle) {
+				h.RebuildTable("users", "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)", map[string]any{"id": &SQLQuery{Query: "id + 10"}})
+			},
+			want: "rebuildTable: foreign key check failed: row 1 of posts references a missing row of users",
+		},
+		{
+			name: "unknown column",
+			rebuild: func(h *Handle) {
+				h.RebuildTable("users", "CREATE TABLE users (id INTEGER PRIMARY KEY)", map[string]any{"name": "name"})
+			},
+			want: "rebuildTable: columnMapping: name is not a column of the new table",
+		},
+		{
+			name: "missing table",
+			rebuild: func(h *Handle) {
+				h.RebuildTable("people", "CREATE TABLE people (id INTEGER PRIMARY KEY)")
+			},
+			want: "rebuildTable: no such table: people",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, h := setupForeignKeys(t, tt.enforce, tt.onDelete)
+
+			func() {
+				defer func() {
+					err, ok := recover().(error)
+					if !ok || err.Error() != tt.want {
+						t.Errorf("panic = %v, want %q", err, tt.want)
+					}
+				}()
+				tt.rebuild(h)
+			}()
+
+			users := h.QueryOne(&SQLQuery{Query: "SELECT COUNT(*) AS count FROM users"})
+			if users["count"] != int64(2) {
+				t.Errorf("users = %v, want the table rolled back", users)
+			}
+		})
+	}
+}
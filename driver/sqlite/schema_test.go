@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/telemetryos/graviton/driver/sqlschema"
+)
+
+func usersTableSpec() map[string]any {
+	return map[string]any{
+		"columns": []any{
+			map[string]any{"name": "id", "type": "increments"},
+			map[string]any{"name": "email", "type": "string", "length": int64(320), "nullable": false, "unique": true},
+			map[string]any{"name": "active", "type": "boolean", "default": true},
+			map[string]any{"name": "created_at", "type": "timestamp", "nullable": false, "defaultNow": true},
+		},
+	}
+}
+
+func Test_Schema_CreateTable(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	h.Schema.CreateTable("users", usersTableSpec())
+	h.Schema.CreateTable("posts", map[string]any{
+		"columns": []any{
+			map[string]any{"name": "id", "type": "increments"},
+			map[string]any{"name": "author_id", "type": "integer", "references": map[string]any{"table": "users", "onDelete": "cascade"}},
+			map[string]any{"name": "title", "type": "string", "default": "it's new"},
+		},
+	})
+	h.Schema.CreateIndex("posts", []any{"author_id", "title"})
+
+	h.Exec(&SQLQuery{Query: "INSERT INTO users (email) VALUES ('ada@example.com')"})
+	h.Exec(&SQLQuery{Query: "INSERT INTO posts (author_id) VALUES (1)"})
+
+	user := h.QueryOne(&SQLQuery{Query: "SELECT * FROM users"})
+	if user["id"] != int64(1) || user["active"] != true {
+		t.Errorf("user = %v, want id 1 and active", user)
+	}
+	if _, ok := user["created_at"].(time.Time); !ok {
+		t.Errorf("created_at = %#v, want a time.Time", user["created_at"])
+	}
+	post := h.QueryOne(&SQLQuery{Query: "SELECT title FROM posts"})
+	if post["title"] != "it's new" {
+		t.Errorf("title = %v, want the default", post["title"])
+	}
+
+	foreignKey := h.QueryOne(&SQLQuery{Query: `SELECT "table", "from", "to", on_delete FROM pragma_foreign_key_list('posts')`})
+	if fmt.Sprint(foreignKey) != "map[from:author_id on_delete:CASCADE table:users to:id]" {
+		t.Errorf("foreign key = %v", foreignKey)
+	}
+	index := h.QueryOne(&SQLQuery{Query: "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'posts'"})
+	if index["name"] != "posts_author_id_title_index" {
+		t.Errorf("index = %v, want posts_author_id_title_index", index)
+	}
+}
+
+func Test_Schema_AlterTable(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	h.Schema.CreateTable("users", usersTableSpec())
+	h.Schema.AddColumn("users", map[string]any{"name": "nickname", "type": "text"})
+	h.Schema.RenameColumn("users", "nickname", "display_name")
+	h.Schema.CreateIndex("users", []any{"display_name"}, map[string]any{"name": "users_display_name", "ifNotExists": true})
+	h.Schema.DropIndex("users", "users_display_name")
+	h.Schema.DropColumn("users", "active")
+	h.Schema.RenameTable("users", "people")
+
+	var columns []string
+	for _, row := range h.Query(&SQLQuery{Query: "SELECT name FROM pragma_table_info('people')"}) {
+		columns = append(columns, row["name"].(string))
+	}
+	if strings.Join(columns, ",") != "id,email,created_at,display_name" {
+		t.Errorf("columns = %v, want id, email, created_at and display_name", columns)
+	}
+
+	h.Schema.DropTable("people")
+	h.Schema.DropTable("people", map[string]any{"ifExists": true})
+	if tables := h.Query(&SQLQuery{Query: "SELECT name FROM sqlite_master WHERE name = 'people'"}); len(tables) != 0 {
+		t.Errorf("tables = %v, want people dropped", tables)
+	}
+}
+
+func Test_Schema_Statements(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{
+			name: "create table",
+			got: func() string {
+				return createTableStatement("main.memberships", sqlschema.DecodeTable("schema.createTable", map[string]any{
+					"columns": []any{
+						map[string]any{"name": "user_id", "type": "bigInteger", "nullable": false},
+						map[string]any{"name": "group_id", "type": "bigInteger", "nullable": false},
+						map[string]any{"name": "role", "type": "string", "default": nil},
+						map[string]any{"name": "score", "type": "decimal", "precision": int64(5), "scale": int64(2), "default": &SQLQuery{Query: "0.5 * 2"}},
+					},
+					"primaryKey":  []any{"user_id", "group_id"},
+					"unique":      []any{[]any{"group_id", "role"}},
+					"ifNotExists": true,
+					"foreignKeys": []any{map[string]any{
+						"columns":    []any{"group_id"},
+						"references": map[string]any{"table": "main.groups"},
+						"onUpdate":   "no action",
+					}},
+				}))
+			},
+			want: `CREATE TABLE IF NOT EXISTS "main"."memberships" (
+  "user_id" INTEGER NOT NULL,
+  "group_id" INTEGER NOT NULL,
+  "role" TEXT DEFAULT NULL,
+  "score" NUMERIC DEFAULT (0.5 * 2),
+  PRIMARY KEY ("user_id", "group_id"),
+  UNIQUE ("group_id", "role"),
+  CONSTRAINT "memberships_group_id_foreign" FOREIGN KEY ("group_id") REFERENCES "groups" ("id") ON UPDATE NO ACTION
+)`,
+		},
+		{
+			name: "add column",
+			got: func() string {
+				return addColumnStatement("posts", sqlschema.DecodeColumn("schema.addColumn", map[string]any{
+					"name": "editor_id", "type": "integer",
+					"references": map[string]any{"table": "users", "name": "posts_editor", "onDelete": "SET NULL"},
+				}))
+			},
+			want: `ALTER TABLE "posts" ADD COLUMN "editor_id" INTEGER CONSTRAINT "posts_editor" REFERENCES "users" ("id") ON DELETE SET NULL`,
+		},
+		{
+			name: "create unique index",
+			got: func() string {
+				return createIndexStatement("main.users", sqlschema.DecodeIndex("schema.createIndex", []any{"email"}, []any{map[string]any{"unique": true}}))
+			},
+			want: `CREATE UNIQUE INDEX "main"."users_email_unique" ON "users" ("email")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); got != tt.want {
+				t.Errorf("statement =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Schema_InvalidSpecs(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+
+	tests := []struct {
+		name string
+		call func()
+		want string
+	}{
+		{
+			name: "unknown column type",
+			call: func() {
+				h.Schema.CreateTable("t", map[string]any{"columns": []any{map[string]any{"name": "a", "type": "money"}}})
+			},
+			want: "schema.createTable: columns[0]: unknown column type `money`",
+		},
+		{
+			name: "unknown option",
+			call: func() {
+				h.Schema.AddColumn("t", map[string]any{"name": "a", "type": "text", "size": int64(1)})
+			},
+			want: "schema.addColumn: unknown option `size`",
+		},
+		{
+			name: "primary key with increments",
+			call: func() {
+				spec := usersTableSpec()
+				spec["primaryKey"] = []any{"id", "email"}
+				h.Schema.CreateTable("t", spec)
+			},
+			want: "schema.createTable: primaryKey cannot be set with the increments column `id`",
+		},
+		{
+			name: "defaultNow on a text column",
+			call: func() {
+				h.Schema.AddColumn("t", map[string]any{"name": "a", "type": "text", "defaultNow": true})
+			},
+			want: "schema.addColumn: defaultNow can only be set on timestamp columns",
+		},
+		{
+			name: "foreign key on an existing table",
+			call: func() {
+				h.Schema.AddForeignKey("t", map[string]any{"columns": []any{"a"}, "references": map[string]any{"table": "users"}})
+			},
+			want: "schema.addForeignKey: SQLite cannot add a foreign key to an existing table; declare it in schema.createTable or use db.rebuildTable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				if !ok || err.Error() != tt.want {
+					t.Errorf("panic = %v, want %q", r, tt.want)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
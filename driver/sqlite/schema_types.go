@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/telemetryos/graviton/schematypes"
+)
+
+// SchemaTypes describes the tables of the main database as the rows
+// migrations read from them, for `graviton types`.
+func (d *Driver) SchemaTypes(ctx context.Context) (schema *schematypes.Schema, returnErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				returnErr = e
+			} else {
+				returnErr = fmt.Errorf("panic in schema types: %v", r)
+			}
+		}
+	}()
+
+	h := newHandle(ctx, d)
+	schema = &schematypes.Schema{Namespace: "Tables"}
+	for _, table := range h.ListTables() {
+		t := &schematypes.Type{Name: table}
+		for _, column := range h.ListColumns(table) {
+			fieldType := columnTypeScriptType(column.Type)
+			if column.Nullable {
+				fieldType = schematypes.Nullable(fieldType)
+			}
+			t.Fields = append(t.Fields, &schematypes.Field{Name: column.Name, Type: fieldType})
+		}
+		schema.Types = append(schema.Types, t)
+	}
+	return schema, nil
+}
+
+// columnTypeScriptType returns the TypeScript type of the values decodeValue
+// reads from a column with the given declared type, following SQLite's rules
+// for a column's affinity. Integers are 64-bit, and those outside the safe
+// range are read as strings.
+func columnTypeScriptType(declaredType string) string {
+	typeName := strings.ToUpper(declaredType)
+	switch {
+	case strings.HasPrefix(typeName, "BOOL"):
+		return "boolean"
+	case strings.Contains(typeName, "DATE"), strings.Contains(typeName, "TIMESTAMP"):
+		return "Date"
+	case typeName == "JSON":
+		return "any"
+	case strings.Contains(typeName, "INT"):
+		return "number | string"
+	case strings.Contains(typeName, "CHAR"), strings.Contains(typeName, "CLOB"), strings.Contains(typeName, "TEXT"):
+		return "string"
+	case strings.Contains(typeName, "BLOB"):
+		return "Uint8Array"
+	case typeName == "":
+		return "any"
+	case strings.Contains(typeName, "REAL"), strings.Contains(typeName, "FLOA"), strings.Contains(typeName, "DOUB"):
+		return "number"
+	default:
+		return "number | string"
+	}
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/telemetryos/graviton/schematypes"
+)
+
+func Test_Driver_SchemaTypes(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+	h := drv.Handle(ctx).(*Handle)
+	h.ExecScript(`
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			email VARCHAR(320) NOT NULL,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			settings JSON,
+			created_at DATETIME NOT NULL,
+			avatar BLOB,
+			score REAL
+		);
+		CREATE TABLE anything (value);
+	`)
+
+	schema, err := drv.SchemaTypes(ctx)
+	if err != nil {
+		t.Fatalf("SchemaTypes() error = %v", err)
+	}
+
+	want := &schematypes.Schema{
+		Namespace: "Tables",
+		Types: []*schematypes.Type{
+			{Name: "anything", Fields: []*schematypes.Field{
+				{Name: "value", Type: "any"},
+			}},
+			{Name: "users", Fields: []*schematypes.Field{
+				{Name: "id", Type: "number | string"},
+				{Name: "email", Type: "string"},
+				{Name: "active", Type: "boolean"},
+				{Name: "settings", Type: "any"},
+				{Name: "created_at", Type: "Date"},
+				{Name: "avatar", Type: "Uint8Array | null"},
+				{Name: "score", Type: "number | null"},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("SchemaTypes() = %+v, want %+v", schema, want)
+	}
+}
@@ -0,0 +1,3 @@
+SELECT checkpoint
+FROM {{.TableName}}
+WHERE filename = ?;
@@ -0,0 +1,2 @@
+INSERT INTO {{.TableName}} (filename, checkpoint, updated_at)
+VALUES (?, ?, ?);
@@ -0,0 +1,8 @@
+package sqlite
+
+import (
+	_ "embed"
+)
+
+//go:embed migration.d.ts
+var MigrationTypeDefTemplate []byte
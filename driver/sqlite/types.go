@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MAX_SAFE_INTEGER is the largest integer a JavaScript number holds exactly.
+// The migration runtime has no BigInt, so larger integers are returned as
+// strings rather than silently rounded.
+const MAX_SAFE_INTEGER = 1<<53 - 1
+
+var timeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// decodeValue converts a value scanned from a column with the given declared
+// type, as reported by ColumnType.DatabaseTypeName, into the value migrations
+// see. SQLite stores values by storage class rather than column type, so the
+// declared type is only used where the stored value can be read as it.
+//
+//   - integers become numbers, or strings outside the safe integer range
+//   - integers in BOOLEAN columns become booleans
+//   - text in DATE, DATETIME and TIMESTAMP columns becomes a Date when it is in
+//     one of SQLite's date and time formats
+//   - text in JSON columns is parsed when it is valid JSON
+//   - blobs become Uint8Arrays
+func decodeValue(typeName string, value any) (any, error) {
+	switch v := value.(type) {
+	case int64:
+		if strings.HasPrefix(typeName, "BOOL") {
+			return v != 0, nil
+		}
+		return safeInteger(v), nil
+	case string:
+		switch {
+		case strings.Contains(typeName, "DATE"), strings.Contains(typeName, "TIMESTAMP"):
+			if t, ok := parseTime(v); ok {
+				return t, nil
+			}
+		case typeName == "JSON":
+			var parsed any
+			if err := json.Unmarshal([]byte(v), &parsed); err == nil {
+				return parsed, nil
+			}
+		}
+	}
+	return value, nil
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func safeInteger(n int64) any {
+	if n > MAX_SAFE_INTEGER || n < -MAX_SAFE_INTEGER {
+		return strconv.FormatInt(n, 10)
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_Handle_Query_DecodesColumnTypes(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	_, err := drv.db.ExecContext(ctx, `CREATE TABLE typed (
+		id INTEGER,
+		big INTEGER,
+		ratio REAL,
+		active BOOLEAN,
+		created_at DATETIME,
+		updated_at TIMESTAMPTZ,
+		data JSON,
+		notes JSON,
+		payload BLOB,
+		name TEXT
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	_, err = drv.db.ExecContext(ctx,
+		"INSERT INTO typed VALUES (1, 9007199254740993, 0.5, 1, '2024-03-01 10:30:00', '2024-03-01T10:30:00.5Z', '{\"tags\":[\"a\"]}', 'not json', X'0102', 'Ada')",
+	)
+	if err != nil {
+		t.Fatalf("Failed to insert row: %v", err)
+	}
+
+	row := drv.Handle(ctx).(*Handle).QueryOne(&SQLQuery{Query: "SELECT * FROM typed"})
+
+	expected := map[string]any{
+		"id":         int64(1),
+		"big":        "9007199254740993",
+		"ratio":      0.5,
+		"active":     true,
+		"created_at": time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+		"updated_at": time.Date(2024, 3, 1, 10, 30, 0, 500000000, time.UTC),
+		"data":       map[string]any{"tags": []any{"a"}},
+		"notes":      "not json",
+		"payload":    []byte{1, 2},
+		"name":       "Ada",
+	}
+	for column, want := range expected {
+		got := row[column]
+		if gotTime, ok := got.(time.Time); ok {
+			if !gotTime.Equal(want.(time.Time)) {
+				t.Errorf("%s = %v, want %v", column, got, want)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v, want %#v", column, got, want)
+		}
+	}
+}
+
+func Test_Handle_Query_DecodesExpressions(t *testing.T) {
+	drv, ctx := setupTestDriver(t)
+
+	row := drv.Handle(ctx).(*Handle).QueryOne(&SQLQuery{Query: "SELECT 1 + 1 AS sum, -9007199254740993 AS small, NULL AS missing"})
+
+	if row["sum"] != int64(2) {
+		t.Errorf("sum = %#v, want 2", row["sum"])
+	}
+	if row["small"] != "-9007199254740993" {
+		t.Errorf("small = %#v, want \"-9007199254740993\"", row["small"])
+	}
+	if value, ok := row["missing"]; !ok || value != nil {
+		t.Errorf("missing = %#v, want nil", value)
+	}
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// syntaxErrorMessages are the parts of SQLite error messages that mean a
+// statement could not be parsed. SQLite reports all of its errors with the
+// same code, so they are told apart by message.
+var syntaxErrorMessages = []string{"syntax error", "incomplete input", "unrecognized token"}
+
+// ValidateSQL checks the syntax of a statement by preparing it in an empty
+// in-memory database, so it never touches the migrated database. Only syntax
+// errors are reported, as every table is missing from the empty database.
+// SQLite only prepares the first of several statements, so each is checked on
+// its own.
+func (d *Driver) ValidateSQL(ctx context.Context, statement string) error {
+	if d.validationDB == nil {
+		db, err := sql.Open("sqlite", ":memory:")
+		if err != nil {
+			return nil
+		}
+		d.validationDB = db
+	}
+	for _, statement := range splitStatements(statement) {
+		if err := d.validateStatement(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) validateStatement(ctx context.Context, statement string) error {
+	stmt, err := d.validationDB.PrepareContext(ctx, statement)
+	if err == nil {
+		return stmt.Close()
+	}
+
+	for _, message := range syntaxErrorMessages {
+		if strings.Contains(err.Error(), message) {
+			message := strings.TrimPrefix(err.Error(), "SQL logic error: ")
+			return errors.New(strings.TrimSuffix(message, " (1)"))
+		}
+	}
+	return nil
+}
+
+// StrictSQLValidation reports whether invalid SQL fails a migration when it is
+// compiled, rather than printing a warning.
+func (d *Driver) StrictSQLValidation() bool {
+	return d.config.StrictSQLValidation
+}
+
+func (d *Driver) Placeholder(index int) string {
+	return "?"
+}
+
+func (d *Driver) QuoteIdentifier(names ...string) string {
+	return quoteIdentifier(names)
+}
+
+func quoteIdentifier(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, ".")
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/telemetryos/graviton/config"
+)
+
+func Test_Driver_ValidateSQL(t *testing.T) {
+	drv := New(&config.DatabaseConfig{})
+	ctx := context.Background()
+	t.Cleanup(func() { drv.Disconnect(ctx) })
+
+	valid := []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT NOT NULL) STRICT",
+		"INSERT INTO users (id, email) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET email = excluded.email RETURNING id",
+		"SELECT * FROM missing WHERE id = ?",
+		`SELECT "full name" FROM "main"."users"`,
+		"CREATE TABLE a (id INTEGER); INSERT INTO a VALUES (';')",
+	}
+	for _, statement := range valid {
+		if err := drv.ValidateSQL(ctx, statement); err != nil {
+			t.Errorf("ValidateSQL(%q) error = %v, want nil", statement, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"CREATE TABL users (id INTEGER)":                          `near "TABL": syntax error`,
+		"SELECT * FROM users WHERE":                               "incomplete input",
+		"SELECT 'unterminated FROM users":                         "unrecognized token",
+		"CREATE TABLE a (id INTEGER); CREATE TABL b (id INTEGER)": `near "TABL": syntax error`,
+	}
+	for statement, expected := range invalid {
+		err := drv.ValidateSQL(ctx, statement)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("ValidateSQL(%q) error = %v, want %q", statement, err, expected)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+// Package sqlschema decodes the specs passed to db.schema in SQL migrations.
+// The SQL drivers share the decoded tables, columns, indexes and foreign keys,
+// and each renders them as DDL for its database.
+package sqlschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// columnTypes are the portable column types a schema column can have. Each
+// driver maps them to its own types.
+var columnTypes = []string{
+	"increments", "bigIncrements", "integer", "bigInteger", "smallInteger",
+	"float", "double", "decimal", "boolean", "string", "text", "uuid", "date",
+	"timestamp", "json", "binary",
+}
+
+// referentialActions are the ON DELETE and ON UPDATE actions a foreign key can
+// have.
+var referentialActions = []string{"CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT", "NO ACTION"}
+
+// Column is a column of a table, as given to schema.createTable or
+// schema.addColumn.
+type Column struct {
+	Name string
+	Type string
+	// Length is the most characters of a string column.
+	Length int64
+	// Precision and Scale are the digits of a decimal column.
+	Precision  int64
+	Scale      int64
+	Nullable   bool
+	Unique     bool
+	PrimaryKey bool
+	HasDefault bool
+	// Default is a literal, or the driver's *SQLQuery whose SQL is the
+	// default expression.
+	Default    any
+	DefaultNow bool
+	References *ForeignKey
+}
+
+// ForeignKey is a foreign key constraint, declared by a column or on its own.
+type ForeignKey struct {
+	Name              string
+	Columns           []string
+	Table             string
+	ReferencedColumns []string
+	OnDelete          string
+	OnUpdate          string
+}
+
+// Table is the definition of a table given to schema.createTable.
+type Table struct {
+	Columns     []*Column
+	PrimaryKey  []string
+	Unique      [][]string
+	ForeignKeys []*ForeignKey
+	IfNotExists bool
+}
+
+// Index is an index given to schema.createIndex.
+type Index struct {
+	Columns     []string
+	Name        string
+	Unique      bool
+	IfNotExists bool
+}
+
+// DecodeTable decodes the definition of a table. operation names the schema
+// method in the errors it panics with.
+func DecodeTable(operation string, value any) *Table {
+	fields := decodeSpec(operation, value, "columns", "primaryKey", "unique", "foreignKeys", "ifNotExists")
+	spec := &Table{
+		PrimaryKey:  specStrings(operation, fields, "primaryKey", false),
+		IfNotExists: Bool(operation, fields, "ifNotExists"),
+	}
+
+	columns := specList(operation, fields, "columns")
+	if len(columns) == 0 {
+		panic(fmt.Errorf("%s: columns must not be empty", operation))
+	}
+	for i, column := range columns {
+		spec.Columns = append(spec.Columns, DecodeColumn(fmt.Sprintf("%s: columns[%d]", operation, i), column))
+	}
+	for i, unique := range specList(operation, fields, "unique") {
+		spec.Unique = append(spec.Unique, decodeStrings(fmt.Sprintf("%s: unique[%d]", operation, i), unique))
+	}
+	for i, foreignKey := range specList(operation, fields, "foreignKeys") {
+		spec.ForeignKeys = append(spec.ForeignKeys, DecodeForeignKey(fmt.Sprintf("%s: foreignKeys[%d]", operation, i), foreignKey))
+	}
+
+	if len(spec.PrimaryKey) > 0 {
+		for _, column := range spec.Columns {
+			if column.IsIncrements() {
+				panic(fmt.Errorf("%s: primaryKey cannot be set with the %s column `%s`", operation, column.Type, column.Name))
+			}
+		}
+	}
+	return spec
+}
+
+// DecodeColumn decodes a column. Columns are nullable unless nullable is
+// false.
+func DecodeColumn(context string, value any) *Column {
+	fields := decodeSpec(context, value,
+		"name", "type", "length", "precision", "scale", "nullable", "unique",
+		"primaryKey", "default", "defaultNow", "references")
+	column := &Column{
+		Name:       specString(context, fields, "name", true),
+		Type:       specString(context, fields, "type", true),
+		Length:     specInt(context, fields, "length"),
+		Precision:  specInt(context, fields, "precision"),
+		Scale:      specInt(context, fields, "scale"),
+		Nullable:   true,
+		Unique:     Bool(context, fields, "unique"),
+		PrimaryKey: Bool(context, fields, "primaryKey"),
+		DefaultNow: Bool(context, fields, "defaultNow"),
+	}
+	if !slices.Contains(columnTypes, column.Type) {
+		panic(fmt.Errorf("%s: unknown column type `%s`", context, column.Type))
+	}
+	if _, ok := fields["nullable"]; ok {
+		column.Nullable = Bool(context, fields, "nullable")
+	}
+	column.Default, column.HasDefault = fields["default"]
+	if column.HasDefault && column.DefaultNow {
+		panic(fmt.Errorf("%s: default and defaultNow cannot both be set", context))
+	}
+	if column.DefaultNow && column.Type != "timestamp" {
+		panic(fmt.Errorf("%s: defaultNow can only be set on timestamp columns", context))
+	}
+	if references, ok := fields["references"]; ok {
+		column.References = decodeReference(context+" references", column.Name, references)
+	}
+	return column
+}
+
+// decodeReference decodes the foreign key declared by a column, which
+// references the id column unless another is given.
+func decodeReference(context string, column string, value any) *ForeignKey {
+	fields := decodeSpec(context, value, "table", "column", "name", "onDelete", "onUpdate")
+	referencedColumn := specString(context, fields, "column", false)
+	if referencedColumn == "" {
+		referencedColumn = "id"
+	}
+	return &ForeignKey{
+		Name:              specString(context, fields, "name", false),
+		Columns:           []string{column},
+		Table:             specString(context, fields, "table", true),
+		ReferencedColumns: []string{referencedColumn},
+		OnDelete:          specAction(context, fields, "onDelete"),
+		OnUpdate:          specAction(context, fields, "onUpdate"),
+	}
+}
+
+// DecodeForeignKey decodes a foreign key declared on its own, which
+// references the id column unless other columns are given.
+func DecodeForeignKey(context string, value any) *ForeignKey {
+	fields := decodeSpec(context, value, "columns", "references", "name", "onDelete", "onUpdate")
+	referencesContext := context + " references"
+	references := decodeSpec(referencesContext, fields["references"], "table", "columns")
+	foreignKey := &ForeignKey{
+		Name:              specString(context, fields, "name", false),
+		Columns:           specStrings(context, fields, "columns", true),
+		Table:             specString(referencesContext, references, "table", true),
+		ReferencedColumns: specStrings(referencesContext, references, "columns", false),
+		OnDelete:          specAction(context, fields, "onDelete"),
+		OnUpdate:          specAction(context, fields, "onUpdate"),
+	}
+	if len(foreignKey.ReferencedColumns) == 0 {
+		foreignKey.ReferencedColumns = []string{"id"}
+	}
+	if len(foreignKey.ReferencedColumns) != len(foreignKey.Columns) {
+		panic(fmt.Errorf("%s: references.columns must have as many columns as columns", context))
+	}
+	return foreignKey
+}
+
+// DecodeIndex decodes the columns and options of an index.
+func DecodeIndex(operation string, columns []any, opts []any) *Index {
+	fields := DecodeOptions(operation, opts, "name", "unique", "ifNotExists")
+	spec := &Index{
+		Columns:     decodeStrings(operation+": columns", columns),
+		Name:        specString(operation, fields, "name", false),
+		Unique:      Bool(operation, fields, "unique"),
+		IfNotExists: Bool(operation, fields, "ifNotExists"),
+	}
+	if len(spec.Columns) == 0 {
+		panic(fmt.Errorf("%s: columns must not be empty", operation))
+	}
+	return spec
+}
+
+// decodeSpec returns the fields of an object passed to a schema method. It
+// panics when the value is not an object or has keys other than the given
+// ones.
+func decodeSpec(context string, value any, keys ...string) map[string]any {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		panic(fmt.Errorf("%s must be an object", context))
+	}
+	for key := range fields {
+		if !slices.Contains(keys, key) {
+			panic(fmt.Errorf("%s: unknown option `%s`", context, key))
+		}
+	}
+	return fields
+}
+
+// DecodeOptions merges the optional options objects passed to a schema
+// method.
+func DecodeOptions(operation string, opts []any, keys ...string) map[string]any {
+	fields := map[string]any{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		for key, value := range decodeSpec(operation+" options", opt, keys...) {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+func specString(context string, fields map[string]any, key string, required bool) string {
+	value, ok := fields[key]
+	if !ok || value == nil {
+		if required {
+			panic(fmt.Errorf("%s: %s is required", context, key))
+		}
+		return ""
+	}
+	str, ok := value.(string)
+	if !ok || str == "" {
+		panic(fmt.Errorf("%s: %s must be a non-empty string", context, key))
+	}
+	return str
+}
+
+func specInt(context string, fields map[string]any, key string) int64 {
+	value, ok := fields[key]
+	if !ok || value == nil {
+		return 0
+	}
+	integer, ok := value.(int64)
+	if !ok || integer < 0 {
+		panic(fmt.Errorf("%s: %s must be a non-negative integer", context, key))
+	}
+	return integer
+}
+
+// Bool returns a boolean field, which is false when it is missing.
+func Bool(context string, fields map[string]any, key string) bool {
+	value, ok := fields[key]
+	if !ok || value == nil {
+		return false
+	}
+	boolean, ok := value.(bool)
+	if !ok {
+		panic(fmt.Errorf("%s: %s must be a boolean", context, key))
+	}
+	return boolean
+}
+
+func specList(context string, fields map[string]any, key string) []any {
+	value, ok := fields[key]
+	if !ok || value == nil {
+		return nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		panic(fmt.Errorf("%s: %s must be an array", context, key))
+	}
+	return list
+}
+
+func specStrings(context string, fields map[string]any, key string, required bool) []string {
+	strs := decodeStrings(context+": "+key, specList(context, fields, key))
+	if required && len(strs) == 0 {
+		panic(fmt.Errorf("%s: %s must not be empty", context, key))
+	}
+	return strs
+}
+
+func decodeStrings(context string, value any) []string {
+	if value == nil {
+		return nil
+	}
+	list, ok := value.([]any)
+	if !ok {
+		panic(fmt.Errorf("%s must be an array of strings", context))
+	}
+	strs := make([]string, len(list))
+	for i, item := range list {
+		str, ok := item.(string)
+		if !ok || str == "" {
+			panic(fmt.Errorf("%s must be an array of strings", context))
+		}
+		strs[i] = str
+	}
+	return strs
+}
+
+// specAction returns the ON DELETE or ON UPDATE action of a foreign key, in
+// upper case.
+func specAction(context string, fields map[string]any, key string) string {
+	action := strings.ToUpper(specString(context, fields, key, false))
+	if action != "" && !slices.Contains(referentialActions, action) {
+		panic(fmt.Errorf("%s: %s must be one of %s", context, key, strings.Join(referentialActions, ", ")))
+	}
+	return action
+}
+
+// IsIncrements reports whether the column is an auto-incrementing primary
+// key.
+func (c *Column) IsIncrements() bool {
+	return c.Type == "increments" || c.Type == "bigIncrements"
+}
+
+// SplitTableName splits a table name such as "main.users" into its schema,
+// which is empty when none is given, and its name.
+func SplitTableName(table string) (string, string) {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[:i], table[i+1:]
+	}
+	return "", table
+}
+
+// ObjectName returns the name given to a constraint or index, or else one made
+// of the table name, its columns and a suffix, such as users_email_unique.
+func ObjectName(name string, table string, columns []string, suffix string) string {
+	if name != "" {
+		return name
+	}
+	_, tableName := SplitTableName(table)
+	return strings.Join(append(append([]string{tableName}, columns...), suffix), "_")
+}
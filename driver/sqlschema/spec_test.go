@@ -0,0 +1,98 @@
+package sqlschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_DecodeTable(t *testing.T) {
+	table := DecodeTable("schema.createTable", map[string]any{
+		"columns": []any{
+			map[string]any{"name": "id", "type": "increments"},
+			map[string]any{"name": "author_id", "type": "integer", "nullable": false, "references": map[string]any{"table": "users", "onDelete": "cascade"}},
+			map[string]any{"name": "title", "type": "string", "length": int64(100), "default": nil},
+		},
+		"unique":      []any{[]any{"author_id", "title"}},
+		"ifNotExists": true,
+	})
+
+	if len(table.Columns) != 3 || !table.IfNotExists || !reflect.DeepEqual(table.Unique, [][]string{{"author_id", "title"}}) {
+		t.Fatalf("DecodeTable() = %+v", table)
+	}
+	if id := table.Columns[0]; !id.IsIncrements() || !id.Nullable {
+		t.Errorf("id = %+v, want an increments column", id)
+	}
+	want := &ForeignKey{Columns: []string{"author_id"}, Table: "users", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE"}
+	if authorID := table.Columns[1]; authorID.Nullable || !reflect.DeepEqual(authorID.References, want) {
+		t.Errorf("author_id = %+v, want a non-null column referencing users.id", authorID)
+	}
+	if title := table.Columns[2]; title.Length != 100 || !title.HasDefault || title.Default != nil {
+		t.Errorf("title = %+v, want length 100 and a null default", title)
+	}
+}
+
+func Test_Decode_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func()
+		want   string
+	}{
+		{
+			name: "empty columns",
+			decode: func() {
+				DecodeTable("schema.createTable", map[string]any{"columns": []any{}})
+			},
+			want: "schema.createTable: columns must not be empty",
+		},
+		{
+			name: "default and defaultNow",
+			decode: func() {
+				DecodeColumn("schema.addColumn", map[string]any{"name": "a", "type": "timestamp", "default": "now", "defaultNow": true})
+			},
+			want: "schema.addColumn: default and defaultNow cannot both be set",
+		},
+		{
+			name: "unknown action",
+			decode: func() {
+				DecodeForeignKey("schema.addForeignKey", map[string]any{"columns": []any{"a"}, "references": map[string]any{"table": "t"}, "onDelete": "drop"})
+			},
+			want: "schema.addForeignKey: onDelete must be one of CASCADE, SET NULL, SET DEFAULT, RESTRICT, NO ACTION",
+		},
+		{
+			name: "mismatched referenced columns",
+			decode: func() {
+				DecodeForeignKey("schema.addForeignKey", map[string]any{"columns": []any{"a", "b"}, "references": map[string]any{"table": "t"}})
+			},
+			want: "schema.addForeignKey: references.columns must have as many columns as columns",
+		},
+		{
+			name: "unknown index option",
+			decode: func() {
+				DecodeIndex("schema.createIndex", []any{"a"}, []any{map[string]any{"where": "a > 0"}})
+			},
+			want: "schema.createIndex options: unknown option `where`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				if !ok || err.Error() != tt.want {
+					t.Errorf("panic = %v, want %q", r, tt.want)
+				}
+			}()
+			tt.decode()
+		})
+	}
+}
+
+func Test_ObjectName(t *testing.T) {
+	if got := ObjectName("", "app.users", []string{"last_name", "first_name"}, "index"); got != "users_last_name_first_name_index" {
+		t.Errorf("ObjectName() = %q, want users_last_name_first_name_index", got)
+	}
+	if got := ObjectName("users_name", "app.users", []string{"name"}, "index"); got != "users_name" {
+		t.Errorf("ObjectName() = %q, want the given name", got)
+	}
+}
@@ -8,14 +8,24 @@ import (
 // 000000000000-test.migration.ts
 var MigrationNamePattern = regexp.MustCompile(`^\d{14}-([a-zA-Z-_]+)\.migration\.ts$`)
 
+// 000000000000-test.seed.ts
+var SeedNamePattern = regexp.MustCompile(`^\d{14}-([a-zA-Z-_]+)\.seed\.ts$`)
+
 type MigrationMetadata struct {
 	Filename  string    `bson:"filename"`
 	Source    string    `bson:"source"`
 	AppliedAt time.Time `bson:"applied_at"`
+
+	// NonAtomic is set when the migration was applied without a transaction,
+	// so a failure part way through could have left it partially applied.
+	NonAtomic bool `bson:"non_atomic,omitempty"`
 }
 
 func (m *MigrationMetadata) Name() string {
 	matches := MigrationNamePattern.FindStringSubmatch(m.Filename)
+	if len(matches) < 2 {
+		matches = SeedNamePattern.FindStringSubmatch(m.Filename)
+	}
 	if len(matches) < 2 {
 		return ""
 	}
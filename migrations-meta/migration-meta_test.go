@@ -15,6 +15,7 @@ func Test_MigrationMetadata_Name(t *testing.T) {
 		{"20231225011003-three.migration.ts", "three"},
 		{"12345678901234-test-migration.migration.ts", "test-migration"},
 		{"00000000000000-test_underscore.migration.ts", "test_underscore"},
+		{"20231225010950-users.seed.ts", "users"},
 	}
 
 	for _, tt := range tests {
@@ -43,6 +44,7 @@ func Test_MigrationMetadata_Name_InvalidFilename(t *testing.T) {
 		{"wrong extension", "20231225010950-one.migration.js"},
 		{"no dash", "20231225010950one.migration.ts"},
 		{"empty filename", ""},
+		{"seed without timestamp", "users.seed.ts"},
 	}
 
 	for _, tt := range tests {
@@ -85,3 +87,26 @@ func Test_MigrationNamePattern(t *testing.T) {
 		})
 	}
 }
+
+func Test_SeedNamePattern(t *testing.T) {
+	tests := []struct {
+		filename string
+		matches  bool
+	}{
+		{"20231225010950-users.seed.ts", true},
+		{"12345678901234-demo-data.seed.ts", true},
+		{"20231225010950-users.migration.ts", false},
+		{"users.seed.ts", false},
+		{"20231225010950-users.seed.js", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			matches := SeedNamePattern.MatchString(tt.filename)
+			if matches != tt.matches {
+				t.Errorf("Pattern match for %q = %v, want %v", tt.filename, matches, tt.matches)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const DEFAULT_BACKFILL_BATCH_SIZE = 1000
+
+type BackfillOptions struct {
+	BatchSize int
+	Throttle  time.Duration
+}
+
+// HasBackfill reports whether the migration exports a backfill. A backfill is
+// an object with a batch function, and optionally batchSize and throttleMs.
+func (s *Script) HasBackfill() bool {
+	_, ok := goja.AssertFunction(s.backfill().Get("batch"))
+	return ok
+}
+
+func (s *Script) BackfillOptions() BackfillOptions {
+	backfill := s.backfill()
+	options := BackfillOptions{BatchSize: DEFAULT_BACKFILL_BATCH_SIZE}
+	if batchSizeVal := backfill.Get("batchSize"); batchSizeVal != nil && !goja.IsUndefined(batchSizeVal) && batchSizeVal.ToInteger() > 0 {
+		options.BatchSize = int(batchSizeVal.ToInteger())
+	}
+	if throttleVal := backfill.Get("throttleMs"); throttleVal != nil && !goja.IsUndefined(throttleVal) {
+		options.Throttle = time.Duration(throttleVal.ToFloat() * float64(time.Millisecond))
+	}
+	return options
+}
+
+// BackfillBatch runs one batch of the migration's backfill. The batch function
+// is given the checkpoint returned by the previous batch, or null for the first
+// batch, and returns the checkpoint to continue from. The handles of the other
+// databases the migration uses are passed last. Checkpoints are stored as
+// JSON. done is true when the batch function returns null or undefined.
+func (s *Script) BackfillBatch(ctx context.Context, checkpoint string, batchSize int) (next string, done bool, err error) {
+	batch, ok := goja.AssertFunction(s.backfill().Get("batch"))
+	if !ok {
+		return "", false, errors.New("migration does not export a backfill")
+	}
+
+	s.ctx = ctx
+	s.handle = s.driver.Handle(ctx)
+
+	checkpointVal := goja.Null()
+	if checkpoint != "" {
+		if checkpointVal, err = s.callJSON("parse", s.runtime.ToValue(checkpoint)); err != nil {
+			return "", false, err
+		}
+	}
+
+	nextVal, err := batch(goja.Undefined(), s.intoJs(reflect.ValueOf(s.handle)), checkpointVal, s.runtime.ToValue(batchSize), s.databaseHandles())
+	if err != nil {
+		return "", false, scriptError(err)
+	}
+	if goja.IsUndefined(nextVal) || goja.IsNull(nextVal) {
+		return "", true, nil
+	}
+
+	nextJSONVal, err := s.callJSON("stringify", nextVal)
+	if err != nil {
+		return "", false, err
+	}
+	next = nextJSONVal.String()
+	if next == checkpoint {
+		return "", false, errors.New("backfill batch returned the checkpoint it was given, it would never finish")
+	}
+	return next, false, nil
+}
+
+func (s *Script) backfill() *goja.Object {
+	migrationVal := s.runtime.Get("migration")
+	if migrationVal == nil || goja.IsUndefined(migrationVal) || goja.IsNull(migrationVal) {
+		return s.runtime.NewObject()
+	}
+	backfillVal := migrationVal.ToObject(s.runtime).Get("backfill")
+	if backfillVal == nil || goja.IsUndefined(backfillVal) || goja.IsNull(backfillVal) {
+		return s.runtime.NewObject()
+	}
+	return backfillVal.ToObject(s.runtime)
+}
+
+func (s *Script) callJSON(method string, arg goja.Value) (goja.Value, error) {
+	fn, _ := goja.AssertFunction(s.runtime.Get("JSON").ToObject(s.runtime).Get(method))
+	return fn(goja.Undefined(), arg)
+}
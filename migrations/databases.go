@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/telemetryos/graviton/driver"
+
+	"github.com/dop251/goja"
+)
+
+// Database is another configured database a migration uses. Migrations
+// declare the databases they use by name with `export const databases`, and
+// are given a handle for each alongside their own.
+type Database struct {
+	Name   string
+	Driver driver.Driver
+
+	// ctx is the context the database's handle is bound to, which carries its
+	// transaction while the script runs in WithTransaction.
+	ctx context.Context
+	// globals are the globals of the database's driver, such as its sql tag,
+	// which are set on its handle.
+	globals map[string]goja.Value
+}
+
+// CommitError is returned by WithTransaction when some of the databases a
+// migration uses committed its changes and another then failed to. The
+// databases in Committed keep the changes, and the rest were rolled back, so
+// the committed changes have to be undone by hand before the migration is run
+// again. Database is the database that failed to commit, or empty for the
+// migration's own.
+type CommitError struct {
+	Database  string
+	Committed []string
+	Err       error
+}
+
+func (e *CommitError) Error() string {
+	database := "the migration's own database"
+	if e.Database != "" {
+		database = "database `" + e.Database + "`"
+	}
+	return fmt.Sprintf("%s failed to commit after `%s` committed, whose changes are kept and must be reverted by hand: %v",
+		database, strings.Join(e.Committed, "`, `"), e.Err)
+}
+
+func (e *CommitError) Unwrap() error {
+	return e.Err
+}
+
+// Databases returns the names of the other databases the script declares it
+// uses, in order. An empty list means it only uses its own.
+func (s *Script) Databases() []string {
+	databasesVal, err := s.runtime.RunString("migration.databases")
+	if err != nil || goja.IsUndefined(databasesVal) || goja.IsNull(databasesVal) {
+		return nil
+	}
+
+	var databases []string
+	if err := s.runtime.ExportTo(databasesVal, &databases); err != nil {
+		return nil
+	}
+	return databases
+}
+
+// UseDatabases gives the script the connected drivers of the other databases
+// it uses, in the order they are declared, and evaluates it again with their
+// globals. Outside WithTransaction their handles are bound to ctx.
+func (s *Script) UseDatabases(ctx context.Context, databases []*Database) {
+	for _, database := range databases {
+		database.ctx = ctx
+	}
+	s.databases = databases
+	s.Evaluate()
+}
+
+// WithTransaction runs fn in a transaction of the script's database, with a
+// transaction open in each of the other databases it uses for their handles.
+// Nothing commits until fn, and the recording of the migration it does, has
+// succeeded everywhere. The other databases then commit first, in the reverse
+// of the order they are declared, and the script's own database, whose
+// tracking table records the migration, commits last. A database failing to
+// commit rolls back those yet to, and is reported with a *CommitError when
+// others already had.
+func (s *Script) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if len(s.databases) == 0 {
+		return s.driver.WithTransaction(ctx, fn)
+	}
+
+	var committed []string
+	var failed string
+	err := s.driver.WithTransaction(ctx, func(txCtx context.Context) error {
+		if len(committed) > 0 {
+			return fmt.Errorf("the transaction was retried after `%s` committed", strings.Join(committed, "`, `"))
+		}
+		return s.withDatabaseTransactions(ctx, s.databases, &committed, &failed, func() error {
+			return fn(txCtx)
+		})
+	})
+	for _, database := range s.databases {
+		database.ctx = ctx
+	}
+
+	if err != nil && len(committed) > 0 {
+		return &CommitError{Database: failed, Committed: committed, Err: err}
+	}
+	return err
+}
+
+// withDatabaseTransactions runs fn with a transaction open in each of the
+// databases, nested so the last commits first. The databases that commit are
+// added to committed, and the one that fails to commit after fn succeeded is
+// set in failed.
+func (s *Script) withDatabaseTransactions(ctx context.Context, databases []*Database, committed *[]string, failed *string, fn func() error) error {
+	if len(databases) == 0 {
+		return fn()
+	}
+
+	database := databases[0]
+	succeeded := false
+	err := database.Driver.WithTransaction(ctx, func(txCtx context.Context) error {
+		if len(*committed) > 0 {
+			return fmt.Errorf("the transaction of database `%s` was retried after `%s` committed", database.Name, strings.Join(*committed, "`, `"))
+		}
+		database.ctx = txCtx
+		if err := s.withDatabaseTransactions(ctx, databases[1:], committed, failed, fn); err != nil {
+			return err
+		}
+		succeeded = true
+		return nil
+	})
+	if err != nil {
+		if succeeded && *failed == "" {
+			*failed = database.Name
+		}
+		return err
+	}
+	*committed = append(*committed, database.Name)
+	return nil
+}
+
+// databaseHandles returns an object with the handle of each of the other
+// databases the script uses by name. Values passed to and returned from a
+// handle are converted by its own driver, and the handle carries its driver's
+// globals, so databases.analytics.sql builds queries for that database.
+func (s *Script) databaseHandles() *goja.Object {
+	handles := s.runtime.NewObject()
+	for _, database := range s.databases {
+		handle := s.intoJsWith(database.Driver, reflect.ValueOf(database.Driver.Handle(database.ctx))).ToObject(s.runtime)
+		for name, value := range database.globals {
+			handle.Set(name, value)
+		}
+		handles.Set(database.Name, handle)
+	}
+	return handles
+}
+
+// drivers returns the driver of the script's database followed by those of the
+// other databases it uses.
+func (s *Script) drivers() []driver.Driver {
+	drivers := []driver.Driver{s.driver}
+	for _, database := range s.databases {
+		drivers = append(drivers, database.Driver)
+	}
+	return drivers
+}
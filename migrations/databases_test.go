@@ -0,0 +1,161 @@
+package migrations
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/driver"
+)
+
+func setupTestDatabase(t *testing.T, ctx context.Context, name string) *Database {
+	t.Helper()
+
+	drv, err := driver.FromDatabaseConfig(&config.DatabaseConfig{
+		Kind:          config.DatabaseKindSQLite,
+		ConnectionUrl: "file:" + filepath.Join(t.TempDir(), name+".db"),
+	})
+	if err != nil {
+		t.Fatalf("FromDatabaseConfig() error = %v", err)
+	}
+	if err := drv.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { drv.Disconnect(ctx) })
+
+	return &Database{Name: name, Driver: drv}
+}
+
+func countRows(t *testing.T, ctx context.Context, drv driver.Driver, table string) any {
+	t.Helper()
+
+	script := NewScript(ctx, drv, drv.Handle(ctx), strings.ReplaceAll(`
+		var migration = {
+			up(db) {
+				globalThis.count = db.queryOne(sql~SELECT count(*) AS count FROM `+table+`~).count
+			},
+		}
+	`, "~", "`"), "count.js")
+	if err := script.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	return script.runtime.Get("count").Export()
+}
+
+func Test_Script_UseDatabases(t *testing.T) {
+	script, ctx := setupTestScript(t, strings.ReplaceAll(`
+		var migration = {
+			databases: ['analytics'],
+			up(db, databases) {
+				db.exec(sql~CREATE TABLE users (name TEXT)~)
+				db.exec(sql~INSERT INTO users VALUES ('Ada')~)
+				databases.analytics.exec(sql~CREATE TABLE events (name TEXT)~)
+				databases.analytics.exec(sql~INSERT INTO events VALUES ('signup')~)
+			},
+			down(db, databases) {
+				db.exec(sql~INSERT INTO users VALUES ('Grace')~)
+				databases.analytics.exec(sql~INSERT INTO events VALUES ('login')~)
+				throw new Error('down failed')
+			},
+		}
+	`, "~", "`"))
+
+	if databases := script.Databases(); !reflect.DeepEqual(databases, []string{"analytics"}) {
+		t.Errorf("Databases() = %v, want [analytics]", databases)
+	}
+
+	analytics := setupTestDatabase(t, ctx, "analytics")
+	script.UseDatabases(ctx, []*Database{analytics})
+
+	if err := script.WithTransaction(ctx, script.Up); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if count := countRows(t, ctx, script.driver, "users"); count != int64(1) {
+		t.Errorf("users count = %v, want 1", count)
+	}
+	if count := countRows(t, ctx, analytics.Driver, "events"); count != int64(1) {
+		t.Errorf("events count = %v, want 1", count)
+	}
+
+	err := script.WithTransaction(ctx, script.Down)
+	if err == nil || !strings.Contains(err.Error(), "down failed") {
+		t.Fatalf("Down() error = %v, want the script's error", err)
+	}
+	if _, ok := err.(*CommitError); ok {
+		t.Errorf("Down() error = %v, want no CommitError when nothing committed", err)
+	}
+	if count := countRows(t, ctx, script.driver, "users"); count != int64(1) {
+		t.Errorf("users count = %v, want 1 after rolling back", count)
+	}
+	if count := countRows(t, ctx, analytics.Driver, "events"); count != int64(1) {
+		t.Errorf("events count = %v, want 1 after rolling back", count)
+	}
+}
+
+func Test_Script_WithoutDatabases(t *testing.T) {
+	script, ctx := setupTestScript(t, strings.ReplaceAll(`
+		var migration = {
+			up(db, databases) {
+				globalThis.names = Object.keys(databases)
+			},
+		}
+	`, "~", "`"))
+
+	if databases := script.Databases(); databases != nil {
+		t.Errorf("Databases() = %v, want none", databases)
+	}
+	if err := script.WithTransaction(ctx, script.Up); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if names := script.runtime.Get("names").Export(); !reflect.DeepEqual(names, []any{}) {
+		t.Errorf("names = %v, want none", names)
+	}
+}
+
+func Test_CommitError_Error(t *testing.T) {
+	err := &CommitError{Committed: []string{"analytics", "events"}, Err: context.Canceled}
+	expected := "the migration's own database failed to commit after `analytics`, `events` committed, whose changes are kept and must be reverted by hand: context canceled"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+
+	err = &CommitError{Database: "events", Committed: []string{"analytics"}, Err: context.Canceled}
+	expected = "database `events` failed to commit after `analytics` committed, whose changes are kept and must be reverted by hand: context canceled"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func Test_Script_UseDatabases_DifferentKinds(t *testing.T) {
+	script, ctx := setupTestScript(t, strings.ReplaceAll(`
+		var migration = {
+			databases: ['reporting'],
+			up(db, databases) {
+				globalThis.own = sql~SELECT ${1}~.query
+				globalThis.reporting = databases.reporting.sql~SELECT ${1}~.query
+			},
+		}
+	`, "~", "`"))
+
+	reporting, err := driver.FromDatabaseConfig(&config.DatabaseConfig{
+		Kind:          config.DatabaseKindPostgreSQL,
+		ConnectionUrl: "postgres://localhost:5432/reporting",
+	})
+	if err != nil {
+		t.Fatalf("FromDatabaseConfig() error = %v", err)
+	}
+	script.UseDatabases(ctx, []*Database{{Name: "reporting", Driver: reporting}})
+
+	if err := script.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if own := script.runtime.Get("own").Export(); own != "SELECT ?" {
+		t.Errorf("sql = %q, want the SQLite tag", own)
+	}
+	if query := script.runtime.Get("reporting").Export(); query != "SELECT $1" {
+		t.Errorf("databases.reporting.sql = %q, want the PostgreSQL tag", query)
+	}
+}
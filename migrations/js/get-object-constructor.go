@@ -3,6 +3,9 @@ package js
 import "github.com/dop251/goja"
 
 func GetObjectConstructor(jsvm *goja.Runtime, val goja.Value) goja.Value {
+	if val == nil || goja.IsUndefined(val) || goja.IsNull(val) {
+		return nil
+	}
 	obj := val.ToObject(jsvm)
 	if obj == nil {
 		return nil
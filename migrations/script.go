@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	_ "embed"
@@ -21,6 +22,15 @@ import (
 
 const CACHE_PATH = ".graviton/cache"
 
+// dataFileLoaders bundle the data files a migration imports into its compiled
+// source, so the source stored when it is applied is complete. SQL and CSV
+// files are imported as text, and JSON files as the value they hold.
+var dataFileLoaders = map[string]api.Loader{
+	".sql":  api.LoaderText,
+	".csv":  api.LoaderText,
+	".json": api.LoaderJSON,
+}
+
 var dummyJsFn = func(call goja.FunctionCall) goja.Value { return goja.Undefined() }
 var dummyJsCtor = func(call goja.ConstructorCall) *goja.Object { return nil }
 var dummyJsFnWithRuntime = func(call goja.FunctionCall, jsvm *goja.Runtime) goja.Value { return goja.Undefined() }
@@ -33,6 +43,9 @@ type Script struct {
 	src     string
 	origin  string
 	runtime *goja.Runtime
+
+	// databases are the other databases the script uses.
+	databases []*Database
 }
 
 func NewScript(ctx context.Context, driver driver.Driver, handle any, src, origin string) *Script {
@@ -50,6 +63,7 @@ func NewScript(ctx context.Context, driver driver.Driver, handle any, src, origi
 }
 
 type BuildScriptMessage = api.Message
+type BuildScriptLocation = api.Location
 
 type BuildScriptError struct {
 	Errors []BuildScriptMessage
@@ -73,12 +87,18 @@ func CompileScriptFromFile(ctx context.Context, driver driver.Driver, origin, pa
 		Format:      api.FormatIIFE,
 		GlobalName:  "migration",
 		Target:      api.ES2022,
+		Metafile:    true,
+		Loader:      dataFileLoaders,
 	})
 
 	if len(result.Errors) != 0 {
 		return nil, &BuildScriptError{Errors: result.Errors}
 	}
 
+	if err := validateSQLTemplates(ctx, driver, result.Metafile); err != nil {
+		return nil, err
+	}
+
 	script := &Script{
 		ctx:    ctx,
 		driver: driver,
@@ -91,16 +111,49 @@ func CompileScriptFromFile(ctx context.Context, driver driver.Driver, origin, pa
 	return script, nil
 }
 
-func (s *Script) Up() error {
-	_, err := s.runtime.RunString("migration.up(__g__)")
-	return err
+// Up runs the migration's up function. The handle passed to the migration is
+// bound to ctx so its work joins any transaction ctx carries. The handles of
+// the other databases it uses are passed second, bound to their transactions
+// when it runs in WithTransaction.
+func (s *Script) Up(ctx context.Context) error {
+	return s.run(ctx, "migration.up(__g__, __databases__)")
 }
 
-func (s *Script) Down() error {
-	_, err := s.runtime.RunString("migration.down(__g__)")
-	return err
+func (s *Script) Down(ctx context.Context) error {
+	return s.run(ctx, "migration.down(__g__, __databases__)")
+}
+
+func (s *Script) Seed(ctx context.Context) error {
+	return s.run(ctx, "migration.seed(__g__, __databases__)")
+}
+
+func (s *Script) run(ctx context.Context, src string) error {
+	s.ctx = ctx
+	s.handle = s.driver.Handle(ctx)
+	s.runtime.Set("__g__", s.intoJs(reflect.ValueOf(s.handle)))
+	s.runtime.Set("__databases__", s.databaseHandles())
+
+	_, err := s.runtime.RunString(src)
+	return scriptError(err)
 }
 
+// Environments returns the environments a seed is restricted to. An empty
+// list means the seed is not restricted.
+func (s *Script) Environments() []string {
+	environmentsVal, err := s.runtime.RunString("migration.environments")
+	if err != nil || goja.IsUndefined(environmentsVal) || goja.IsNull(environmentsVal) {
+		return nil
+	}
+
+	var environments []string
+	if err := s.runtime.ExportTo(environmentsVal, &environments); err != nil {
+		return nil
+	}
+	return environments
+}
+
+// Evaluate runs the script's source in a new runtime with the globals of its
+// database and of the other databases it uses.
 func (s *Script) Evaluate() {
 	s.runtime = goja.New()
 	s.runtime.Set("console", JSConsole(s.runtime))
@@ -112,10 +165,41 @@ func (s *Script) Evaluate() {
 		s.runtime.Set(name, s.intoJs(reflect.ValueOf(value)))
 	}
 
+	// The globals of the other databases are set on their handles, so that
+	// databases of different kinds can each have their own sql tag. Globals no
+	// other database defines are also set globally.
+	for _, database := range s.databases {
+		database.Driver.Init(s.ctx, s.runtime)
+		database.globals = map[string]goja.Value{}
+		for name, value := range database.Driver.Globals(s.ctx, s.runtime) {
+			database.globals[name] = s.intoJsWith(database.Driver, reflect.ValueOf(value))
+			if s.runtime.Get(name) == nil {
+				s.runtime.Set(name, database.globals[name])
+			}
+		}
+	}
+
 	s.runtime.RunScript(s.origin, s.src)
 }
 
 func (s *Script) intoJs(vr reflect.Value) goja.Value {
+	return s.intoJsWith(s.driver, vr)
+}
+
+// intoJsWith converts a Go value into a JavaScript value, with drv converting
+// the values of its database. The methods of the value convert their arguments
+// and results with drv too.
+func (s *Script) intoJsWith(drv driver.Driver, vr reflect.Value) goja.Value {
+	if !vr.IsValid() {
+		return goja.Null()
+	}
+	switch vr.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		if vr.IsNil() {
+			return goja.Null()
+		}
+	}
+
 	intf := vr.Interface()
 
 	if gojaVal, ok := intf.(goja.Value); ok {
@@ -126,6 +210,19 @@ func (s *Script) intoJs(vr reflect.Value) goja.Value {
 		return gojaObj
 	}
 
+	if jsVal, ok := drv.MaybeIntoJSValue(s.ctx, s.runtime, intf, func(value any) goja.Value {
+		return s.intoJsWith(drv, reflect.ValueOf(value))
+	}); ok {
+		return jsVal
+	}
+
+	switch intf := intf.(type) {
+	case time.Time:
+		return s.newJsObject("Date", intf.UnixMilli())
+	case []byte:
+		return s.newJsObject("Uint8Array", s.runtime.NewArrayBuffer(intf))
+	}
+
 	switch vr.Kind() {
 	case reflect.Bool,
 		reflect.String,
@@ -145,13 +242,13 @@ func (s *Script) intoJs(vr reflect.Value) goja.Value {
 	case reflect.Slice:
 		arr := s.runtime.NewArray()
 		for i := 0; i < vr.Len(); i += 1 {
-			arr.Set(strconv.Itoa(i), s.intoJs(vr.Index(i)))
+			arr.Set(strconv.Itoa(i), s.intoJsWith(drv, vr.Index(i)))
 		}
 		return arr
 	case reflect.Map:
 		obj := s.runtime.NewObject()
 		for _, key := range vr.MapKeys() {
-			obj.Set(key.String(), s.intoJs(vr.MapIndex(key)))
+			obj.Set(fmt.Sprint(key.Interface()), s.intoJsWith(drv, vr.MapIndex(key)))
 		}
 		return obj
 	case reflect.Struct:
@@ -160,14 +257,14 @@ func (s *Script) intoJs(vr reflect.Value) goja.Value {
 			field := vr.Type().Field(i)
 			if unicode.IsUpper(rune(field.Name[0])) {
 				jsName := strings.ToLower(field.Name[0:1]) + field.Name[1:]
-				obj.Set(jsName, s.intoJs(vr.Field(i)))
+				obj.Set(jsName, s.intoJsWith(drv, vr.Field(i)))
 			}
 		}
 		for i := 0; i < vr.NumMethod(); i += 1 {
 			method := vr.Type().Method(i)
 			if unicode.IsUpper(rune(method.Name[0])) {
 				jsName := strings.ToLower(method.Name[0:1]) + method.Name[1:]
-				obj.Set(jsName, s.intoJs(vr.Method(i)))
+				obj.Set(jsName, s.intoJsWith(drv, vr.Method(i)))
 			}
 		}
 		if vr.CanAddr() {
@@ -176,7 +273,7 @@ func (s *Script) intoJs(vr reflect.Value) goja.Value {
 				method := vrAddr.Type().Method(i)
 				if unicode.IsUpper(rune(method.Name[0])) {
 					jsName := strings.ToLower(method.Name[0:1]) + method.Name[1:]
-					obj.Set(jsName, s.intoJs(vrAddr.Method(i)))
+					obj.Set(jsName, s.intoJsWith(drv, vrAddr.Method(i)))
 				}
 			}
 		}
@@ -193,31 +290,28 @@ func (s *Script) intoJs(vr reflect.Value) goja.Value {
 
 		default:
 			return s.runtime.ToValue(func(call goja.FunctionCall) goja.Value {
-				argsVrs := []reflect.Value{}
-				for _, arg := range call.Arguments {
-					argsVrs = append(argsVrs, reflect.ValueOf(s.fromJs(arg)))
-				}
-				rtnVrs := vr.Call(argsVrs)
+				defer s.throwGoError(drv)
+				rtnVrs := vr.Call(s.callArgs(drv, tr, call.Arguments))
 				switch len(rtnVrs) {
 				case 0:
 					return goja.Undefined()
 				case 1:
-					return s.intoJs(rtnVrs[0])
+					return s.intoJsWith(drv, rtnVrs[0])
 				default:
 					arr := s.runtime.NewArray()
 					for i := 0; i < len(rtnVrs); i += 1 {
-						arr.Set(strconv.Itoa(i), s.intoJs(rtnVrs[i]))
+						arr.Set(strconv.Itoa(i), s.intoJsWith(drv, rtnVrs[i]))
 					}
 					return arr
 				}
 			})
 		}
 	case reflect.Ptr, reflect.Interface:
-		return s.intoJs(vr.Elem())
+		return s.intoJsWith(drv, vr.Elem())
 	case reflect.Array:
 		arr := s.runtime.NewArray()
 		for i := 0; i < vr.Len(); i += 1 {
-			arr.Set(strconv.Itoa(i), s.intoJs(vr.Index(i)))
+			arr.Set(strconv.Itoa(i), s.intoJsWith(drv, vr.Index(i)))
 		}
 		return arr
 	default:
@@ -226,28 +320,183 @@ func (s *Script) intoJs(vr reflect.Value) goja.Value {
 	}
 }
 
+// throwGoError rethrows an error panicked by a Go function called from
+// JavaScript, such as a failed statement, as a JavaScript exception so the
+// migration can catch it. The driver converts the errors the database returns,
+// so their codes can be inspected. Exceptions thrown by JavaScript callbacks
+// are rethrown unchanged.
+func (s *Script) throwGoError(drv driver.Driver) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(*goja.Exception); !ok {
+		if err, ok := r.(error); ok {
+			if jsErr, ok := drv.MaybeIntoJSValue(s.ctx, s.runtime, err, func(value any) goja.Value {
+				return s.intoJsWith(drv, reflect.ValueOf(value))
+			}); ok {
+				panic(jsErr)
+			}
+			panic(s.runtime.NewGoError(err))
+		}
+	}
+	panic(r)
+}
+
+// scriptError returns the Go error an uncaught exception was thrown for, so
+// the error a migration fails with is the one the database returned.
+func scriptError(err error) error {
+	if exception, ok := err.(*goja.Exception); ok {
+		if goErr := exception.Unwrap(); goErr != nil {
+			return goErr
+		}
+	}
+	return err
+}
+
+// callArgs converts JavaScript arguments into arguments for a Go function of
+// type fnType. As in JavaScript, missing arguments are allowed and extra
+// arguments are ignored. Missing and null arguments become zero values and
+// numbers are converted to the parameter's numeric type.
+func (s *Script) callArgs(drv driver.Driver, fnType reflect.Type, args []goja.Value) []reflect.Value {
+	numIn := fnType.NumIn()
+	argsVrs := []reflect.Value{}
+	for i := 0; i < len(args) || i < numIn; i += 1 {
+		isVariadicArg := fnType.IsVariadic() && i >= numIn-1
+		if i >= len(args) {
+			if isVariadicArg {
+				break
+			}
+			argsVrs = append(argsVrs, reflect.Zero(fnType.In(i)))
+			continue
+		}
+
+		var paramType reflect.Type
+		switch {
+		case isVariadicArg:
+			paramType = fnType.In(numIn - 1).Elem()
+		case i < numIn:
+			paramType = fnType.In(i)
+		default:
+			return argsVrs
+		}
+
+		if fn, ok := goja.AssertFunction(args[i]); ok && paramType.Kind() == reflect.Func {
+			argsVrs = append(argsVrs, s.goFunc(drv, fn, paramType))
+			continue
+		}
+
+		argsVrs = append(argsVrs, convertArg(s.fromJsWith(drv, args[i]), paramType))
+	}
+	return argsVrs
+}
+
+// goFunc wraps a JavaScript function so it can be passed to a Go function
+// expecting a func of type fnType, such as a forEach callback. An exception
+// thrown by the JavaScript function is rethrown when the Go code calls it.
+func (s *Script) goFunc(drv driver.Driver, fn goja.Callable, fnType reflect.Type) reflect.Value {
+	return reflect.MakeFunc(fnType, func(in []reflect.Value) []reflect.Value {
+		jsArgs := make([]goja.Value, len(in))
+		for i, arg := range in {
+			jsArgs[i] = s.intoJsWith(drv, arg)
+		}
+
+		result, err := fn(goja.Undefined(), jsArgs...)
+		if err != nil {
+			panic(err)
+		}
+
+		out := make([]reflect.Value, fnType.NumOut())
+		for i := range out {
+			if i == 0 {
+				out[i] = convertArg(s.fromJsWith(drv, result), fnType.Out(i))
+			} else {
+				out[i] = reflect.Zero(fnType.Out(i))
+			}
+		}
+		return out
+	})
+}
+
+func convertArg(value any, paramType reflect.Type) reflect.Value {
+	if value == nil {
+		return reflect.Zero(paramType)
+	}
+	vr := reflect.ValueOf(value)
+	if !vr.Type().AssignableTo(paramType) && isNumberKind(vr.Kind()) && isNumberKind(paramType.Kind()) {
+		return vr.Convert(paramType)
+	}
+	return vr
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Script) newJsObject(ctorName string, args ...any) goja.Value {
+	argVals := make([]goja.Value, len(args))
+	for i, arg := range args {
+		argVals[i] = s.runtime.ToValue(arg)
+	}
+	obj, err := s.runtime.New(s.runtime.Get(ctorName), argVals...)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
 func (s *Script) fromJs(val goja.Value) any {
+	return s.fromJsWith(s.driver, val)
+}
+
+// fromJsWith converts a JavaScript value into a Go value for drv's database.
+// Values such as sql tags are converted by the driver that made them, which is
+// drv unless they come from another database of the same kind.
+func (s *Script) fromJsWith(drv driver.Driver, val goja.Value) any {
 	switch {
 	case js.IsObjectFromConstructorWithGlobalName(s.runtime, val, "Array"):
 		arr := val.ToObject(s.runtime)
 		arrLen := int(arr.Get("length").ToInteger())
 		goVal := []any{}
 		for i := 0; i < arrLen; i += 1 {
-			goVal = append(goVal, s.fromJs(arr.Get(strconv.Itoa(i))))
+			goVal = append(goVal, s.fromJsWith(drv, arr.Get(strconv.Itoa(i))))
 		}
 		return goVal
 	case js.IsObjectFromConstructorWithGlobalName(s.runtime, val, "Object"):
 		obj := val.ToObject(s.runtime)
+		if documentDriver, ok := drv.(driver.DocumentDriver); ok {
+			keys := obj.Keys()
+			values := make([]any, len(keys))
+			for i, key := range keys {
+				values[i] = s.fromJsWith(drv, obj.Get(key))
+			}
+			return documentDriver.NewDocument(keys, values)
+		}
 		goVal := map[string]any{}
 		for _, key := range obj.Keys() {
-			goVal[key] = s.fromJs(obj.Get(key))
+			goVal[key] = s.fromJsWith(drv, obj.Get(key))
 		}
 		return goVal
 	default:
-		goVal, ok := s.driver.MaybeFromJSValue(s.ctx, s.runtime, val)
+		goVal, ok := drv.MaybeFromJSValue(s.ctx, s.runtime, val)
 		if ok {
 			return goVal
 		}
+		for _, other := range s.drivers() {
+			if other == drv {
+				continue
+			}
+			if goVal, ok := other.MaybeFromJSValue(s.ctx, s.runtime, val); ok {
+				return goVal
+			}
+		}
 		return val.Export()
 	}
 }
@@ -0,0 +1,114 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/driver"
+)
+
+func setupTestScript(t *testing.T, src string) (*Script, context.Context) {
+	t.Helper()
+
+	drv, err := driver.FromDatabaseConfig(&config.DatabaseConfig{
+		Kind:          config.DatabaseKindSQLite,
+		ConnectionUrl: "file:" + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("FromDatabaseConfig() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := drv.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { drv.Disconnect(ctx) })
+
+	return NewScript(ctx, drv, drv.Handle(ctx), src, "test.js"), ctx
+}
+
+func Test_Script_CatchesDatabaseErrors(t *testing.T) {
+	script, ctx := setupTestScript(t, strings.ReplaceAll(`
+		var migration = {
+			up(db) {
+				db.exec(sql~CREATE TABLE users (name TEXT UNIQUE)~)
+				db.exec(sql~INSERT INTO users VALUES ('Ada')~)
+				try {
+					db.transaction(tx => {
+						tx.exec(sql~INSERT INTO users VALUES ('Grace')~)
+						tx.exec(sql~INSERT INTO users VALUES ('Ada')~)
+					})
+				} catch (e) {
+					db.exec(sql~INSERT INTO users VALUES (${e.name === 'DatabaseError' && e.code === 2067 ? 'caught' : e.message})~)
+				}
+				globalThis.names = db.query(sql~SELECT name FROM users ORDER BY name~).map(row => row.name)
+			},
+			down(db) {
+				db.exec(sql~DROP TABLE missing~)
+			},
+		}
+	`, "~", "`"))
+
+	err := script.driver.WithTransaction(ctx, script.Up)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	names := script.runtime.Get("names").Export()
+	expected := []any{"Ada", "caught"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("names = %v, want %v", names, expected)
+	}
+
+	err = script.driver.WithTransaction(ctx, script.Down)
+	if err == nil || !strings.HasPrefix(err.Error(), "SQL logic error: no such table: missing") {
+		t.Errorf("Down() error = %v, want the database's error", err)
+	}
+}
+
+func Test_CompileScriptFromFile_ImportsDataFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"schema.sql": "CREATE TABLE users (name TEXT);\nCREATE TABLE roles (name TEXT);\n",
+		"users.json": `["Ada", "Grace"]`,
+		"roles.csv":  "name\nadmin\n",
+		"20240101000000-data.migration.ts": strings.Join([]string{
+			`import schema from "./schema.sql"`,
+			`import users from "./users.json"`,
+			`import roles from "./roles.csv"`,
+			`export function up(db: Handle) {`,
+			`  db.execScript(schema)`,
+			"  users.forEach((name: string) => db.exec(sql`INSERT INTO users VALUES (${name})`))",
+			"  roles.trim().split('\\n').slice(1).forEach((name: string) => db.exec(sql`INSERT INTO roles VALUES (${name})`))",
+			"  globalThis.names = db.query(sql`SELECT name FROM users UNION ALL SELECT name FROM roles`).map(row => row.name)",
+			`}`,
+		}, "\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	setup, ctx := setupTestScript(t, "")
+	script, err := CompileScriptFromFile(ctx, setup.driver, "20240101000000-data.migration.ts", filepath.Join(dir, "20240101000000-data.migration.ts"))
+	if err != nil {
+		t.Fatalf("CompileScriptFromFile() error = %v", err)
+	}
+	if !strings.Contains(script.src, "CREATE TABLE roles") {
+		t.Error("compiled source does not include the imported SQL file")
+	}
+
+	if err := setup.driver.WithTransaction(ctx, script.Up); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	names := script.runtime.Get("names").Export()
+	expected := []any{"Ada", "Grace", "admin"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("names = %v, want %v", names, expected)
+	}
+}
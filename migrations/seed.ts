@@ -0,0 +1,7 @@
+// Seeds only run in the environments listed here. Running without --env counts
+// as development.
+export const environments = ['development']
+
+export function seed(db: Handle) {
+  // TODO: Insert seed data here
+}
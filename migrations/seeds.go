@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/driver"
+	migrationsmeta "github.com/telemetryos/graviton/migrations-meta"
+)
+
+// GetSeeds compiles every seed within the database's seeds directory. Seeds
+// that have already been run carry the time they were last applied.
+func GetSeeds(ctx context.Context, projectPath string, conf *config.DatabaseConfig, d driver.Driver) ([]*Migration, error) {
+	appliedSeedsMetadata, err := d.GetAppliedSeedsMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appliedSeeds := make(map[string]*migrationsmeta.MigrationMetadata)
+	for _, appliedSeedMetadata := range appliedSeedsMetadata {
+		appliedSeeds[appliedSeedMetadata.Filename] = appliedSeedMetadata
+	}
+
+	if conf.SeedsPath == "" {
+		conf.SeedsPath = "seeds"
+	}
+
+	seedsPath := filepath.Join(projectPath, conf.SeedsPath)
+	seedsDir, err := os.ReadDir(seedsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var seeds []*Migration
+	for _, seedDir := range seedsDir {
+		seedFilename := seedDir.Name()
+		if !seedDir.Type().IsRegular() || !migrationsmeta.SeedNamePattern.MatchString(seedFilename) {
+			continue
+		}
+
+		seedPath := filepath.Join(seedsPath, seedFilename)
+		script, err := CompileScriptFromFile(ctx, d, seedFilename, seedPath)
+		if err != nil {
+			return nil, err
+		}
+
+		seedMetadata := &migrationsmeta.MigrationMetadata{
+			Filename: seedFilename,
+			Source:   script.src,
+		}
+		if appliedSeed, ok := appliedSeeds[seedFilename]; ok {
+			seedMetadata.AppliedAt = appliedSeed.AppliedAt
+		}
+
+		seeds = append(seeds, &Migration{
+			MigrationMetadata: seedMetadata,
+			Script:            script,
+		})
+	}
+
+	return seeds, nil
+}
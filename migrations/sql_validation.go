@@ -0,0 +1,393 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/telemetryos/graviton/driver"
+)
+
+// sqlStatementKeywords are the words a complete statement can start with.
+// Templates starting with anything else are fragments meant to be nested in
+// another query, and are not checked on their own unless they are passed
+// straight to a handle method.
+var sqlStatementKeywords = map[string]bool{
+	"ALTER": true, "ANALYZE": true, "COMMENT": true, "CREATE": true,
+	"DELETE": true, "DROP": true, "EXPLAIN": true, "GRANT": true,
+	"INSERT": true, "MERGE": true, "PRAGMA": true, "REFRESH": true,
+	"REINDEX": true, "RENAME": true, "REPLACE": true, "REVOKE": true,
+	"SELECT": true, "TRUNCATE": true, "UPDATE": true, "VACUUM": true,
+	"VALUES": true, "WITH": true,
+}
+
+// sqlValueKeywords are the keywords that can only be followed by a value.
+var sqlValueKeywords = map[string]bool{
+	"AND": true, "BETWEEN": true, "ELSE": true, "ILIKE": true, "LIKE": true,
+	"LIMIT": true, "NOT": true, "OFFSET": true, "OR": true, "THEN": true,
+	"WHEN": true,
+}
+
+// sqlTemplateExtensions are the extensions of the inputs that can hold sql
+// templates. Other inputs, such as JSON or text files, are not scanned.
+var sqlTemplateExtensions = map[string]bool{
+	".js": true, ".ts": true, ".mjs": true, ".cjs": true, ".tsx": true,
+}
+
+var (
+	sqlStringLiteral  = `'[^'\\]*'|"[^"\\]*"`
+	sqlIdentCallRegex = regexp.MustCompile(`^sql\.ident\(\s*((?:` + sqlStringLiteral + `)(?:\s*,\s*(?:` + sqlStringLiteral + `))*)\s*,?\s*\)$`)
+	sqlRawCallRegex   = regexp.MustCompile(`^sql\.raw\(\s*(` + sqlStringLiteral + `)\s*\)$`)
+	sqlStringRegex    = regexp.MustCompile(sqlStringLiteral)
+	handleCallRegex   = regexp.MustCompile(`\.(exec|query|queryOne|cursor|forEach)\s*(<[^()]*>)?\s*\(\s*$`)
+)
+
+// sqlTemplate is an sql tagged template found in a migration's source.
+type sqlTemplate struct {
+	line        int
+	column      int
+	lineText    string
+	parts       []string
+	expressions []string
+	// statement is true when the template is passed straight to a handle
+	// method, so it is a complete statement whatever it starts with.
+	statement bool
+}
+
+// validateSQLTemplates checks the syntax of the statements written with the
+// sql tag in each source file of a compiled migration. sql.ident and sql.raw
+// calls with string literal arguments are replaced with the SQL they produce,
+// and other interpolations with placeholders. An interpolation anywhere a
+// value cannot go, such as a table name, may hold names or SQL that are only
+// known when the migration runs, so those statements are left for the
+// database to check when they run.
+//
+// Only script inputs are scanned. Statements that do not parse are printed to
+// stderr as warnings, keeping them out of output such as shell completions, or
+// are returned as a *BuildScriptError when strict_sql_validation is set.
+func validateSQLTemplates(ctx context.Context, d driver.Driver, metafile string) error {
+	validator, ok := d.(driver.SQLValidatingDriver)
+	if !ok {
+		return nil
+	}
+
+	var meta struct {
+		Inputs map[string]json.RawMessage `json:"inputs"`
+	}
+	if err := json.Unmarshal([]byte(metafile), &meta); err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(meta.Inputs))
+	for path := range meta.Inputs {
+		if !strings.Contains(path, "node_modules") && sqlTemplateExtensions[filepath.Ext(path)] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var messages []BuildScriptMessage
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		for _, template := range findSQLTemplates(string(src)) {
+			statement, exact := template.render(validator)
+			if !(template.statement || isSQLStatement(statement)) || !exact {
+				continue
+			}
+			if err := validator.ValidateSQL(ctx, statement); err != nil {
+				messages = append(messages, BuildScriptMessage{
+					Text: "invalid SQL: " + err.Error(),
+					Location: &BuildScriptLocation{
+						File:     path,
+						Line:     template.line,
+						Column:   template.column,
+						LineText: template.lineText,
+					},
+				})
+			}
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	if validator.StrictSQLValidation() {
+		return &BuildScriptError{Errors: messages}
+	}
+	for _, message := range messages {
+		fmt.Fprintf(os.Stderr, "%s:%d:%d - warning:\n%s\n", message.Location.File, message.Location.Line, message.Location.Column, message.Text)
+	}
+	return nil
+}
+
+// render builds the statement the template produces. exact is false when an
+// interpolation was replaced with a placeholder where a value cannot go, so
+// the statement may not parse even though the migration is correct.
+func (t *sqlTemplate) render(validator driver.SQLValidatingDriver) (statement string, exact bool) {
+	var query strings.Builder
+	exact = true
+	paramIndex := 0
+
+	for i, part := range t.parts {
+		query.WriteString(part)
+		if i >= len(t.expressions) {
+			continue
+		}
+
+		expression := strings.TrimSpace(t.expressions[i])
+		if match := sqlIdentCallRegex.FindStringSubmatch(expression); match != nil {
+			var names []string
+			for _, literal := range sqlStringRegex.FindAllString(match[1], -1) {
+				names = append(names, literal[1:len(literal)-1])
+			}
+			query.WriteString(validator.QuoteIdentifier(names...))
+			continue
+		}
+		if match := sqlRawCallRegex.FindStringSubmatch(expression); match != nil {
+			query.WriteString(match[1][1 : len(match[1])-1])
+			continue
+		}
+
+		if !isSQLValuePosition(query.String()) {
+			exact = false
+		}
+		paramIndex += 1
+		query.WriteString(validator.Placeholder(paramIndex))
+	}
+
+	return query.String(), exact
+}
+
+// isSQLValuePosition reports whether an interpolation following the given
+// statement text is somewhere only a value can go: after an operator or one of
+// sqlValueKeywords, or in the parentheses of a VALUES or IN list.
+func isSQLValuePosition(before string) bool {
+	trimmed := strings.TrimRight(before, " \t\r\n")
+	if trimmed == "" {
+		return false
+	}
+
+	switch last := trimmed[len(trimmed)-1]; {
+	case strings.IndexByte("=<>+-*/%|", last) >= 0:
+		return true
+	case last == '(' || last == ',':
+		return isSQLValueList(trimmed)
+	}
+
+	word := trimmed[strings.LastIndexFunc(trimmed, func(r rune) bool { return !unicode.IsLetter(r) })+1:]
+	return sqlValueKeywords[strings.ToUpper(word)]
+}
+
+// isSQLValueList reports whether the innermost open parenthesis in the
+// statement text belongs to a VALUES or IN list, including a later row of a
+// VALUES list.
+func isSQLValueList(before string) bool {
+	open := -1
+	depth := 0
+	for i := len(before) - 1; i >= 0 && open < 0; i-- {
+		switch before[i] {
+		case ')':
+			depth += 1
+		case '(':
+			if depth == 0 {
+				open = i
+			}
+			depth -= 1
+		}
+	}
+	if open < 0 {
+		return false
+	}
+
+	preceding := strings.TrimRight(before[:open], " \t\r\n")
+	if strings.HasSuffix(preceding, ",") {
+		row := strings.TrimRight(preceding[:len(preceding)-1], " \t\r\n")
+		return strings.HasSuffix(row, ")") && isSQLValueList(row[:len(row)-1])
+	}
+	word := preceding[strings.LastIndexFunc(preceding, func(r rune) bool { return !unicode.IsLetter(r) })+1:]
+	switch strings.ToUpper(word) {
+	case "VALUES", "IN":
+		return true
+	}
+	return false
+}
+
+func isSQLStatement(statement string) bool {
+	fields := strings.Fields(strings.TrimLeft(statement, " \t\r\n("))
+	if len(fields) == 0 {
+		return false
+	}
+	return sqlStatementKeywords[strings.ToUpper(fields[0])]
+}
+
+// findSQLTemplates finds the templates tagged with sql in JavaScript or
+// TypeScript source, skipping comments, strings and other templates.
+func findSQLTemplates(src string) []*sqlTemplate {
+	s := &sourceScanner{src: src}
+	var templates []*sqlTemplate
+
+	for s.pos < len(src) {
+		switch c := src[s.pos]; {
+		case strings.HasPrefix(src[s.pos:], "//"), strings.HasPrefix(src[s.pos:], "/*"):
+			s.skipComment()
+		case c == '\'' || c == '"':
+			s.skipString()
+		case c == '`':
+			s.scanTemplate()
+		case isIdentifierChar(c):
+			start := s.pos
+			for s.pos < len(src) && isIdentifierChar(src[s.pos]) {
+				s.pos += 1
+			}
+			if src[start:s.pos] != "sql" || (start > 0 && (isIdentifierChar(src[start-1]) || src[start-1] == '.')) {
+				continue
+			}
+			next := s.pos
+			for next < len(src) && strings.IndexByte(" \t\r\n", src[next]) >= 0 {
+				next += 1
+			}
+			if next < len(src) && src[next] == '`' {
+				template := s.location(start)
+				template.statement = handleCallRegex.MatchString(src[:start])
+				s.pos = next
+				template.parts, template.expressions = s.scanTemplate()
+				templates = append(templates, template)
+			}
+		default:
+			s.pos += 1
+		}
+	}
+
+	return templates
+}
+
+type sourceScanner struct {
+	src string
+	pos int
+}
+
+func (s *sourceScanner) location(offset int) *sqlTemplate {
+	lineStart := strings.LastIndexByte(s.src[:offset], '\n') + 1
+	lineEnd := strings.IndexByte(s.src[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(s.src)
+	} else {
+		lineEnd += offset
+	}
+	return &sqlTemplate{
+		line:     strings.Count(s.src[:offset], "\n") + 1,
+		column:   offset - lineStart,
+		lineText: s.src[lineStart:lineEnd],
+	}
+}
+
+func (s *sourceScanner) skipComment() {
+	if strings.HasPrefix(s.src[s.pos:], "//") {
+		end := strings.IndexByte(s.src[s.pos:], '\n')
+		if end < 0 {
+			s.pos = len(s.src)
+		} else {
+			s.pos += end
+		}
+		return
+	}
+	end := strings.Index(s.src[s.pos+2:], "*/")
+	if end < 0 {
+		s.pos = len(s.src)
+	} else {
+		s.pos += end + 4
+	}
+}
+
+func (s *sourceScanner) skipString() {
+	quote := s.src[s.pos]
+	s.pos += 1
+	for s.pos < len(s.src) && s.src[s.pos] != quote && s.src[s.pos] != '\n' {
+		if s.src[s.pos] == '\\' {
+			s.pos += 1
+		}
+		s.pos += 1
+	}
+	s.pos += 1
+}
+
+// scanTemplate reads a template literal starting at its opening backtick,
+// returning its cooked text between interpolations and the source of each
+// interpolated expression.
+func (s *sourceScanner) scanTemplate() (parts []string, expressions []string) {
+	var part strings.Builder
+	s.pos += 1
+
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == '`':
+			s.pos += 1
+			return append(parts, part.String()), expressions
+		case c == '\\' && s.pos+1 < len(s.src):
+			switch escaped := s.src[s.pos+1]; escaped {
+			case 'n':
+				part.WriteByte('\n')
+			case 't':
+				part.WriteByte('\t')
+			case 'r':
+				part.WriteByte('\r')
+			default:
+				part.WriteByte(escaped)
+			}
+			s.pos += 2
+		case strings.HasPrefix(s.src[s.pos:], "${"):
+			parts = append(parts, part.String())
+			part.Reset()
+			s.pos += 2
+			start := s.pos
+			s.skipExpression()
+			expressions = append(expressions, s.src[start:min(s.pos, len(s.src))])
+			s.pos += 1
+		default:
+			part.WriteByte(c)
+			s.pos += 1
+		}
+	}
+
+	return append(parts, part.String()), expressions
+}
+
+// skipExpression moves to the brace closing an interpolated expression.
+func (s *sourceScanner) skipExpression() {
+	depth := 0
+	for s.pos < len(s.src) {
+		switch c := s.src[s.pos]; {
+		case strings.HasPrefix(s.src[s.pos:], "//"), strings.HasPrefix(s.src[s.pos:], "/*"):
+			s.skipComment()
+		case c == '\'' || c == '"':
+			s.skipString()
+		case c == '`':
+			s.scanTemplate()
+		case c == '{':
+			depth += 1
+			s.pos += 1
+		case c == '}':
+			if depth == 0 {
+				return
+			}
+			depth -= 1
+			s.pos += 1
+		default:
+			s.pos += 1
+		}
+	}
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,158 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/telemetryos/graviton/config"
+	"github.com/telemetryos/graviton/driver"
+)
+
+type testValidator struct{}
+
+func (v *testValidator) ValidateSQL(ctx context.Context, statement string) error { return nil }
+func (v *testValidator) StrictSQLValidation() bool                               { return false }
+func (v *testValidator) Placeholder(index int) string                            { return fmt.Sprintf("$%d", index) }
+func (v *testValidator) QuoteIdentifier(names ...string) string {
+	return `"` + strings.Join(names, `"."`) + `"`
+}
+
+func Test_FindSQLTemplates(t *testing.T) {
+	src := strings.Join([]string{
+		"// sql`DROP TABLE commented`",
+		"/* sql`DROP TABLE commented` */",
+		"const text = \"sql`DROP TABLE quoted`\"",
+		"const other = `sql${'`'}`",
+		"export function up(db: Handle) {",
+		"  db.exec(sql`CREATE TABLE users (id int)`)",
+		"  db.query<User>(sql `SELECT * FROM users WHERE id = ${ids.map(id => `${id}`)[0]} AND name = ${'}'}`)",
+		"  const where = sql`name = ${name}`",
+		"  db.exec(mysql`SELECT 1`)",
+		"}",
+	}, "\n")
+
+	templates := findSQLTemplates(src)
+	if len(templates) != 3 {
+		t.Fatalf("findSQLTemplates() found %d templates, want 3", len(templates))
+	}
+
+	expected := []struct {
+		line        int
+		column      int
+		parts       []string
+		expressions []string
+		statement   bool
+	}{
+		{6, 10, []string{"CREATE TABLE users (id int)"}, nil, true},
+		{7, 17, []string{"SELECT * FROM users WHERE id = ", " AND name = ", ""}, []string{"ids.map(id => `${id}`)[0]", "'}'"}, true},
+		{8, 16, []string{"name = ", ""}, []string{"name"}, false},
+	}
+	for i, want := range expected {
+		got := templates[i]
+		if got.line != want.line || got.column != want.column {
+			t.Errorf("template %d at %d:%d, want %d:%d", i, got.line, got.column, want.line, want.column)
+		}
+		if !reflect.DeepEqual(got.parts, want.parts) || !reflect.DeepEqual(got.expressions, want.expressions) {
+			t.Errorf("template %d = %q %q, want %q %q", i, got.parts, got.expressions, want.parts, want.expressions)
+		}
+		if got.statement != want.statement {
+			t.Errorf("template %d statement = %v, want %v", i, got.statement, want.statement)
+		}
+	}
+}
+
+func Test_SQLTemplate_Render(t *testing.T) {
+	tests := []struct {
+		src       string
+		expected  string
+		exact     bool
+		statement bool
+	}{
+		{
+			"sql`INSERT INTO users (id, name) VALUES (${id}, ${name}), (${2}, ${'b'})`",
+			"INSERT INTO users (id, name) VALUES ($1, $2), ($3, $4)",
+			true, true,
+		},
+		{
+			"sql`UPDATE ${sql.ident('public', 'users')} SET name = ${name} WHERE id IN (${ids}) ORDER BY id ${sql.raw('DESC')}`",
+			`UPDATE "public"."users" SET name = $1 WHERE id IN ($2) ORDER BY id DESC`,
+			true, true,
+		},
+		{
+			"sql`SELECT * FROM users WHERE age > ${age} AND name LIKE ${pattern} LIMIT ${limit}`",
+			"SELECT * FROM users WHERE age > $1 AND name LIKE $2 LIMIT $3",
+			true, true,
+		},
+		{
+			"sql`CREATE TABLE ${table} (id int)`",
+			"CREATE TABLE $1 (id int)",
+			false, true,
+		},
+		{
+			"sql`INSERT INTO users (${columns}) VALUES ${rows}`",
+			"INSERT INTO users ($1) VALUES $2",
+			false, true,
+		},
+		{
+			"sql`status = ${'active'}`",
+			"status = $1",
+			true, false,
+		},
+	}
+
+	for _, test := range tests {
+		templates := findSQLTemplates(test.src)
+		if len(templates) != 1 {
+			t.Fatalf("findSQLTemplates(%q) found %d templates, want 1", test.src, len(templates))
+		}
+		statement, exact := templates[0].render(&testValidator{})
+		if statement != test.expected || exact != test.exact {
+			t.Errorf("render(%q) = %q, %v, want %q, %v", test.src, statement, exact, test.expected, test.exact)
+		}
+		if isSQLStatement(statement) != test.statement {
+			t.Errorf("isSQLStatement(%q) = %v, want %v", statement, !test.statement, test.statement)
+		}
+	}
+}
+
+func Test_ValidateSQLTemplates_ScriptInputs(t *testing.T) {
+	ctx := context.Background()
+	drv, err := driver.FromDatabaseConfig(&config.DatabaseConfig{
+		Kind:                config.DatabaseKindSQLite,
+		ConnectionUrl:       "file:" + filepath.Join(t.TempDir(), "test.db"),
+		StrictSQLValidation: true,
+	})
+	if err != nil {
+		t.Fatalf("FromDatabaseConfig() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	src := "db.exec(sql`CREATE TABL users (id INTEGER)`)\n"
+	inputs := map[string]any{}
+	for _, name := range []string{"migration.ts", "notes.txt", "fixtures.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		inputs[path] = map[string]any{}
+	}
+	metafile, err := json.Marshal(map[string]any{"inputs": inputs})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var buildErr *BuildScriptError
+	if err := validateSQLTemplates(ctx, drv, string(metafile)); !errors.As(err, &buildErr) {
+		t.Fatalf("validateSQLTemplates() error = %v, want *BuildScriptError", err)
+	}
+	if len(buildErr.Errors) != 1 || buildErr.Errors[0].Location.File != filepath.Join(dir, "migration.ts") {
+		t.Errorf("validateSQLTemplates() errors = %+v, want one in migration.ts", buildErr.Errors)
+	}
+}
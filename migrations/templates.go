@@ -6,3 +6,6 @@ import (
 
 //go:embed tsconfig.json
 var TSConfigTemplate []byte
+
+//go:embed seed.ts
+var SeedTemplate []byte
@@ -0,0 +1,129 @@
+package schematypes
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FILENAME is the name of the file `graviton types` writes next to
+// migration.d.ts.
+const FILENAME = "schema.d.ts"
+
+// Schema describes the tables or collections of a database as TypeScript
+// types, declared in Namespace so they cannot clash with the types of
+// migration.d.ts.
+type Schema struct {
+	Namespace string
+	Types     []*Type
+}
+
+// Type is the type of the rows of a table or the documents of a collection.
+type Type struct {
+	// Name is the table or collection the type is for. The type itself is
+	// named after it with TypeName.
+	Name   string
+	Fields []*Field
+}
+
+// Field is a column of a table or a field of a document. Type is written as
+// TypeScript, such as `string | null` or `{ city?: string }`.
+type Field struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Render returns the schema.d.ts for a database.
+func Render(databaseName string, schema *Schema) []byte {
+	var b strings.Builder
+	b.WriteString("// Generated by `graviton types` from database `" + databaseName + "`. Run it again\n")
+	b.WriteString("// after changing the schema rather than editing this file.\n\n")
+	b.WriteString("declare namespace " + schema.Namespace + " {\n")
+
+	used := map[string]bool{}
+	for i, t := range schema.Types {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		name := TypeName(t.Name)
+		for n := 2; used[name]; n++ {
+			name = TypeName(t.Name) + strconv.Itoa(n)
+		}
+		used[name] = true
+
+		b.WriteString("  type " + name + " = {\n")
+		for _, field := range t.Fields {
+			b.WriteString("    " + Property(field.Name, field.Optional) + ": " + field.Type + ";\n")
+		}
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+// TypeName returns the name of the type for a table or collection in Pascal
+// case, so user_accounts becomes UserAccounts.
+func TypeName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	typeName := b.String()
+	if typeName == "" || unicode.IsDigit([]rune(typeName)[0]) {
+		typeName = "_" + typeName
+	}
+	return typeName
+}
+
+// Property returns a property name as it is written in a type, quoted when it
+// is not an identifier.
+func Property(name string, optional bool) string {
+	if !identifierPattern.MatchString(name) {
+		quoted, _ := json.Marshal(name)
+		name = string(quoted)
+	}
+	if optional {
+		name += "?"
+	}
+	return name
+}
+
+// Union returns the union of TypeScript types in the order they are given,
+// leaving out repeats. It is any when no types are given or any is one of
+// them.
+func Union(types ...string) string {
+	var members []string
+	for _, t := range types {
+		if t == "any" {
+			return "any"
+		}
+		if t != "" && !slices.Contains(members, t) {
+			members = append(members, t)
+		}
+	}
+	if len(members) == 0 {
+		return "any"
+	}
+	return strings.Join(members, " | ")
+}
+
+// Nullable adds null to a type.
+func Nullable(t string) string {
+	return Union(t, "null")
+}
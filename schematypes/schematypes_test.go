@@ -0,0 +1,70 @@
+package schematypes
+
+import (
+	"testing"
+)
+
+func Test_Render(t *testing.T) {
+	schema := &Schema{
+		Namespace: "Tables",
+		Types: []*Type{
+			{Name: "user_accounts", Fields: []*Field{
+				{Name: "id", Type: "number"},
+				{Name: "display name", Type: Nullable("string")},
+			}},
+			{Name: "UserAccounts", Fields: []*Field{
+				{Name: "tags", Type: "string[]", Optional: true},
+			}},
+		},
+	}
+
+	want := "// Generated by `graviton types` from database `main`. Run it again\n" +
+		"// after changing the schema rather than editing this file.\n" +
+		"\n" +
+		"declare namespace Tables {\n" +
+		"  type UserAccounts = {\n" +
+		"    id: number;\n" +
+		"    \"display name\": string | null;\n" +
+		"  }\n" +
+		"\n" +
+		"  type UserAccounts2 = {\n" +
+		"    tags?: string[];\n" +
+		"  }\n" +
+		"}\n"
+	if got := string(Render("main", schema)); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_TypeName(t *testing.T) {
+	tests := map[string]string{
+		"users":           "Users",
+		"user_accounts":   "UserAccounts",
+		"audit-log.2024":  "AuditLog2024",
+		"2024_events":     "_2024Events",
+		"userPreferences": "UserPreferences",
+		"--":              "_",
+	}
+	for name, want := range tests {
+		if got := TypeName(name); got != want {
+			t.Errorf("TypeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func Test_Union(t *testing.T) {
+	tests := []struct {
+		types []string
+		want  string
+	}{
+		{[]string{"string", "null", "string"}, "string | null"},
+		{[]string{"number", "any"}, "any"},
+		{[]string{}, "any"},
+		{[]string{"", "Date"}, "Date"},
+	}
+	for _, tt := range tests {
+		if got := Union(tt.types...); got != tt.want {
+			t.Errorf("Union(%q) = %q, want %q", tt.types, got, tt.want)
+		}
+	}
+}